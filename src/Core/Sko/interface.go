@@ -89,6 +89,18 @@ func mkSkoFuncType(
 	return resultingScheme
 }
 
+// Returns a fresh Skolem symbol applied to the given metavariables, to be
+// substituted for x. It is meant for the searches that skolemize the problem
+// beforehand rather than when applying the delta rules.
+func MkSkolemTerm(x AST.Var, metas Lib.List[AST.Meta]) AST.Fun {
+	return AST.MakerFun(
+		AST.MakerNewId(fmt.Sprintf("skolem@%v", x.GetName())),
+		Lib.ListMap(metas, Glob.To[AST.Term]),
+		[]AST.TypeApp{},
+		mkSkoFuncType(metas, x.GetTypeApp()),
+	)
+}
+
 /* If every Skolem symbol is created using this function, then it will generate
  * a fresh symbol for sure. Otherwise, nothing is guaranteed.
  */
@@ -37,6 +37,8 @@
 package Core
 
 import (
+	"hash/fnv"
+
 	"github.com/GoelandProver/Goeland/AST"
 	"github.com/GoelandProver/Goeland/Glob"
 	"github.com/GoelandProver/Goeland/Lib"
@@ -80,6 +82,15 @@ func (fat FormAndTerms) Equals(fat2 FormAndTerms) bool {
 		AST.EqualsWithoutOrder(fat.GetTerms(), fat2.GetTerms())
 }
 
+// Returns a hash of the formula, which is the same for the FormAndTerms that
+// are equal. Only the formula, as printed by ToString, is hashed: the terms are
+// left out, as their order does not matter for Equals.
+func (fat FormAndTerms) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(fat.form.ToString()))
+	return h.Sum64()
+}
+
 func (fat FormAndTerms) ToString() string {
 	return fat.GetForm().ToMappedString(AST.DefaultMapString, Glob.GetTypeProof())
 }
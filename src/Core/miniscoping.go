@@ -0,0 +1,353 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+package Core
+
+import (
+	"reflect"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+/** This file provides the miniscoping of a formula: its quantifiers are pushed
+ * inward, across the connectives, as far as their variables allow it:
+ *
+ *   - ∀x.(A ∧ B) becomes ∀x.A ∧ ∀x.B, and ∃x.(A ∨ B) becomes ∃x.A ∨ ∃x.B,
+ *
+ *   - ∀x.(A ∨ B) becomes ∀x.A ∨ B, and ∃x.(A ∧ B) becomes ∃x.A ∧ B, when x
+ *     does not occur in B,
+ *
+ *   - ∀x.(A ⇒ B) becomes A ⇒ ∀x.B when x does not occur in A, and ∃x.A ⇒ B
+ *     when x does not occur in B (and dually for ∃),
+ *
+ *   - a quantifier whose variable does not occur is dropped.
+ *
+ * The variables of a quantifier copied on several formulas are renamed, so
+ * that no two quantifiers of the result bind the same variable.
+ *
+ * The formulas of the problem are miniscoped when the -miniscope option is set,
+ * and the original ones are kept so that the proofs can refer to them.
+ **/
+
+// The formulas of the problem before and after their miniscoping.
+var miniscopings []Lib.Pair[AST.Form, AST.Form]
+
+// Forgets the formulas miniscoped for the previous problem.
+func ClearMiniscopings() {
+	miniscopings = nil
+}
+
+// Miniscopes a formula of the problem, and remembers the original one.
+func MiniscopeStatement(form AST.Form) AST.Form {
+	miniscoped := Miniscope(form)
+	if !miniscoped.Equals(form) {
+		miniscopings = append(miniscopings, Lib.MkPair(form, miniscoped))
+	}
+	return miniscoped
+}
+
+// Replaces the miniscoped formulas of the problem by the original ones in the
+// conjunction of the axioms and of the negated conjecture. As this conjunction
+// is flattened, a miniscoped formula may span several of its conjuncts.
+func UnminiscopeProblem(form AST.Form) AST.Form {
+	for _, p := range miniscopings {
+		if p.Snd.Equals(form) {
+			return p.Fst
+		}
+	}
+
+	switch nf := form.(type) {
+	case AST.And:
+		forms := nf.FormList.Slice()
+		result := AST.NewFormList()
+		for i := 0; i < len(forms); {
+			original, length := unminiscopeConjuncts(forms[i:])
+			result.Append(original)
+			i += length
+		}
+		return AST.MakerAnd(result)
+	case AST.Not:
+		return AST.MakerNot(UnminiscopeProblem(nf.GetForm()))
+	}
+	return form
+}
+
+// Returns the original formula of the first conjuncts, and their number.
+func unminiscopeConjuncts(forms []AST.Form) (AST.Form, int) {
+	for _, p := range miniscopings {
+		conjuncts := AST.NewFormList(p.Snd).Flatten().Slice()
+		if len(conjuncts) > len(forms) {
+			continue
+		}
+		matches := true
+		for i, f := range conjuncts {
+			matches = matches && f.Equals(forms[i])
+		}
+		if matches {
+			return p.Fst, len(conjuncts)
+		}
+	}
+	return UnminiscopeProblem(forms[0]), 1
+}
+
+func Miniscope(form AST.Form) AST.Form {
+	switch nf := form.(type) {
+	case AST.Not:
+		return AST.MakerNot(Miniscope(nf.GetForm()))
+	case AST.And:
+		return AST.MakerAnd(miniscopeList(nf.FormList))
+	case AST.Or:
+		return AST.MakerOr(miniscopeList(nf.FormList))
+	case AST.Imp:
+		return AST.MakerImp(Miniscope(nf.GetF1()), Miniscope(nf.GetF2()))
+	case AST.Equ:
+		return AST.MakerEqu(Miniscope(nf.GetF1()), Miniscope(nf.GetF2()))
+	case AST.AllType:
+		// The proofs of the miniscoping do not open the type quantifiers.
+		return form
+	case AST.All:
+		return pushQuantifier(nf.GetVarList(), Miniscope(nf.GetForm()), isAll)
+	case AST.Ex:
+		return pushQuantifier(nf.GetVarList(), Miniscope(nf.GetForm()), isEx)
+	}
+	return form
+}
+
+func miniscopeList(forms *AST.FormList) *AST.FormList {
+	result := AST.NewFormList()
+	for _, f := range forms.Slice() {
+		result.Append(Miniscope(f))
+	}
+	return result
+}
+
+const (
+	isAll = iota
+	isEx
+)
+
+// Pushes the quantifiers of the variables inside the (miniscoped) formula, the
+// innermost one first.
+func pushQuantifier(vars []AST.Var, form AST.Form, quantifier int) AST.Form {
+	for i := len(vars) - 1; i >= 0; i-- {
+		form = pushVariable(vars[i], form, quantifier)
+	}
+	return form
+}
+
+func pushVariable(x AST.Var, form AST.Form, quantifier int) AST.Form {
+	if !occurs(x, form) {
+		return form
+	}
+
+	// The connective over which the quantifier distributes, and the one where it
+	// only applies on the formulas where its variable occurs.
+	distributes, splits := isAnd, isOr
+	if quantifier == isEx {
+		distributes, splits = isOr, isAnd
+	}
+
+	switch nf := form.(type) {
+	case AST.And, AST.Or:
+		forms := form.GetChildFormulas()
+		if connective(form) == distributes {
+			result := AST.NewFormList()
+			for _, f := range forms.Slice() {
+				result.Append(pushVariable(x, f, quantifier).RenameVariables())
+			}
+			return makeConnective(distributes, result)
+		}
+
+		result, occurring := AST.NewFormList(), AST.NewFormList()
+		for _, f := range forms.Slice() {
+			if occurs(x, f) {
+				occurring.Append(f)
+			} else {
+				result.Append(f)
+			}
+		}
+		if result.IsEmpty() {
+			break
+		}
+		if occurring.Len() == 1 {
+			result.Append(pushVariable(x, occurring.Get(0), quantifier))
+		} else {
+			result.Append(quantify(x, makeConnective(splits, occurring), quantifier))
+		}
+		return makeConnective(splits, result)
+
+	case AST.Imp:
+		// ∀x.(A ⇒ B) is ∀x.(¬A ∨ B), and ∃x.(A ⇒ B) is ∃x.(¬A ∨ B).
+		dual := isEx
+		if quantifier == isEx {
+			dual = isAll
+		}
+		switch {
+		case !occurs(x, nf.GetF1()):
+			return AST.MakerImp(nf.GetF1(), pushVariable(x, nf.GetF2(), quantifier))
+		case !occurs(x, nf.GetF2()):
+			return AST.MakerImp(pushVariable(x, nf.GetF1(), dual), nf.GetF2())
+		}
+	}
+
+	return quantify(x, form, quantifier)
+}
+
+func quantify(x AST.Var, form AST.Form, quantifier int) AST.Form {
+	switch nf := form.(type) {
+	case AST.All:
+		if quantifier == isAll {
+			return AST.MakerAll(append([]AST.Var{x}, nf.GetVarList()...), nf.GetForm())
+		}
+	case AST.Ex:
+		if quantifier == isEx {
+			return AST.MakerEx(append([]AST.Var{x}, nf.GetVarList()...), nf.GetForm())
+		}
+	}
+
+	if quantifier == isAll {
+		return AST.MakerAll([]AST.Var{x}, form)
+	}
+	return AST.MakerEx([]AST.Var{x}, form)
+}
+
+const (
+	isAnd = iota
+	isOr
+)
+
+func connective(form AST.Form) int {
+	if _, ok := form.(AST.And); ok {
+		return isAnd
+	}
+	return isOr
+}
+
+func makeConnective(conn int, forms *AST.FormList) AST.Form {
+	if forms.Len() == 1 {
+		return forms.Get(0)
+	}
+	if conn == isAnd {
+		return AST.MakerAnd(forms)
+	}
+	return AST.MakerOr(forms)
+}
+
+func occurs(x AST.Var, form AST.Form) bool {
+	_, found := form.ReplaceTermByTerm(Glob.To[AST.Term](x), Glob.To[AST.Term](x))
+	return found
+}
+
+// Whether the formulas are equal up to the names of their bound variables, the
+// grouping of their quantifiers, e.g., ∀x.∀y.A and ∀x y.A, and the nesting of
+// their conjunctions and disjunctions, e.g., (A ∧ B) ∧ C and A ∧ B ∧ C.
+func AlphaEquals(f, g AST.Form) bool {
+	switch f.(type) {
+	case AST.All, AST.Ex:
+		if reflect.TypeOf(f) != reflect.TypeOf(g) {
+			return false
+		}
+		fVars, fBody := quantifiedBlock(f)
+		gVars, gBody := quantifiedBlock(g)
+		return alphaEqualsQuantified(fVars, fBody, gVars, gBody)
+	case AST.Not, AST.And, AST.Or, AST.Imp, AST.Equ:
+		if reflect.TypeOf(f) != reflect.TypeOf(g) {
+			return false
+		}
+		fs, gs := flattenedChildren(f), flattenedChildren(g)
+		if fs.Len() != gs.Len() {
+			return false
+		}
+		for i := range fs.Slice() {
+			if !AlphaEquals(fs.Get(i), gs.Get(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return f.Equals(g)
+}
+
+// The variables of the consecutive quantifiers of the same kind at the top of
+// the formula, and the formula under them.
+func quantifiedBlock(form AST.Form) ([]AST.Var, AST.Form) {
+	vars := []AST.Var{}
+	for {
+		switch nf := form.(type) {
+		case AST.All:
+			vars = append(vars, nf.GetVarList()...)
+			form = nf.GetForm()
+			if _, next := form.(AST.All); next {
+				continue
+			}
+		case AST.Ex:
+			vars = append(vars, nf.GetVarList()...)
+			form = nf.GetForm()
+			if _, next := form.(AST.Ex); next {
+				continue
+			}
+		}
+		return vars, form
+	}
+}
+
+// The children of the formula, where the ones with the same connective, for a
+// conjunction or a disjunction, are replaced by their own children.
+func flattenedChildren(form AST.Form) *AST.FormList {
+	switch form.(type) {
+	case AST.And, AST.Or:
+		result := AST.NewFormList()
+		for _, f := range form.GetChildFormulas().Slice() {
+			if reflect.TypeOf(f) == reflect.TypeOf(form) {
+				result.Append(flattenedChildren(f).Slice()...)
+			} else {
+				result.Append(f)
+			}
+		}
+		return result
+	}
+	return form.GetChildFormulas()
+}
+
+func alphaEqualsQuantified(fVars []AST.Var, f AST.Form, gVars []AST.Var, g AST.Form) bool {
+	if len(fVars) != len(gVars) {
+		return false
+	}
+	for i := range gVars {
+		if !fVars[i].GetTypeApp().Equals(gVars[i].GetTypeApp()) {
+			return false
+		}
+		g, _ = g.ReplaceTermByTerm(Glob.To[AST.Term](gVars[i]), Glob.To[AST.Term](fVars[i]))
+	}
+	return AlphaEquals(f, g)
+}
@@ -89,6 +89,7 @@ const (
 	Type
 	Unknown
 	Include
+	Question
 )
 
 /**********************/
@@ -108,6 +109,8 @@ func (fr FormulaRole) ToString() string {
 		res = "NegatedConjecture"
 	case Include:
 		res = "Include"
+	case Question:
+		res = "Question"
 	case Unknown:
 		res = "Unknown"
 	}
@@ -118,7 +121,7 @@ func (statement Statement) ToString() string {
 	switch statement.GetRole() {
 	case Include:
 		return statement.GetRole().ToString() + " " + statement.GetName()
-	case Axiom, Conjecture:
+	case Axiom, Conjecture, NegatedConjecture, Question:
 		str := statement.role.ToString() + " " + statement.name + " "
 		switch f := statement.form.(type) {
 		case Lib.Some[AST.Form]:
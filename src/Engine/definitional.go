@@ -0,0 +1,280 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the definitional (Tseitin) transformation: a subformula
+* whose size exceeds a threshold is replaced by a fresh predicate applied to its
+* free variables, and defined by an axiom. The definition only states the
+* implication that the polarity of the subformula requires:
+*   - a positive occurrence of F is defined by ! [x1 ... xn] : (d(x1, ..., xn) => F),
+*   - a negative one by ! [x1 ... xn] : (F => d(x1, ..., xn)),
+*   - an occurrence of both polarities (below an equivalence) by an equivalence.
+* Polarities are the ones of the formula to refute, hence reversed in the
+* conjectures. The definitions bear the name of the statement they come from.
+**/
+
+package Engine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+// As for the THF encoding, this prefix cannot be written in a TPTP file.
+const definitionalPrefix = "@tseitin_"
+
+var definitionalCounter = 0
+
+// Forgets the fresh predicates introduced so far, before loading another problem.
+func ResetDefinitions() {
+	definitionalCounter = 0
+}
+
+type polarity int
+
+const (
+	positivePolarity polarity = iota
+	negativePolarity
+	bothPolarities
+)
+
+func (p polarity) flip() polarity {
+	switch p {
+	case positivePolarity:
+		return negativePolarity
+	case negativePolarity:
+		return positivePolarity
+	}
+	return p
+}
+
+// Collects the definitions of the fresh predicates introduced in a statement,
+// and their types when the statement is typed.
+type definitionalNamer struct {
+	threshold    int
+	typed        bool
+	definitions  []Parser.PForm
+	declarations []Lib.Pair[string, Parser.PType]
+}
+
+// Returns the statements defining the fresh predicates that name the large
+// subformulas of the formula of the statement, along with the formula where
+// they are named. Questions, and conjectures whose answers are computed, are
+// left unchanged as their quantifiers are needed to extract the answers.
+func nameSubformulas(
+	con Context,
+	statement Parser.PStatement,
+	form Parser.PForm,
+) ([]Core.Statement, Parser.PForm) {
+	var pol polarity
+	switch statement.Role() {
+	case Parser.Axiom, Parser.NegatedConjecture:
+		pol = positivePolarity
+	case Parser.Conjecture:
+		if Glob.GetAnswers() {
+			return []Core.Statement{}, form
+		}
+		pol = negativePolarity
+	default:
+		return []Core.Statement{}, form
+	}
+
+	namer := definitionalNamer{
+		threshold: Glob.GetDefinitionalSize(),
+		typed:     statement.Language() == Parser.PTFF,
+	}
+	named, _ := namer.nameChildren(form, pol, nil)
+
+	statements := []Core.Statement{}
+	for _, declaration := range namer.declarations {
+		statements = append(statements, Core.MakeTypingStatement(
+			statement.Name(),
+			Core.Type,
+			elaborateParsingType(declaration),
+		))
+	}
+	for _, definition := range namer.definitions {
+		statements = append(statements, Core.MakeFormStatement(
+			statement.Name(),
+			Core.Axiom,
+			elaborateParsingForm(con, definition),
+		))
+	}
+
+	if len(namer.definitions) > 0 {
+		Glob.PrintDebug(elab_label, Lib.MkLazy(func() string {
+			return fmt.Sprintf("%d subformulas of statement %s named, which becomes %s",
+				len(namer.definitions), statement.Name(), named.ToString())
+		}))
+	}
+
+	return statements, named
+}
+
+// Names f if its size, once its own subformulas are named, exceeds the
+// threshold. Returns the resulting formula and its size.
+func (n *definitionalNamer) name(
+	f Parser.PForm,
+	pol polarity,
+	vars []Lib.Pair[string, Parser.PAtomicType],
+) (Parser.PForm, int) {
+	named, size := n.nameChildren(f, pol, vars)
+	if size <= n.threshold {
+		return named, size
+	}
+
+	switch definition := n.define(named, pol, vars).(type) {
+	case Lib.Some[Parser.PForm]:
+		return definition.Val, 1
+	}
+	return named, size
+}
+
+// The variables are the ones bound above f, from the outermost one.
+func (n *definitionalNamer) nameChildren(
+	f Parser.PForm,
+	pol polarity,
+	vars []Lib.Pair[string, Parser.PAtomicType],
+) (Parser.PForm, int) {
+	switch form := f.(type) {
+	case Parser.PUnary:
+		sub, size := n.name(form.PForm, pol.flip(), vars)
+		return Parser.MkPNeg(sub), size + 1
+
+	case Parser.PBin:
+		leftPol, rightPol := pol, pol
+		switch form.Operator() {
+		case Parser.PBinaryImp:
+			leftPol = pol.flip()
+		case Parser.PBinaryEqu:
+			leftPol, rightPol = bothPolarities, bothPolarities
+		}
+
+		left, leftSize := n.name(form.Left(), leftPol, vars)
+		right, rightSize := n.name(form.Right(), rightPol, vars)
+		size := leftSize + rightSize + 1
+
+		switch form.Operator() {
+		case Parser.PBinaryOr:
+			return Parser.MkPOr(left, right), size
+		case Parser.PBinaryAnd:
+			return Parser.MkPAnd(left, right), size
+		case Parser.PBinaryImp:
+			return Parser.MkPImp(left, right), size
+		case Parser.PBinaryEqu:
+			return Parser.MkPEqu(left, right), size
+		}
+
+	case Parser.PQuant:
+		innerVars := append(slices.Clone(vars), form.Vars()...)
+		sub, size := n.name(form.PForm, pol, innerVars)
+		switch form.PQuantifier {
+		case Parser.PQuantAll:
+			return Parser.MkPAll(form.Vars(), sub), size + 1
+		case Parser.PQuantEx:
+			return Parser.MkPEx(form.Vars(), sub), size + 1
+		}
+	}
+
+	return f, 1
+}
+
+// Introduces a fresh predicate for f, applied to its free variables. Nothing is
+// introduced under a type quantifier, as the predicate would be polymorphic.
+func (n *definitionalNamer) define(
+	f Parser.PForm,
+	pol polarity,
+	vars []Lib.Pair[string, Parser.PAtomicType],
+) Lib.Option[Parser.PForm] {
+	for _, v := range vars {
+		if isTType(v.Snd.(Parser.PType)) {
+			return Lib.MkNone[Parser.PForm]()
+		}
+	}
+
+	freeVars := []Lib.Pair[string, Parser.PAtomicType]{}
+	args := []Parser.PTerm{}
+	for _, name := range Parser.FreeVariables(f) {
+		// The innermost binder of a variable gives its type.
+		ty := Parser.MkDefPAtomicType()
+		for i := len(vars) - 1; i >= 0; i-- {
+			if vars[i].Fst == name {
+				ty = vars[i].Snd
+				break
+			}
+		}
+		freeVars = append(freeVars, Lib.MkPair(name, ty))
+		args = append(args, Parser.MkPVar(name))
+	}
+
+	symbol := fmt.Sprintf("%s%d", definitionalPrefix, definitionalCounter)
+	definitionalCounter += 1
+	head := Parser.MkPPred(symbol, args)
+
+	var definition Parser.PForm
+	switch pol {
+	case positivePolarity:
+		definition = Parser.MkPImp(head, f)
+	case negativePolarity:
+		definition = Parser.MkPImp(f, head)
+	case bothPolarities:
+		definition = Parser.MkPEqu(head, f)
+	}
+	if len(freeVars) > 0 {
+		definition = Parser.MkPAll(freeVars, definition)
+	}
+	n.definitions = append(n.definitions, definition)
+
+	if n.typed {
+		n.declarations = append(n.declarations, Lib.MkPair(symbol, predicateType(freeVars)))
+	}
+
+	return Lib.MkSome(head)
+}
+
+// The type of a predicate over the given variables.
+func predicateType(vars []Lib.Pair[string, Parser.PAtomicType]) Parser.PType {
+	if len(vars) == 0 {
+		return boolType
+	}
+
+	args := vars[0].Snd.(Parser.PType)
+	for _, v := range vars[1:] {
+		args = Parser.MkTypeProd(args, v.Snd.(Parser.PType))
+	}
+	return Parser.MkTypeMap(args, boolType)
+}
@@ -0,0 +1,353 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file encodes THF (TH0) statements into first-order ones, using the
+* applicative encoding:
+*   - an application e @ e' becomes the term @(e, e'),
+*   - a term e of type $o used as a formula becomes the atom @holds(e),
+*   - a lambda-abstraction is lifted to a fresh symbol, defined by an axiom,
+*   - a formula used as a term is named by a fresh symbol, defined by an axiom.
+* Types are erased, as it is done for FOF problems.
+**/
+
+package Engine
+
+import (
+	"fmt"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+	"slices"
+)
+
+// The symbols of the encoding cannot be written in a TPTP file, so they never
+// clash with the symbols of the problem.
+const (
+	hoAppSymbol    = "@"
+	hoHoldsSymbol  = "@holds"
+	hoLambdaPrefix = "@lambda_"
+	hoDefPrefix    = "@def_"
+)
+
+var boolType = Parser.MkTypeConst("$o").(Parser.PType)
+
+// Types of the THF constants declared so far, shared by all the files of a problem.
+var hoSignature = map[string]Parser.PType{}
+var hoFreshCounter = 0
+
+// Forgets the THF declarations, before loading another problem.
+func ResetHOSignature() {
+	hoSignature = map[string]Parser.PType{}
+	hoFreshCounter = 0
+}
+
+// Collects the definitions of the symbols introduced while encoding a formula.
+type hoEncoder struct {
+	definitions []Parser.PForm
+}
+
+func elaborateHOStatement(statement Parser.PStatement) []Core.Statement {
+	switch f := statement.Form().(type) {
+	case Lib.Some[Parser.PForm]:
+		encoder := hoEncoder{}
+		form := encoder.encodeForm(Context{}, f.Val)
+		statements := []Core.Statement{}
+		for i, definition := range encoder.definitions {
+			statements = append(statements, Core.MakeFormStatement(
+				fmt.Sprintf("%s_def_%d", statement.Name(), i),
+				Core.Axiom,
+				elaborateParsingForm(Context{}, definition),
+			))
+		}
+
+		Glob.PrintDebug(elab_label, Lib.MkLazy(func() string {
+			return fmt.Sprintf("THF statement %s encoded as %s", statement.Name(), form.ToString())
+		}))
+
+		return append(statements, Core.MakeFormStatement(
+			statement.Name(),
+			elaborateRole(statement.Role(), statement),
+			elaborateParsingForm(Context{}, form),
+		))
+
+	case Lib.None[Parser.PForm]:
+		switch ty := statement.TypedConst().(type) {
+		case Lib.Some[Lib.Pair[string, Parser.PType]]:
+			// Types are erased by the encoding: they are only used to know which
+			// terms are formulas.
+			hoSignature[ty.Val.Fst] = ty.Val.Snd
+			return []Core.Statement{}
+		}
+	}
+
+	Glob.Anomaly(elab_label, fmt.Sprintf(
+		"THF statement %s is neither a formula nor a type", statement.ToString()))
+	return []Core.Statement{}
+}
+
+func (enc *hoEncoder) encodeForm(con Context, e Parser.PForm) Parser.PForm {
+	switch f := e.(type) {
+	case Parser.PConst:
+		return f
+
+	case Parser.PUnary:
+		return Parser.MkPNeg(enc.encodeForm(con, f.PForm))
+
+	case Parser.PBin:
+		left, right := enc.encodeForm(con, f.Left()), enc.encodeForm(con, f.Right())
+		switch f.Operator() {
+		case Parser.PBinaryOr:
+			return Parser.MkPOr(left, right)
+		case Parser.PBinaryAnd:
+			return Parser.MkPAnd(left, right)
+		case Parser.PBinaryImp:
+			return Parser.MkPImp(left, right)
+		case Parser.PBinaryEqu:
+			return Parser.MkPEqu(left, right)
+		}
+
+	case Parser.PHOQuant:
+		newCon, vars := hoBindVars(con, f.Vars())
+		switch f.PHOBinder {
+		case Parser.PHOAll:
+			return Parser.MkPAll(vars, enc.encodeForm(newCon, f.PForm))
+		case Parser.PHOEx:
+			return Parser.MkPEx(vars, enc.encodeForm(newCon, f.PForm))
+		}
+
+	case Parser.PApp:
+		if left, right, ok := hoEquality(f); ok {
+			if isBoolType(hoTypeOf(con, left)) || isBoolType(hoTypeOf(con, right)) {
+				return Parser.MkPEqu(enc.encodeForm(con, left), enc.encodeForm(con, right))
+			}
+			return Parser.MkPPred(
+				Parser.PEqSymbol,
+				[]Parser.PTerm{enc.encodeTerm(con, left), enc.encodeTerm(con, right)},
+			)
+		}
+	}
+
+	return Parser.MkPPred(hoHoldsSymbol, []Parser.PTerm{enc.encodeTerm(con, e)})
+}
+
+func (enc *hoEncoder) encodeTerm(con Context, e Parser.PForm) Parser.PTerm {
+	switch f := e.(type) {
+	case Parser.PVar:
+		return Parser.MkPVar(f.Name())
+
+	case Parser.PPred:
+		if len(f.Args()) == 0 && f.Symbol() != Parser.PEqSymbol {
+			return Parser.MkFunConst(f.Symbol())
+		}
+
+	case Parser.PApp:
+		if _, _, ok := hoEquality(f); !ok {
+			return Parser.MkPFun(
+				hoAppSymbol,
+				[]Parser.PTerm{enc.encodeTerm(con, f.Fun()), enc.encodeTerm(con, f.Arg())},
+			)
+		}
+
+	case Parser.PHOQuant:
+		if f.PHOBinder == Parser.PHOLambda {
+			return enc.liftLambda(con, f)
+		}
+	}
+
+	return enc.nameFormula(con, e)
+}
+
+// ^[x1 ... xn]: e becomes @lambda_k(y1, ..., ym), where the yi are the free
+// variables of the abstraction, defined by
+//
+//	! [y1 ... ym, x1 ... xn] : @(...@(@lambda_k(y1, ..., ym), x1)..., xn) = e
+func (enc *hoEncoder) liftLambda(con Context, lambda Parser.PHOQuant) Parser.PTerm {
+	freeVars, head := enc.freshSymbol(con, lambda, hoLambdaPrefix)
+	newCon, vars := hoBindVars(con, lambda.Vars())
+
+	app := head
+	for _, v := range vars {
+		app = Parser.MkPFun(hoAppSymbol, []Parser.PTerm{app, Parser.MkPVar(v.Fst)})
+	}
+
+	var definition Parser.PForm
+	if isBoolType(hoTypeOf(newCon, lambda.PForm)) {
+		definition = Parser.MkPEqu(
+			Parser.MkPPred(hoHoldsSymbol, []Parser.PTerm{app}),
+			enc.encodeForm(newCon, lambda.PForm),
+		)
+	} else {
+		definition = Parser.MkPPred(
+			Parser.PEqSymbol,
+			[]Parser.PTerm{app, enc.encodeTerm(newCon, lambda.PForm)},
+		)
+	}
+
+	enc.define(append(freeVars, vars...), definition)
+	return head
+}
+
+// A formula F used as a term becomes @def_k(y1, ..., ym), where the yi are the
+// free variables of F, defined by
+//
+//	! [y1 ... ym] : @holds(@def_k(y1, ..., ym)) <=> F
+func (enc *hoEncoder) nameFormula(con Context, e Parser.PForm) Parser.PTerm {
+	freeVars, head := enc.freshSymbol(con, e, hoDefPrefix)
+	enc.define(freeVars, Parser.MkPEqu(
+		Parser.MkPPred(hoHoldsSymbol, []Parser.PTerm{head}),
+		enc.encodeForm(con, e),
+	))
+	return head
+}
+
+func (enc *hoEncoder) freshSymbol(
+	con Context,
+	e Parser.PForm,
+	prefix string,
+) ([]Lib.Pair[string, Parser.PAtomicType], Parser.PTerm) {
+	vars := []Lib.Pair[string, Parser.PAtomicType]{}
+	args := []Parser.PTerm{}
+	for _, name := range hoFreeVariables(con, e, []string{}, []string{}) {
+		vars = append(vars, Lib.MkPair(name, Parser.MkDefPAtomicType()))
+		args = append(args, Parser.MkPVar(name))
+	}
+
+	symbol := fmt.Sprintf("%s%d", prefix, hoFreshCounter)
+	hoFreshCounter += 1
+	return vars, Parser.MkPFun(symbol, args)
+}
+
+func (enc *hoEncoder) define(vars []Lib.Pair[string, Parser.PAtomicType], definition Parser.PForm) {
+	if len(vars) > 0 {
+		definition = Parser.MkPAll(vars, definition)
+	}
+	enc.definitions = append(enc.definitions, definition)
+}
+
+// Adds the variables to the context and returns their first-order (erased) version.
+func hoBindVars(
+	con Context,
+	vars []Lib.Pair[string, Parser.PType],
+) (Context, []Lib.Pair[string, Parser.PAtomicType]) {
+	newCon := append(Context{}, vars...)
+	newCon = append(newCon, con...)
+	erased := []Lib.Pair[string, Parser.PAtomicType]{}
+	for _, v := range vars {
+		erased = append(erased, Lib.MkPair(v.Fst, Parser.MkDefPAtomicType()))
+	}
+	return newCon, erased
+}
+
+// Recognizes (=) @ left @ right.
+func hoEquality(app Parser.PApp) (Parser.PForm, Parser.PForm, bool) {
+	if inner, ok := app.Fun().(Parser.PApp); ok {
+		if eq, ok := inner.Fun().(Parser.PPred); ok &&
+			eq.Symbol() == Parser.PEqSymbol && len(eq.Args()) == 0 {
+			return inner.Arg(), app.Arg(), true
+		}
+	}
+	return nil, nil, false
+}
+
+// Approximates the type of e: unknown symbols are considered to be of type $i.
+func hoTypeOf(con Context, e Parser.PForm) Parser.PType {
+	switch f := e.(type) {
+	case Parser.PVar:
+		switch ty := lookupInContext(con, f.Name()).(type) {
+		case Lib.Some[Parser.PType]:
+			return ty.Val
+		}
+		return defaultType
+
+	case Parser.PPred:
+		if ty, ok := hoSignature[f.Symbol()]; ok {
+			return ty
+		}
+		return defaultType
+
+	case Parser.PApp:
+		if _, _, ok := hoEquality(f); ok {
+			return boolType
+		}
+		if ty, ok := hoTypeOf(con, f.Fun()).(Parser.PTypeBin); ok && ty.Operator() == Parser.PTypeMap {
+			return ty.Right()
+		}
+		return defaultType
+
+	case Parser.PHOQuant:
+		if f.PHOBinder == Parser.PHOLambda {
+			newCon, _ := hoBindVars(con, f.Vars())
+			ty := hoTypeOf(newCon, f.PForm)
+			for i := len(f.Vars()) - 1; i >= 0; i-- {
+				ty = Parser.MkTypeMap(f.Vars()[i].Snd, ty)
+			}
+			return ty
+		}
+	}
+	return boolType
+}
+
+func isBoolType(ty Parser.PType) bool {
+	switch t := ty.(type) {
+	case Parser.PTypeFun:
+		return t.Symbol() == "$o"
+	}
+	return false
+}
+
+// Returns the variables of the context that are free in e, in order of first occurrence.
+func hoFreeVariables(con Context, e Parser.PForm, bound, acc []string) []string {
+	switch f := e.(type) {
+	case Parser.PVar:
+		_, inContext := lookupInContext(con, f.Name()).(Lib.Some[Parser.PType])
+		if inContext && !slices.Contains(bound, f.Name()) && !slices.Contains(acc, f.Name()) {
+			acc = append(acc, f.Name())
+		}
+	case Parser.PUnary:
+		acc = hoFreeVariables(con, f.PForm, bound, acc)
+	case Parser.PBin:
+		acc = hoFreeVariables(con, f.Left(), bound, acc)
+		acc = hoFreeVariables(con, f.Right(), bound, acc)
+	case Parser.PApp:
+		acc = hoFreeVariables(con, f.Fun(), bound, acc)
+		acc = hoFreeVariables(con, f.Arg(), bound, acc)
+	case Parser.PHOQuant:
+		newBound := append([]string{}, bound...)
+		for _, v := range f.Vars() {
+			newBound = append(newBound, v.Fst)
+		}
+		acc = hoFreeVariables(con, f.PForm, newBound, acc)
+	}
+	return acc
+}
@@ -54,8 +54,12 @@ func ToInternalSyntax(parser_statements []Parser.PStatement) []Core.Statement {
 	statements := []Core.Statement{}
 	con := Context{}
 	for _, statement := range parser_statements {
-		newCon, stmt := elaborateParsingStatement(con, statement)
-		statements = append(statements, stmt)
+		if statement.Language() == Parser.PTHF {
+			statements = append(statements, elaborateHOStatement(statement)...)
+			continue
+		}
+		newCon, stmts := elaborateParsingStatement(con, statement)
+		statements = append(statements, stmts...)
 		con = newCon
 	}
 	return statements
@@ -64,16 +68,24 @@ func ToInternalSyntax(parser_statements []Parser.PStatement) []Core.Statement {
 func elaborateParsingStatement(
 	con Context,
 	statement Parser.PStatement,
-) (Context, Core.Statement) {
+) (Context, []Core.Statement) {
 	statement_role := elaborateRole(statement.Role(), statement)
 	var core_statement Core.Statement
+	definitions := []Core.Statement{}
 	switch f := statement.Form().(type) {
 
 	case Lib.Some[Parser.PForm]:
+		form := f.Val
+		if statement.IsClause() {
+			form = universalClosure(form)
+		}
+		if Glob.GetDefinitionalSize() > 0 {
+			definitions, form = nameSubformulas(con, statement, form)
+		}
 		core_statement = Core.MakeFormStatement(
 			statement.Name(),
 			statement_role,
-			elaborateParsingForm(con, f.Val),
+			elaborateParsingForm(con, form),
 		)
 
 	case Lib.None[Parser.PForm]:
@@ -96,7 +108,7 @@ func elaborateParsingStatement(
 			}
 		}
 	}
-	return con, core_statement
+	return con, append(definitions, core_statement)
 }
 
 func elaborateRole(parsing_role Parser.PFormulaRole, stmt Parser.PStatement) Core.FormulaRole {
@@ -107,6 +119,8 @@ func elaborateRole(parsing_role Parser.PFormulaRole, stmt Parser.PStatement) Cor
 		return Core.Conjecture
 	case Parser.NegatedConjecture:
 		return Core.NegatedConjecture
+	case Parser.Question:
+		return Core.Question
 	case Parser.Type:
 		return Core.Type
 	case Parser.Unknown:
@@ -123,6 +137,19 @@ func elaborateRole(parsing_role Parser.PFormulaRole, stmt Parser.PStatement) Cor
 	return Core.Unknown
 }
 
+// Clauses (cnf records) have implicitly universally quantified variables.
+func universalClosure(f Parser.PForm) Parser.PForm {
+	vars := []Lib.Pair[string, Parser.PAtomicType]{}
+	for _, name := range Parser.FreeVariables(f) {
+		vars = append(vars, Lib.MkPair(name, Parser.MkDefPAtomicType()))
+	}
+
+	if len(vars) == 0 {
+		return f
+	}
+	return Parser.MkPAll(vars, f)
+}
+
 func elaborateParsingForm(con Context, f Parser.PForm) AST.Form {
 	return elaborateForm(con, f, f)
 }
@@ -477,3 +504,9 @@ func flattenProd(ty AST.TypeApp) []AST.TypeApp {
 	}
 	return []AST.TypeApp{ty}
 }
+
+// Elaborates a closed formula that is not part of a problem, e.g., a formula of
+// a proof to check.
+func ElaborateForm(f Parser.PForm) AST.Form {
+	return elaborateParsingForm(Context{}, f)
+}
@@ -36,7 +36,29 @@ import (
 	"os"
 )
 
-func exitWithError(panicMsg string) {
+// Error raised by Fatal and Anomaly instead of exiting when the errors are
+// recoverable (see SetRecoverableErrors).
+type FatalError struct {
+	Label string
+	Msg   string
+}
+
+func (e FatalError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Label, e.Msg)
+}
+
+var recoverableErrors = false
+
+// When Goéland is used as a library, the errors panic with a FatalError that
+// the caller recovers, instead of exiting the program.
+func SetRecoverableErrors(b bool) {
+	recoverableErrors = b
+}
+
+func exitWithError(label, msg, panicMsg string) {
+	if recoverableErrors {
+		panic(FatalError{label, msg})
+	}
 	if GetDebug() {
 		panic(panicMsg)
 	}
@@ -46,10 +68,10 @@ func exitWithError(panicMsg string) {
 func Anomaly(label, msg string) {
 	url := "https://github.com/GoelandProver/Goeland/issues"
 	PrintError("Anomaly", fmt.Sprintf("In %s: %s.\nPlease report at %s", label, msg, url))
-	exitWithError("Anomaly encountered.")
+	exitWithError(label, msg, "Anomaly encountered.")
 }
 
 func Fatal(label, msg string) {
 	PrintError(label, msg)
-	exitWithError("Fatal error encountered.")
+	exitWithError(label, msg, "Fatal error encountered.")
 }
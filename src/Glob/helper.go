@@ -59,6 +59,7 @@ var start time.Time
 var nb_step = 1
 var exchanges = false
 var proof = false
+var printResults = true
 var prettyPrint = false
 var data_struct = "trees"
 var limit = -1
@@ -70,7 +71,23 @@ var lock_cpt_node sync.Mutex
 var dmt_before_eq bool
 var problem_name string
 var core_limit = -1
+var timeout = -1
+var maxGoroutines = -1
+var maxMemory = -1
+var reintroduction = "uniform"
+var selection = "first"
+var schedule = ""
+var scheduleParallel = false
+var batch = ""
+var batchFormat = "csv"
+var checkSCTPTP = ""
+var minimizeProof = false
+var coreAxioms = false
+var sineTolerance = 0.0
+var sineDepth = 0
+var premisesDropped = false
 var completeness = false
+var answers = false
 var isTypeProof = false
 var arithModule = false
 var innerSkolem = false
@@ -79,6 +96,10 @@ var commit string
 var version = fmt.Sprintf("1.2-dev.r%s", commit)
 var printVersion = false
 var allowFlattening = false
+var definitionalSize = 0
+var miniscope = false
+var simplify = false
+var regularity = false
 var type_check = true
 
 var IncrEq = false
@@ -150,6 +171,8 @@ func GetStart() time.Time {
 }
 
 func GetNbGoroutines() int {
+	mutex.Lock()
+	defer mutex.Unlock()
 	return nb_gor
 }
 
@@ -173,6 +196,12 @@ func IsOneStep() bool {
 	return one_step
 }
 
+// Tells whether the statuses, proofs and models are printed, which is not the
+// case when Goéland is used as a library.
+func GetPrintResults() bool {
+	return printResults
+}
+
 func GetProof() bool {
 	return proof
 }
@@ -187,14 +216,16 @@ func IsLoaded(s string) bool {
 }
 
 func GetCptNode() int {
+	lock_cpt_node.Lock()
+	defer lock_cpt_node.Unlock()
 	return cpt_node
 }
 
 func IncrCptNode() int {
 	lock_cpt_node.Lock()
+	defer lock_cpt_node.Unlock()
 	cpt_node++
-	lock_cpt_node.Unlock()
-	return GetCptNode()
+	return cpt_node
 }
 
 func GetDMTBeforeEq() bool {
@@ -229,10 +260,64 @@ func GetCoreLimit() int {
 	return core_limit
 }
 
+func GetTimeout() int {
+	return timeout
+}
+
+// The number of running goroutines above which the search stops, -1 if there
+// is no limit.
+func GetMaxGoroutines() int {
+	return maxGoroutines
+}
+
+// The memory in use (in MiB) above which the search stops, -1 if there is no
+// limit.
+func GetMaxMemory() int {
+	return maxMemory
+}
+
+func GetSchedule() string {
+	return schedule
+}
+
+func IsScheduleParallel() bool {
+	return scheduleParallel
+}
+
+func GetBatch() string {
+	return batch
+}
+
+func GetBatchFormat() string {
+	return batchFormat
+}
+
+func GetCheckSCTPTP() string {
+	return checkSCTPTP
+}
+
+func GetMinimizeProof() bool {
+	return minimizeProof
+}
+
+func GetCoreAxioms() bool {
+	return coreAxioms
+}
+
+// The tolerance and the depth of the SInE premise selection, which is
+// disabled when the tolerance is not positive. A depth of 0 is unlimited.
+func GetSine() (float64, int) {
+	return sineTolerance, sineDepth
+}
+
 func GetCompleteness() bool {
 	return completeness
 }
 
+func GetAnswers() bool {
+	return answers
+}
+
 func GetTypeProof() bool {
 	return isTypeProof
 }
@@ -277,6 +362,12 @@ func Flatten() bool {
 	return allowFlattening
 }
 
+// The size above which the subformulas are named by fresh predicates, which
+// they are not if it is 0.
+func GetDefinitionalSize() int {
+	return definitionalSize
+}
+
 func NoTypeCheck() bool {
 	return !type_check
 }
@@ -330,6 +421,10 @@ func SetOneStep(b bool) {
 	one_step = b
 }
 
+func SetPrintResults(b bool) {
+	printResults = b
+}
+
 func SetProof(b bool) {
 	proof = b
 }
@@ -372,10 +467,85 @@ func SetCoreLimit(i int) {
 	core_limit = i
 }
 
+func SetTimeout(i int) {
+	timeout = i
+}
+
+func SetMaxGoroutines(i int) {
+	maxGoroutines = i
+}
+
+func SetMaxMemory(i int) {
+	maxMemory = i
+}
+
+func GetReintroduction() string {
+	return reintroduction
+}
+
+func SetReintroduction(policy string) {
+	reintroduction = policy
+}
+
+func GetSelection() string {
+	return selection
+}
+
+func SetSelection(selector string) {
+	selection = selector
+}
+
+func SetSchedule(file string) {
+	schedule = file
+}
+
+func SetScheduleParallel(b bool) {
+	scheduleParallel = b
+}
+
+func SetBatch(problems string) {
+	batch = problems
+}
+
+func SetBatchFormat(format string) {
+	batchFormat = format
+}
+
+func SetCheckSCTPTP(file string) {
+	checkSCTPTP = file
+}
+
+func SetMinimizeProof(b bool) {
+	minimizeProof = b
+}
+
+func SetCoreAxioms(b bool) {
+	coreAxioms = b
+}
+
+func SetSine(tolerance float64, depth int) {
+	sineTolerance = tolerance
+	sineDepth = depth
+}
+
+// Whether the premise selection has dropped some axioms of the problem, in
+// which case the problem cannot be found satisfiable.
+func ArePremisesDropped() bool {
+	return premisesDropped
+}
+
+func SetPremisesDropped(b bool) {
+	premisesDropped = b
+}
+
 func SetCompleteness(b bool) {
 	completeness = b
 }
 
+func SetAnswers(b bool) {
+	answers = b
+}
+
 func SetTypeProof(b bool) {
 	isTypeProof = b
 }
@@ -412,6 +582,38 @@ func SetFlatteningAllowed() {
 	allowFlattening = true
 }
 
+func SetDefinitionalSize(size int) {
+	definitionalSize = size
+}
+
+func GetMiniscope() bool {
+	return miniscope
+}
+
+func SetMiniscope(b bool) {
+	miniscope = b
+}
+
+func GetSimplify() bool {
+	return simplify
+}
+
+func SetSimplify(b bool) {
+	simplify = b
+}
+
+func GetRegularity() bool {
+	return regularity
+}
+
+func SetRegularity(b bool) {
+	regularity = b
+}
+
+func SetTypeCheck(b bool) {
+	type_check = b
+}
+
 func SetNoTypeCheck() {
 	type_check = false
 }
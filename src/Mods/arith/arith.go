@@ -0,0 +1,168 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file contains the arithmetic module: a closure rule that evaluates the
+* ground arithmetic expressions and closes the branches whose arithmetic
+* literals are contradictory, regardless of the values of the metavariables.
+**/
+
+package arith
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+var arith_label = "ARI"
+
+func Enable() {
+	Search.TryArithmeticClosure = TryArithmeticClosure
+	gs3.ArithmeticClosure = closes
+}
+
+// Returns true if the branch containing form and the atomic formulas of the
+// state can be closed by arithmetic reasoning. The closure does not need any
+// substitution: the constraints are unsatisfiable for every instance of the
+// metavariables.
+func TryArithmeticClosure(form AST.Form, state *Search.State) bool {
+	atomics := []AST.Form{}
+	for _, f := range state.GetAtomic() {
+		atomics = append(atomics, f.GetForm())
+	}
+	return closes(form, atomics)
+}
+
+// Returns true if form and the literals among the formulas of a branch are
+// contradictory by arithmetic reasoning.
+func closes(form AST.Form, branch []AST.Form) bool {
+	if closesByEvaluation(form, branch) {
+		return true
+	}
+
+	lin := newLinearizer()
+	c, isArith := lin.literal(form)
+	if !isArith {
+		return false
+	}
+
+	if c.expr.isConstant() || c.rel == ne {
+		res := c.expr.isConstant() && c.isGroundFalse()
+		if res {
+			Glob.PrintDebug(arith_label, Lib.MkLazy(func() string {
+				return fmt.Sprintf("%s evaluates to false", form.ToString())
+			}))
+		}
+		return res
+	}
+
+	constraints := []constraint{c}
+	for _, f := range branch {
+		if other, isArith := lin.literal(f); isArith && other.rel != ne {
+			constraints = append(constraints, other)
+		}
+	}
+
+	res := lin.unsatisfiable(constraints)
+	if res {
+		Glob.PrintDebug(arith_label, Lib.MkLazy(func() string {
+			return fmt.Sprintf("Contradictory linear constraints with %s", form.ToString())
+		}))
+	}
+	return res
+}
+
+// Closes the branch if form is the complement of an atomic formula of the
+// branch, once the ground arithmetic expressions are evaluated.
+func closesByEvaluation(form AST.Form, branch []AST.Form) bool {
+	positive, key, evaluated := literalKey(form)
+	if !evaluated {
+		return false
+	}
+
+	for _, f := range branch {
+		if otherPositive, otherKey, _ := literalKey(f); otherPositive != positive && otherKey == key {
+			Glob.PrintDebug(arith_label, Lib.MkLazy(func() string {
+				return fmt.Sprintf("%s and %s are complementary", form.ToString(), f.ToString())
+			}))
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the polarity of a literal and a representation of its atom where the
+// ground arithmetic expressions are replaced by their values. The last boolean
+// tells whether such an expression has been found.
+func literalKey(form AST.Form) (bool, string, bool) {
+	positive := true
+	if not, isNot := form.(AST.Not); isNot {
+		positive = false
+		form = not.GetForm()
+	}
+
+	pred, isPred := form.(AST.Pred)
+	if !isPred {
+		return positive, "", false
+	}
+
+	evaluated := false
+	args := []string{}
+	for _, arg := range pred.GetArgs().GetSlice() {
+		args = append(args, termKey(arg, &evaluated))
+	}
+	return positive, fmt.Sprintf("%s(%s)", pred.GetID().GetName(), strings.Join(args, ", ")), evaluated
+}
+
+func termKey(t AST.Term, evaluated *bool) string {
+	if value, ok := evaluate(t); ok {
+		if fun, isFun := t.(AST.Fun); isFun && fun.GetArgs().Len() > 0 {
+			*evaluated = true
+		}
+		return value.RatString()
+	}
+
+	if fun, isFun := t.(AST.Fun); isFun && fun.GetArgs().Len() > 0 {
+		args := []string{}
+		for _, arg := range fun.GetArgs().GetSlice() {
+			args = append(args, termKey(arg, evaluated))
+		}
+		return fmt.Sprintf("%s(%s)", fun.GetName(), strings.Join(args, ", "))
+	}
+	return t.ToString()
+}
@@ -0,0 +1,143 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file contains the tests of the arithmetic module.
+**/
+
+package arith
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+func TestMain(m *testing.M) {
+	Glob.SetArithModule(true)
+	AST.Init()
+	m.Run()
+}
+
+func num(s string) AST.Term {
+	return AST.MakerConst(AST.MakerId(s))
+}
+
+func app(symbol string, args ...AST.Term) AST.Term {
+	return AST.MakerFun(AST.MakerId(symbol), Lib.MkListV(args...), []AST.TypeApp{})
+}
+
+func pred(symbol string, args ...AST.Term) AST.Form {
+	return AST.MakerPred(AST.MakerId(symbol), Lib.MkListV(args...), []AST.TypeApp{})
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		term     AST.Term
+		expected string
+	}{
+		{app("$sum", num("2"), num("3")), "5"},
+		{app("$product", num("1/2"), num("-4")), "-2"},
+		{app("$quotient_e", num("-7"), num("2")), "-4"},
+		{app("$quotient_t", num("-7"), num("2")), "-3"},
+		{app("$remainder_f", num("7"), num("-2")), "-1"},
+		{app("$remainder_e", num("-7"), num("-2")), "1"},
+		{app("$round", num("-2.5")), "-3"},
+		{app("$ceiling", num("-2.5")), "-2"},
+		{app("$to_int", num("-1/3")), "-1"},
+	}
+
+	for _, test := range tests {
+		value, ok := evaluate(test.term)
+		if !ok {
+			t.Fatalf("%s was not evaluated", test.term.ToString())
+		}
+		if value.RatString() != test.expected {
+			t.Errorf("%s evaluates to %s, expected %s", test.term.ToString(), value.RatString(), test.expected)
+		}
+	}
+
+	if _, ok := evaluate(app("$quotient", num("1"), num("0"))); ok {
+		t.Errorf("A division by zero has been evaluated")
+	}
+}
+
+func TestLinearConstraints(t *testing.T) {
+	x := AST.MakerMeta("X", -1, AST.MkTypeHint("$rat"))
+	n := AST.MakerMeta("N", -1, AST.MkTypeHint("$int"))
+
+	tests := []struct {
+		literals []AST.Form
+		unsat    bool
+	}{
+		// x < x + 1/2 cannot be false.
+		{[]AST.Form{AST.MakerNot(pred("$less", x, app("$sum", x, num("1/2"))))}, true},
+		{[]AST.Form{pred("$less", x, num("3")), pred("$greater", app("$product", num("2"), x), num("5"))}, false},
+		{[]AST.Form{pred("$less", x, num("3")), pred("$greatereq", x, num("3"))}, true},
+		// 2 < 2n < 4 has rational solutions only.
+		{[]AST.Form{
+			pred("$less", num("2"), app("$product", num("2"), n)),
+			pred("$less", app("$product", num("2"), n), num("4")),
+		}, true},
+		{[]AST.Form{pred("=", x, num("1")), pred("$lesseq", num("2"), x)}, true},
+	}
+
+	for i, test := range tests {
+		lin := newLinearizer()
+		constraints := []constraint{}
+		for _, literal := range test.literals {
+			c, isArith := lin.literal(literal)
+			if !isArith {
+				t.Fatalf("Test %d: %s is not an arithmetic literal", i, literal.ToString())
+			}
+			constraints = append(constraints, c)
+		}
+		if res := lin.unsatisfiable(constraints); res != test.unsat {
+			t.Errorf("Test %d: expected %v, got %v", i, test.unsat, res)
+		}
+	}
+}
+
+func TestTighten(t *testing.T) {
+	lin := newLinearizer()
+	lin.ints["n"] = true
+
+	// 2n - 1 < 0 becomes n <= 0.
+	expr := mkAtom("n").scale(big.NewRat(2, 1)).add(mkConstant(big.NewRat(-1, 1)))
+	res := lin.tighten(constraint{expr, lt})
+	if res.rel != le || res.expr.coefs["n"].Cmp(big.NewRat(1, 1)) != 0 || res.expr.constant.Sign() != 0 {
+		t.Errorf("Unexpected tightened constraint: %s", res.key())
+	}
+}
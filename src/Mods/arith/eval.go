@@ -0,0 +1,429 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file evaluates the TPTP arithmetic symbols and translates arithmetic
+* literals into linear constraints. Numbers are represented by exact rationals,
+* hence $int, $rat and $real are all handled over the rationals.
+**/
+
+package arith
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+)
+
+// Relation between a linear expression e and 0: e < 0, e <= 0, e = 0 or e != 0.
+type relation int
+
+const (
+	lt relation = iota
+	le
+	eq
+	ne
+)
+
+// A linear combination of atoms with rational coefficients, plus a constant.
+// Atoms are metavariables or terms that cannot be evaluated, identified by
+// their string representation.
+type linear struct {
+	coefs    map[string]*big.Rat
+	constant *big.Rat
+}
+
+type constraint struct {
+	expr linear
+	rel  relation
+}
+
+func mkConstant(value *big.Rat) linear {
+	return linear{map[string]*big.Rat{}, new(big.Rat).Set(value)}
+}
+
+func mkAtom(name string) linear {
+	return linear{map[string]*big.Rat{name: big.NewRat(1, 1)}, new(big.Rat)}
+}
+
+func (l linear) isConstant() bool {
+	return len(l.coefs) == 0
+}
+
+func (l linear) scale(factor *big.Rat) linear {
+	res := mkConstant(new(big.Rat).Mul(l.constant, factor))
+	if factor.Sign() == 0 {
+		return res
+	}
+	for atom, coef := range l.coefs {
+		res.coefs[atom] = new(big.Rat).Mul(coef, factor)
+	}
+	return res
+}
+
+func (l linear) add(other linear) linear {
+	res := mkConstant(new(big.Rat).Add(l.constant, other.constant))
+	for atom, coef := range l.coefs {
+		res.coefs[atom] = new(big.Rat).Set(coef)
+	}
+	for atom, coef := range other.coefs {
+		if c, found := res.coefs[atom]; found {
+			c.Add(c, coef)
+			if c.Sign() == 0 {
+				delete(res.coefs, atom)
+			}
+		} else {
+			res.coefs[atom] = new(big.Rat).Set(coef)
+		}
+	}
+	return res
+}
+
+func (l linear) sub(other linear) linear {
+	return l.add(other.scale(big.NewRat(-1, 1)))
+}
+
+// Holds the atoms known to denote integers, used to strengthen the constraints.
+type linearizer struct {
+	ints map[string]bool
+}
+
+func newLinearizer() *linearizer {
+	return &linearizer{map[string]bool{}}
+}
+
+func isArithSymbol(name string) bool {
+	switch name {
+	case "$sum", "$difference", "$product", "$quotient",
+		"$quotient_e", "$quotient_t", "$quotient_f",
+		"$remainder_e", "$remainder_t", "$remainder_f",
+		"$uminus", "$floor", "$ceiling", "$truncate", "$round",
+		"$to_int", "$to_rat", "$to_real":
+		return true
+	}
+	return false
+}
+
+// Numbers are parsed as constants named after their TPTP representation.
+func numeral(t AST.Term) (*big.Rat, bool) {
+	if fun, isFun := t.(AST.Fun); isFun && fun.GetArgs().Len() == 0 {
+		name := fun.GetName()
+		if name == "" || !(name[0] == '-' || name[0] == '+' || (name[0] >= '0' && name[0] <= '9')) {
+			return nil, false
+		}
+		return new(big.Rat).SetString(strings.TrimPrefix(name, "+"))
+	}
+	return nil, false
+}
+
+func isIntNumeral(t AST.Term) bool {
+	if _, ok := numeral(t); ok {
+		return !strings.ContainsAny(t.(AST.Fun).GetName(), "/.eE")
+	}
+	return false
+}
+
+// Tells whether t denotes an integer, as far as the types tell.
+func isIntTerm(t AST.Term) bool {
+	switch term := t.(type) {
+	case AST.Meta:
+		return term.GetTypeHint() != nil && AST.IsInt(term.GetTypeHint())
+	case AST.Fun:
+		if _, ok := numeral(term); ok {
+			return isIntNumeral(term)
+		}
+		switch term.GetName() {
+		case "$to_int":
+			return true
+		case "$to_rat", "$to_real", "$quotient":
+			return false
+		}
+		if isArithSymbol(term.GetName()) {
+			for _, arg := range term.GetArgs().GetSlice() {
+				if !isIntTerm(arg) {
+					return false
+				}
+			}
+			return true
+		}
+		ty := valueType(term)
+		return ty != nil && AST.IsInt(ty)
+	}
+	return false
+}
+
+// The type of the values of a function: the one it has been built with, as for
+// the Skolem symbols of the δ-rules, which are not in the typing context, or
+// else the one of the typing context.
+func valueType(fun AST.Fun) AST.TypeScheme {
+	if ty := fun.GetTypeHint(); ty != nil {
+		out, _ := AST.GetOutType(ty).(AST.TypeScheme)
+		return out
+	}
+	if fun.GetArgs().Len() == 0 {
+		return AST.GetType(fun.GetName())
+	}
+	return nil
+}
+
+// Tells whether t is an arithmetic expression: a number, an arithmetic
+// function, or a term whose type is a number type.
+func isArithTerm(t AST.Term) bool {
+	switch term := t.(type) {
+	case AST.Meta:
+		ty := term.GetTypeHint()
+		return ty != nil && (AST.IsInt(ty) || AST.IsRat(ty) || AST.IsReal(ty))
+	case AST.Fun:
+		if _, ok := numeral(term); ok || isArithSymbol(term.GetName()) {
+			return true
+		}
+		ty := valueType(term)
+		return ty != nil && (AST.IsInt(ty) || AST.IsRat(ty) || AST.IsReal(ty))
+	}
+	return false
+}
+
+// Evaluates a ground arithmetic term.
+func evaluate(t AST.Term) (*big.Rat, bool) {
+	if value, ok := numeral(t); ok {
+		return value, true
+	}
+
+	fun, isFun := t.(AST.Fun)
+	if !isFun || !isArithSymbol(fun.GetName()) {
+		return nil, false
+	}
+
+	args := []*big.Rat{}
+	for _, arg := range fun.GetArgs().GetSlice() {
+		value, ok := evaluate(arg)
+		if !ok {
+			return nil, false
+		}
+		args = append(args, value)
+	}
+
+	switch len(args) {
+	case 1:
+		return evaluateUnary(fun.GetName(), args[0])
+	case 2:
+		return evaluateBinary(fun.GetName(), args[0], args[1])
+	}
+	return nil, false
+}
+
+func evaluateUnary(symbol string, x *big.Rat) (*big.Rat, bool) {
+	switch symbol {
+	case "$uminus":
+		return new(big.Rat).Neg(x), true
+	case "$floor", "$to_int":
+		return floor(x), true
+	case "$ceiling":
+		return new(big.Rat).Neg(floor(new(big.Rat).Neg(x))), true
+	case "$truncate":
+		return truncate(x), true
+	case "$round":
+		// Halfway values are rounded away from zero.
+		half := big.NewRat(1, 2)
+		if x.Sign() < 0 {
+			return new(big.Rat).Neg(floor(new(big.Rat).Add(new(big.Rat).Neg(x), half))), true
+		}
+		return floor(new(big.Rat).Add(x, half)), true
+	case "$to_rat", "$to_real":
+		return x, true
+	}
+	return nil, false
+}
+
+func evaluateBinary(symbol string, x, y *big.Rat) (*big.Rat, bool) {
+	switch symbol {
+	case "$sum":
+		return new(big.Rat).Add(x, y), true
+	case "$difference":
+		return new(big.Rat).Sub(x, y), true
+	case "$product":
+		return new(big.Rat).Mul(x, y), true
+	}
+
+	if y.Sign() == 0 {
+		return nil, false
+	}
+	quotient := new(big.Rat).Quo(x, y)
+
+	switch symbol {
+	case "$quotient":
+		return quotient, true
+	case "$quotient_t":
+		return truncate(quotient), true
+	case "$quotient_f":
+		return floor(quotient), true
+	case "$quotient_e":
+		return euclideanQuotient(quotient, y), true
+	case "$remainder_t":
+		return remainder(x, y, truncate(quotient)), true
+	case "$remainder_f":
+		return remainder(x, y, floor(quotient)), true
+	case "$remainder_e":
+		return remainder(x, y, euclideanQuotient(quotient, y)), true
+	}
+	return nil, false
+}
+
+func floor(x *big.Rat) *big.Rat {
+	num, den := x.Num(), x.Denom()
+	// big.Int.Div is the Euclidean division: with a positive divisor, it is the floor.
+	return new(big.Rat).SetInt(new(big.Int).Div(num, den))
+}
+
+func truncate(x *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Quo(x.Num(), x.Denom()))
+}
+
+// The Euclidean quotient q of x by y is such that 0 <= x - q*y < |y|.
+func euclideanQuotient(quotient, y *big.Rat) *big.Rat {
+	if y.Sign() > 0 {
+		return floor(quotient)
+	}
+	return new(big.Rat).Neg(floor(new(big.Rat).Neg(quotient)))
+}
+
+func remainder(x, y, quotient *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(x, new(big.Rat).Mul(y, quotient))
+}
+
+func (l *linearizer) atom(t AST.Term) linear {
+	name := t.ToString()
+	if isIntTerm(t) {
+		l.ints[name] = true
+	}
+	return mkAtom(name)
+}
+
+func (l *linearizer) linearize(t AST.Term) linear {
+	if value, ok := evaluate(t); ok {
+		return mkConstant(value)
+	}
+
+	fun, isFun := t.(AST.Fun)
+	if !isFun {
+		return l.atom(t)
+	}
+
+	args := fun.GetArgs().GetSlice()
+	switch fun.GetName() {
+	case "$sum":
+		return l.linearize(args[0]).add(l.linearize(args[1]))
+	case "$difference":
+		return l.linearize(args[0]).sub(l.linearize(args[1]))
+	case "$uminus":
+		return l.linearize(args[0]).scale(big.NewRat(-1, 1))
+	case "$to_rat", "$to_real":
+		return l.linearize(args[0])
+	case "$product":
+		left, right := l.linearize(args[0]), l.linearize(args[1])
+		if left.isConstant() {
+			return right.scale(left.constant)
+		}
+		if right.isConstant() {
+			return left.scale(right.constant)
+		}
+	case "$quotient":
+		right := l.linearize(args[1])
+		if right.isConstant() && right.constant.Sign() != 0 {
+			return l.linearize(args[0]).scale(new(big.Rat).Inv(right.constant))
+		}
+	}
+
+	return l.atom(t)
+}
+
+// Translates an arithmetic literal into a constraint. The boolean is false if
+// the literal is not an arithmetic one.
+func (l *linearizer) literal(form AST.Form) (constraint, bool) {
+	positive := true
+	if not, isNot := form.(AST.Not); isNot {
+		positive = false
+		form = not.GetForm()
+	}
+
+	pred, isPred := form.(AST.Pred)
+	if !isPred {
+		return constraint{}, false
+	}
+	args := pred.GetArgs().GetSlice()
+
+	switch {
+	case pred.GetID().Equals(AST.Id_eq) && len(args) == 2:
+		if !isArithTerm(args[0]) && !isArithTerm(args[1]) {
+			return constraint{}, false
+		}
+		expr := l.linearize(args[0]).sub(l.linearize(args[1]))
+		if positive {
+			return constraint{expr, eq}, true
+		}
+		return constraint{expr, ne}, true
+
+	case len(args) == 2:
+		x, y := l.linearize(args[0]), l.linearize(args[1])
+		// Negations are turned into the converse relations: the order is total.
+		switch pred.GetID().GetName() {
+		case "$less":
+			if positive {
+				return constraint{x.sub(y), lt}, true
+			}
+			return constraint{y.sub(x), le}, true
+		case "$lesseq":
+			if positive {
+				return constraint{x.sub(y), le}, true
+			}
+			return constraint{y.sub(x), lt}, true
+		case "$greater":
+			if positive {
+				return constraint{y.sub(x), lt}, true
+			}
+			return constraint{x.sub(y), le}, true
+		case "$greatereq":
+			if positive {
+				return constraint{y.sub(x), le}, true
+			}
+			return constraint{x.sub(y), lt}, true
+		}
+
+	case len(args) == 1 && pred.GetID().GetName() == "$is_int":
+		if value, ok := evaluate(args[0]); ok && value.IsInt() != positive {
+			return constraint{mkConstant(big.NewRat(1, 1)), le}, true
+		}
+	}
+
+	return constraint{}, false
+}
@@ -0,0 +1,230 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the Fourier-Motzkin elimination, used to decide whether
+* a set of linear constraints is unsatisfiable. When every atom of a
+* constraint denotes an integer, the constraint is tightened, which allows to
+* refute some problems that have rational solutions but no integer ones. The
+* procedure remains incomplete over the integers.
+**/
+
+package arith
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Upper bound on the number of constraints, as the elimination may blow up.
+const maxConstraints = 1000
+
+// Tells whether a constraint without atoms is violated.
+func (c constraint) isGroundFalse() bool {
+	sign := c.expr.constant.Sign()
+	switch c.rel {
+	case lt:
+		return sign >= 0
+	case le:
+		return sign > 0
+	case eq:
+		return sign != 0
+	case ne:
+		return sign == 0
+	}
+	return false
+}
+
+func (c constraint) key() string {
+	atoms := c.expr.atoms()
+	parts := make([]string, 0, len(atoms)+2)
+	for _, atom := range atoms {
+		parts = append(parts, fmt.Sprintf("%s*%s", c.expr.coefs[atom].RatString(), atom))
+	}
+	parts = append(parts, c.expr.constant.RatString(), fmt.Sprint(c.rel))
+	return strings.Join(parts, " ")
+}
+
+// The atoms of the expression, sorted to get a deterministic behaviour.
+func (l linear) atoms() []string {
+	atoms := make([]string, 0, len(l.coefs))
+	for atom := range l.coefs {
+		atoms = append(atoms, atom)
+	}
+	sort.Strings(atoms)
+	return atoms
+}
+
+// Strengthens e < 0 or e <= 0 when all the atoms of e denote integers: with
+// g the gcd of the coefficients of e = g*s + c, where s is an integer, it
+// becomes s + k <= 0 with k the smallest integer allowed.
+func (l *linearizer) tighten(c constraint) constraint {
+	if c.expr.isConstant() || (c.rel != lt && c.rel != le) {
+		return c
+	}
+
+	lcm := big.NewInt(1)
+	for _, atom := range c.expr.atoms() {
+		if !l.ints[atom] {
+			return c
+		}
+		den := c.expr.coefs[atom].Denom()
+		gcd := new(big.Int).GCD(nil, nil, lcm, den)
+		lcm.Mul(lcm, new(big.Int).Quo(den, gcd))
+	}
+
+	gcd := new(big.Int)
+	for _, coef := range c.expr.coefs {
+		num := new(big.Int).Mul(coef.Num(), new(big.Int).Quo(lcm, coef.Denom()))
+		gcd.GCD(nil, nil, gcd, num.Abs(num))
+	}
+
+	factor := new(big.Rat).SetFrac(lcm, gcd)
+	res := c.expr.scale(factor)
+	bound := new(big.Rat).Neg(res.constant)
+	if c.rel == lt {
+		bound = new(big.Rat).Sub(ceil(bound), big.NewRat(1, 1))
+	} else {
+		bound = floor(bound)
+	}
+	res.constant = bound.Neg(bound)
+	return constraint{res, le}
+}
+
+func ceil(x *big.Rat) *big.Rat {
+	return new(big.Rat).Neg(floor(new(big.Rat).Neg(x)))
+}
+
+// Returns true if the constraints are unsatisfiable. Equalities are split in
+// two inequalities, and disequalities must have been discarded beforehand.
+func (l *linearizer) unsatisfiable(constraints []constraint) bool {
+	current := []constraint{}
+	for _, c := range constraints {
+		if c.rel == eq {
+			current = append(current, constraint{c.expr, le}, constraint{c.expr.scale(big.NewRat(-1, 1)), le})
+		} else {
+			current = append(current, c)
+		}
+	}
+
+	for {
+		var refuted bool
+		if current, refuted = l.simplify(current); refuted {
+			return true
+		}
+
+		atom, found := chooseAtom(current)
+		if !found || len(current) > maxConstraints {
+			return false
+		}
+		current = l.eliminate(current, atom)
+	}
+}
+
+// Tightens and deduplicates the constraints, and checks the ground ones.
+func (l *linearizer) simplify(constraints []constraint) ([]constraint, bool) {
+	res := []constraint{}
+	seen := map[string]bool{}
+	for _, c := range constraints {
+		c = l.tighten(c)
+		if c.expr.isConstant() {
+			if c.isGroundFalse() {
+				return nil, true
+			}
+			continue
+		}
+		if key := c.key(); !seen[key] {
+			seen[key] = true
+			res = append(res, c)
+		}
+	}
+	return res, false
+}
+
+// Chooses the atom whose elimination produces the fewest constraints.
+func chooseAtom(constraints []constraint) (string, bool) {
+	pos, neg := map[string]int{}, map[string]int{}
+	for _, c := range constraints {
+		for atom, coef := range c.expr.coefs {
+			if coef.Sign() > 0 {
+				pos[atom]++
+			} else {
+				neg[atom]++
+			}
+		}
+	}
+
+	best, bestCost, found := "", 0, false
+	for _, c := range constraints {
+		for _, atom := range c.expr.atoms() {
+			cost := pos[atom]*neg[atom] - pos[atom] - neg[atom]
+			if !found || cost < bestCost || (cost == bestCost && atom < best) {
+				best, bestCost, found = atom, cost, true
+			}
+		}
+	}
+	return best, found
+}
+
+// Eliminates an atom by combining each of its lower bounds with each of its
+// upper bounds. Constraints in which the atom occurs only with one sign are
+// dropped, as they can always be satisfied by choosing the atom.
+func (l *linearizer) eliminate(constraints []constraint, atom string) []constraint {
+	res, upper, lower := []constraint{}, []constraint{}, []constraint{}
+	for _, c := range constraints {
+		coef, found := c.expr.coefs[atom]
+		switch {
+		case !found:
+			res = append(res, c)
+		case coef.Sign() > 0:
+			upper = append(upper, c)
+		default:
+			lower = append(lower, c)
+		}
+	}
+
+	for _, up := range upper {
+		for _, low := range lower {
+			upCoef := up.expr.coefs[atom]
+			lowCoef := new(big.Rat).Neg(low.expr.coefs[atom])
+			expr := up.expr.scale(new(big.Rat).Inv(upCoef)).add(low.expr.scale(new(big.Rat).Inv(lowCoef)))
+			rel := le
+			if up.rel == lt || low.rel == lt {
+				rel = lt
+			}
+			res = append(res, constraint{expr, rel})
+		}
+	}
+	return res
+}
@@ -223,6 +223,10 @@ Lemma goeland_notall : forall (T : Type) (P : T -> Prop),
   (forall z : T, (~(P z) -> False)) -> (~(forall x : T, (P x)) -> False).
 Proof. intros T P Ha Hb. apply Hb. intro. apply NNPP. exact (Ha x). Qed.
 
+Lemma goeland_cut : forall P : Prop,
+  (~P -> False) -> (P -> False) -> False.
+Proof. tauto. Qed.
+
 Definition goeland_and_s := fun P Q c h => goeland_and P Q h c.
 Definition goeland_or_s := fun P Q c h i => goeland_or P Q h i c.
 Definition goeland_imply_s := fun P Q c h i => goeland_imply P Q h i c.
@@ -42,190 +42,122 @@ import (
 
 	"github.com/GoelandProver/Goeland/AST"
 	"github.com/GoelandProver/Goeland/Glob"
-	"github.com/GoelandProver/Goeland/Mods/dmt"
 	"github.com/GoelandProver/Goeland/Mods/gs3"
 )
 
-var dummy int
-
-func makeCoqProofFromGS3(proof *gs3.GS3Sequent) string {
-	dummy = 0
-	axioms, conjecture := processMainFormula(proof.GetTargetForm())
-	totalAxioms := axioms.Len()
-	if Glob.IsLoaded("dmt") {
-		axioms.Append(dmt.GetRegisteredAxioms().Slice()...)
-	}
-	var resultingString string
-	resultingString = makeTheorem(axioms, conjecture)
-	resultingString += "Proof.\n"
-	hypotheses := AST.NewFormList()
-	if axioms.Len() > 0 {
-		indices := make([]int, axioms.Len())
-		for i, form := range axioms.Slice() {
-			indices[i], hypotheses = introduce(form, hypotheses)
-		}
-		resultingString += "intros " + strings.Join(Glob.MapTo(indices, func(_ int, index int) string { return introName(index) }), " ") + ". "
-		if totalAxioms > 0 {
-			proof = proof.Child(0)
-		}
-	}
-	index, hypotheses := introduce(AST.MakerNot(conjecture), hypotheses)
-	resultingString += "intro " + introName(index) + ". "
-	resultingString += followProofSteps(proof, hypotheses, make([]AST.Term, 0))
-
-	return resultingString + "\nQed.\n"
+// The Coq steps of the proof, one per line.
+var printer = gs3.Printer[string]{
+	Name:         "Coq",
+	Closure:      closureStep,
+	Rule:         ruleStep,
+	Weaken:       weakenStep,
+	Rewrite:      rewriteStep,
+	VariableName: getConstantName,
 }
 
-func followProofSteps(proof *gs3.GS3Sequent, hypotheses *AST.FormList, constantsCreated []AST.Term) string {
-	var resultingString string
-	var childrenHypotheses []*AST.FormList
-	if !proof.IsEmpty() {
-		resultingString, childrenHypotheses, constantsCreated = makeProofStep(proof, hypotheses, constantsCreated)
-	}
-	for i, child := range proof.Children() {
-		if proof.IsEmpty() {
-			resultingString += "\n" + followProofSteps(child, hypotheses.Copy(), cp(constantsCreated))
-		} else {
-			resultingString += "\n" + followProofSteps(child, childrenHypotheses[i].Copy(), cp(constantsCreated))
-		}
-	}
-	return resultingString
-}
-
-func makeProofStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, constantsCreated []AST.Term) (string, []*AST.FormList, []AST.Term) {
-	stepResult, childrenHypotheses, constantsCreated := makeStep(proof, hypotheses, constantsCreated)
-	return stepResult, childrenHypotheses, constantsCreated
-}
-
-func makeStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, constantsCreated []AST.Term) (string, []*AST.FormList, []AST.Term) {
-	var resultingString string
-	childrenHypotheses := []*AST.FormList{hypotheses}
-
-	target, _ := hypotheses.GetIndexOf(proof.GetTargetForm())
-	switch proof.Rule() {
-	// Closure.
-	case gs3.AX:
-		if isPredEqual(proof.GetTargetForm()) {
-			resultingString = "congruence."
-		} else {
-			resultingString = "auto."
-		}
-
+// The formats of the rules: the target is applied, then the formulas of the
+// premises are introduced.
+var ruleFormats = map[gs3.Rule]string{
 	// Alpha rules
-	case gs3.NNOT:
-		resultingString, childrenHypotheses = alphaStep(proof, hypotheses, target, "%s")
-	case gs3.AND:
-		resultingString, childrenHypotheses = alphaStep(proof, hypotheses, target, "(goeland_and_s _ _ %s)")
-	case gs3.NOR:
-		resultingString, childrenHypotheses = alphaStep(proof, hypotheses, target, "(goeland_notor_s _ _ %s)")
-	case gs3.NIMP:
-		resultingString, childrenHypotheses = alphaStep(proof, hypotheses, target, "(goeland_notimply_s _ _ %s)")
+	gs3.NNOT: "%s",
+	gs3.AND:  "(goeland_and_s _ _ %s)",
+	gs3.NOR:  "(goeland_notor_s _ _ %s)",
+	gs3.NIMP: "(goeland_notimply_s _ _ %s)",
 
 	// Beta rules
-	case gs3.NAND:
-		resultingString, childrenHypotheses = betaStep(proof, hypotheses, target, "(goeland_notand_s _ _ %s)")
-	case gs3.NEQU:
-		resultingString, childrenHypotheses = betaStep(proof, hypotheses, target, "(goeland_notequiv_s _ _ %s)")
-	case gs3.OR:
-		resultingString, childrenHypotheses = betaStep(proof, hypotheses, target, "(goeland_or_s _ _ %s)")
-	case gs3.IMP:
-		resultingString, childrenHypotheses = betaStep(proof, hypotheses, target, "(goeland_imply_s _ _ %s)")
-	case gs3.EQU:
-		resultingString, childrenHypotheses = betaStep(proof, hypotheses, target, "(goeland_equiv_s _ _ %s)")
+	gs3.NAND: "(goeland_notand_s _ _ %s)",
+	gs3.NEQU: "(goeland_notequiv_s _ _ %s)",
+	gs3.OR:   "(goeland_or_s _ _ %s)",
+	gs3.IMP:  "(goeland_imply_s _ _ %s)",
+	gs3.EQU:  "(goeland_equiv_s _ _ %s)",
 
 	// Delta rules
-	case gs3.NALL:
-		resultingString, childrenHypotheses, constantsCreated = deltaStep(proof, hypotheses, target, "apply %s. intros %s. apply NNPP. intros %s. ", constantsCreated)
-	case gs3.EX:
-		resultingString, childrenHypotheses, constantsCreated = deltaStep(proof, hypotheses, target, "elim %s. intros %s. intros %s. ", constantsCreated)
+	gs3.NALL: "apply %s. intros %s. apply NNPP. intros %s. ",
+	gs3.EX:   "elim %s. intros %s. intros %s. ",
 
 	// Gamma rules
-	case gs3.ALL:
-		resultingString, childrenHypotheses = gammaStep(proof, hypotheses, target, "generalize (%s %s). intros %s. ", constantsCreated)
-	case gs3.NEX:
-		resultingString, childrenHypotheses = gammaStep(proof, hypotheses, target, "apply %s. exists %s. apply NNPP. intros %s. ", constantsCreated)
+	gs3.ALL: "generalize (%s %s). intros %s. ",
+	gs3.NEX: "apply %s. exists %s. apply NNPP. intros %s. ",
+}
 
-	// Weakening rule
-	case gs3.W:
-		if proof.TermGenerated() != nil {
-			resultingString = fmt.Sprintf("clear %s.", getConstantName(proof.TermGenerated().(AST.Fun).GetID()))
-		} else {
-			resultingString, childrenHypotheses = cleanHypotheses(hypotheses, proof.GetTargetForm())
+func makeCoqProofFromGS3(proof *gs3.GS3Sequent) string {
+	theorem, proofString := gs3.PrintProof(proof, printer)
+	resultingString := makeTheorem(theorem.Axioms, theorem.Conjecture)
+	resultingString += "Proof.\n"
+	if theorem.Axioms.Len() > 0 {
+		indices := make([]int, theorem.Axioms.Len())
+		for i := range indices {
+			indices[i] = i
 		}
-
-	case gs3.REWRITE:
-		resultingString, childrenHypotheses = rewriteStep(proof.GetRewriteWith(), hypotheses, target, proof.GetResultFormulasOfChild(0).Get(0))
+		resultingString += "intros " + introNames(indices) + ". "
 	}
-
-	return resultingString, childrenHypotheses, constantsCreated
-}
-
-func alphaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, format string) (string, []*AST.FormList) {
-	var indices []int
-	indices, hypotheses = introduceList(proof.GetResultFormulasOfChild(0), hypotheses)
-	resultingString := fmt.Sprintf("apply "+format+". intros %s. ", introName(target), introNames(indices))
-	return resultingString, []*AST.FormList{hypotheses}
-}
-
-func betaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, format string) (string, []*AST.FormList) {
-	resultHyps := []*AST.FormList{}
-	var indices []int
-	resultingString := fmt.Sprintf("apply "+format+"; ", introName(target))
-	introducedNames := make([]string, 0)
-	for i := range proof.Children() {
-		hypoCopy := hypotheses.Copy()
-		indices, hypoCopy = introduceList(proof.GetResultFormulasOfChild(i), hypoCopy)
-		introducedNames = append(introducedNames, "intros "+introNames(indices, " "))
-		resultHyps = append(resultHyps, hypoCopy)
+	if theorem.Conjecture != nil {
+		resultingString += "intro " + introName(theorem.Axioms.Len()) + ". "
 	}
+	resultingString += proofString
 
-	return resultingString + "[ " + strings.Join(introducedNames, " | ") + " ].", resultHyps
+	return resultingString + "\nQed.\n"
 }
 
-func deltaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, format string, constantsCreated []AST.Term) (string, []*AST.FormList, []AST.Term) {
-	var indices []int
-	var name string
-	//PrintInfo("DELTA", fmt.Sprintf("%s\n%s", hypotheses[target].ToString(), proof.GetResultFormulasOfChild(0).ToString()))
-	indices, hypotheses = introduceList(proof.GetResultFormulasOfChild(0), hypotheses)
-	constantsCreated, name = addTermGenerated(constantsCreated, proof.TermGenerated())
-	resultingString := fmt.Sprintf(format, introName(target), name, introNames(indices))
-	return resultingString, []*AST.FormList{hypotheses}, constantsCreated
+func closureStep(target AST.Form, branch gs3.Branch) string {
+	if isPredEqual(target) {
+		return "congruence."
+	}
+	return "auto."
 }
 
-func gammaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, format string, constantsCreated []AST.Term) (string, []*AST.FormList) {
-	var indices []int
-	indices, hypotheses = introduceList(proof.GetResultFormulasOfChild(0), hypotheses)
-	name := "(" + getRealConstantName(constantsCreated, proof.TermGenerated()) + ")"
-	resultingString := fmt.Sprintf(format, introName(target), name, introNames(indices))
-	return resultingString, []*AST.FormList{hypotheses}
+func ruleStep(proof *gs3.GS3Sequent, target int, branch gs3.Branch, premises []gs3.Premise[string]) string {
+	var resultingString string
+	format := ruleFormats[proof.Rule()]
+	switch {
+	case gs3.IsAlphaRule(proof.Rule()):
+		resultingString = fmt.Sprintf("apply "+format+". intros %s. ", introName(target), introNames(premises[0].Introduced))
+	case gs3.IsBetaRule(proof.Rule()):
+		introducedNames := Glob.MapTo(premises, func(_ int, premise gs3.Premise[string]) string {
+			return "intros " + introNames(premise.Introduced)
+		})
+		resultingString = fmt.Sprintf("apply "+format+"; ", introName(target)) + "[ " + strings.Join(introducedNames, " | ") + " ]."
+	case gs3.IsDeltaRule(proof.Rule()):
+		resultingString = fmt.Sprintf(format, introName(target), premises[0].Variable, introNames(premises[0].Introduced))
+	case gs3.IsGammaRule(proof.Rule()):
+		name := "(" + getRealConstantName(branch.Constants, proof.TermGenerated()) + ")"
+		resultingString = fmt.Sprintf(format, introName(target), name, introNames(premises[0].Introduced))
+	case proof.Rule() == gs3.CUT:
+		cut := mapDefault(proof.GetCutFormula().ToMappedString(coqMapConnectors(), Glob.GetTypeProof()))
+		resultingString = fmt.Sprintf("apply (goeland_cut (%s)); [ intros %s | intros %s ].", cut, introNames(premises[0].Introduced), introNames(premises[1].Introduced))
+	}
+
+	for _, premise := range premises {
+		resultingString += "\n" + premise.Proof
+	}
+	return resultingString
 }
 
-func rewriteStep(rewriteRule AST.Form, hypotheses *AST.FormList, target int, replacementForm AST.Form) (string, []*AST.FormList) {
-	index, _ := hypotheses.GetIndexOf(rewriteRule)
-	resultingString := fmt.Sprintf("rewrite %s in %s.", introName(index), introName(target))
-	hypotheses.Set(target, replacementForm)
-	return resultingString, []*AST.FormList{hypotheses}
+// Clears the weakened skolem symbol or hypothesis.
+func weakenStep(proof *gs3.GS3Sequent, target int, premise gs3.Premise[string]) string {
+	resultingString := ""
+	if proof.TermGenerated() != nil {
+		resultingString = fmt.Sprintf("clear %s.", getConstantName(proof.TermGenerated().(AST.Fun).GetID()))
+	} else if target != -1 {
+		resultingString = fmt.Sprintf("clear %s. ", introName(target))
+	}
+	return resultingString + "\n" + premise.Proof
 }
 
-// Processes the formula that was proven by Goéland.
-func processMainFormula(form AST.Form) (*AST.FormList, AST.Form) {
-	formList := AST.NewFormList()
-	switch nf := form.(type) {
-	case AST.Not:
-		form = nf.GetForm()
-	case AST.And:
-		last := nf.FormList.Len() - 1
-		formList = AST.NewFormList(nf.FormList.GetElements(0, last)...)
-		form = nf.FormList.Get(last).(AST.Not).GetForm()
-	}
-	return formList, form
+func rewriteStep(proof *gs3.GS3Sequent, target, rule int, branch gs3.Branch, premise gs3.Premise[string]) string {
+	return fmt.Sprintf("rewrite %s in %s.", introName(rule), introName(target)) + "\n" + premise.Proof
 }
 
-// Prints the theorem's name & properly formats the first formula.
+// Prints the theorem's name & properly formats the first formula. The axioms
+// imply False when there is no conjecture.
 func makeTheorem(axioms *AST.FormList, conjecture AST.Form) string {
 	problemName := strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(Glob.GetProblemName(), ".", "_"), "=", "_"), "+", "_")
 	axiomsWithConj := axioms.Copy()
-	axiomsWithConj.Append(AST.MakerNot(AST.MakerNot(conjecture)))
+	if conjecture != nil {
+		axiomsWithConj.Append(AST.MakerNot(AST.MakerNot(conjecture)))
+	} else {
+		axiomsWithConj.Append(AST.MakerBot())
+	}
 	formattedProblem := makeImpChain(axiomsWithConj)
 	return "Theorem goeland_proof_of_" + problemName + " : " + mapDefault(formattedProblem.ToMappedString(coqMapConnectors(), Glob.GetTypeProof())) + ".\n"
 }
@@ -240,22 +172,6 @@ func makeImpChain(forms *AST.FormList) AST.Form {
 	return form
 }
 
-// Introduces a new formula in coq's hypotheses.
-func introduce(f AST.Form, hypotheses *AST.FormList) (int, *AST.FormList) {
-	//PrintInfo("INTRODUCING", f.ToString())
-	index := hypotheses.Len()
-	hypotheses.Append(f)
-	return index, hypotheses
-}
-
-func introduceList(fl, hypotheses *AST.FormList) ([]int, *AST.FormList) {
-	indices := make([]int, fl.Len())
-	for i, f := range fl.Slice() {
-		indices[i], hypotheses = introduce(f, hypotheses)
-	}
-	return indices, hypotheses
-}
-
 // Makes a Coq's name for a new hypothesis.
 func introName(i int) string {
 	return fmt.Sprintf("H%d", i)
@@ -281,15 +197,6 @@ func isPredEqual(f AST.Form) bool {
 	return false
 }
 
-func addTermGenerated(constantsCreated []AST.Term, term AST.Term) ([]AST.Term, string) {
-	if term == nil {
-		dummy++
-		return constantsCreated, fmt.Sprintf("x%d", dummy-1)
-	}
-	constantsCreated = append(constantsCreated, term)
-	return constantsCreated, getConstantName(term.(AST.Fun).GetID())
-}
-
 func getRealConstantName(constantsCreated []AST.Term, term AST.Term) string {
 	if term == nil {
 		return "goeland_I"
@@ -326,22 +233,6 @@ func findInConstants(constantsCreated []AST.Term, term AST.Term) string {
 	return "goeland_I"
 }
 
-func cp[T any](source []T) []T {
-	arr := make([]T, len(source))
-	copy(arr, source)
-	return arr
-}
-
-func cleanHypotheses(hypotheses *AST.FormList, form AST.Form) (string, []*AST.FormList) {
-	result := ""
-	index, _ := hypotheses.GetIndexOf(form)
-	if index != -1 {
-		hypotheses.Set(index, AST.MakerTop())
-		result = fmt.Sprintf("clear %s. ", introName(index))
-	}
-	return result, []*AST.FormList{hypotheses}
-}
-
 func getConstantName(id AST.Id) string {
 	return id.ToString()
 }
@@ -64,7 +64,11 @@ func tryEqualityReasoningProblem(ep EqualityProblem, father_chan chan answerEP,
 	case <-father_chan: // kil order
 	default:
 		// No kill order, let's apply the next rules.
-		found, substs := equalityReasoningProblem(ep, father_chan, last_applied_rule_index, last_applied_rule_type)
+		found, substs, killed := equalityReasoningProblem(ep, father_chan, last_applied_rule_index, last_applied_rule_type)
+		if killed {
+			// The father received the kill order, it does not wait for an answer.
+			return
+		}
 		Glob.PrintDebug(
 			"TERP",
 			Lib.MkLazy(func() string { return "Send solution to father_chan" }),
@@ -81,8 +85,8 @@ func tryEqualityReasoningProblem(ep EqualityProblem, father_chan chan answerEP,
 	}
 }
 
-/* launch an equality reasoning problem resolution. Stop when the first solution is found */
-func equalityReasoningProblem(ep EqualityProblem, father_chan chan answerEP, last_applied_rule_index, last_applied_rule_type int) (bool, []Unif.Substitutions) {
+/* launch an equality reasoning problem resolution. Stop when the first solution is found, or when the father sends a kill order */
+func equalityReasoningProblem(ep EqualityProblem, father_chan chan answerEP, last_applied_rule_index, last_applied_rule_type int) (bool, []Unif.Substitutions, bool) {
 	Glob.PrintDebug("ERP", Lib.MkLazy(func() string { return fmt.Sprintf("EP : %v", ep.ToString()) }))
 	substs_res := []Unif.Substitutions{}
 	unif_found := false
@@ -106,13 +110,16 @@ func equalityReasoningProblem(ep EqualityProblem, father_chan chan answerEP, las
 					Unif.SubstListToString(substs_res))
 			}),
 		)
-		return true, substs_res
+		return true, substs_res, false
 	} else {
 		Glob.PrintDebug("ERP", Lib.MkLazy(func() string { return "Stop case not found" }))
 	}
 
 	// Apply available rule
-	solution_found, solution_subst := manageRLRules(ep, father_chan, last_applied_rule_index, last_applied_rule_type)
+	solution_found, solution_subst, killed := manageRLRules(ep, father_chan, last_applied_rule_index, last_applied_rule_type)
+	if killed {
+		return false, nil, true
+	}
 	if solution_found {
 		unif_found = true
 		substs_res = append(substs_res, solution_subst...)
@@ -122,13 +129,13 @@ func equalityReasoningProblem(ep EqualityProblem, father_chan chan answerEP, las
 		"ERP",
 		Lib.MkLazy(func() string { return fmt.Sprintf("Send : %v !", Unif.SubstListToString(substs_res)) }),
 	)
-	return unif_found, substs_res
+	return unif_found, substs_res, false
 }
 
 /*** Launch rules ***/
 
-/* Manage application of right and left rules - // bewteen same type, sequential bewteen diffrent types */
-func manageRLRules(ep EqualityProblem, father chan answerEP, last_applied_rule_index, last_applied_rule_type int) (bool, []Unif.Substitutions) {
+/* Manage application of right and left rules - // bewteen same type, sequential bewteen diffrent types. The left rules are not applied after a kill order. */
+func manageRLRules(ep EqualityProblem, father chan answerEP, last_applied_rule_index, last_applied_rule_type int) (bool, []Unif.Substitutions, bool) {
 
 	// Compute right rule
 	Glob.PrintDebug("MRLR", Lib.MkLazy(func() string { return "Try apply right rules !" }))
@@ -143,11 +150,13 @@ func manageRLRules(ep EqualityProblem, father chan answerEP, last_applied_rule_i
 		}),
 	)
 	// apply right rules
-	res_right, subst_right := manageRule(ep, rules_to_apply, father)
+	res_right, subst_right, killed := manageRule(ep, rules_to_apply, father)
 
-	if res_right {
+	if killed {
+		return false, nil, true
+	} else if res_right {
 		// TODO : HERE - lost completeness
-		return true, subst_right
+		return true, subst_right, false
 	} else {
 		// Compute left rule
 		Glob.PrintDebug("MRLR", Lib.MkLazy(func() string { return "Try apply left rules !" }))
@@ -170,7 +179,7 @@ func manageRLRules(ep EqualityProblem, father chan answerEP, last_applied_rule_i
 	}
 }
 
-func manageRule(ep EqualityProblem, rsl ruleStructList, father chan answerEP) (bool, []Unif.Substitutions) {
+func manageRule(ep EqualityProblem, rsl ruleStructList, father chan answerEP) (bool, []Unif.Substitutions, bool) {
 	chan_tab := tryLaunchRule(ep, rsl)
 	return selectAnswerEP(chan_tab, father)
 }
@@ -192,8 +201,8 @@ func launchApplyRules(rs ruleStruct, ep EqualityProblem) chan answerEP {
 
 /*** Retrieve result ***/
 
-/* Wait for children to close. Return chen the fisrt child with a substitution answer */
-func selectAnswerEP(chan_tab [](chan answerEP), chan_parent chan answerEP) (bool, []Unif.Substitutions) {
+/* Wait for children to close. Return chen the fisrt child with a substitution answer, or whether the parent sent a kill order */
+func selectAnswerEP(chan_tab [](chan answerEP), chan_parent chan answerEP) (bool, []Unif.Substitutions, bool) {
 	// Instantiation
 	cases := makeCases(chan_tab, chan_parent)
 	hasAnswered := make([]bool, len(chan_tab)) // Everything to false
@@ -248,7 +257,7 @@ func selectAnswerEP(chan_tab [](chan answerEP), chan_parent chan answerEP) (bool
 	Glob.PrintDebug("SAEP", Lib.MkLazy(func() string { return "End of select" }))
 
 	selectCleanup(hasAnswered, chan_tab)
-	return answer_found, substs_res
+	return answer_found, substs_res, stop_found
 }
 
 /* Make cases : take a list of chan an make a select structure */
@@ -0,0 +1,688 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file checks a GS3 proof independently of the proof-search: every rule
+* application is verified syntactically, and every branch must be closed.
+**/
+
+package gs3
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+// Error raised on the first rule application of a proof that is not valid.
+type CheckError struct {
+	NodeId int
+	Msg    string
+}
+
+func (e CheckError) Error() string {
+	return fmt.Sprintf("node %d: %s", e.NodeId, e.Msg)
+}
+
+// Error raised on the first rule application of a proof that cannot be checked,
+// as the proof lacks the details of the step.
+type UncheckedError struct {
+	NodeId int
+	Msg    string
+}
+
+func (e UncheckedError) Error() string {
+	return fmt.Sprintf("node %d: %s", e.NodeId, e.Msg)
+}
+
+func (e UncheckedError) Unchecked() bool {
+	return true
+}
+
+// Translates the proof of the search in GS3 and checks it against the problem
+// given to the search. The only formulas that can be assumed are the problem
+// and the rewrite rules of the DMT.
+func CheckSearchProof(problem AST.Form, proof []Search.ProofStruct) error {
+	if len(proof) == 0 {
+		return CheckError{-1, "the proof is empty"}
+	}
+	if problem == nil {
+		return CheckError{proof[0].Node_id, "the problem of the proof is unknown"}
+	}
+
+	root := Core.UnminiscopeProblem(proof[0].GetFormula().GetForm())
+	if !isPartOfProblem(root, Core.UnminiscopeProblem(problem)) {
+		return CheckError{proof[0].Node_id, fmt.Sprintf("the root %s is not the problem", root.ToString())}
+	}
+
+	roots := AST.NewFormList(root)
+	if Glob.IsLoaded("dmt") {
+		roots.Append(dmt.GetRegisteredAxioms().Slice()...)
+	}
+	return CheckProof(MakeGS3Proof(proof), roots)
+}
+
+// The root of a proof is the problem, or, once the proof is minimized, the
+// conjunction of some of its formulas.
+func isPartOfProblem(root, problem AST.Form) bool {
+	if root.Equals(problem) {
+		return true
+	}
+
+	conjuncts := AST.NewFormList(problem)
+	if and, isAnd := problem.(AST.And); isAnd {
+		conjuncts = and.FormList
+	}
+	parts := AST.NewFormList(root)
+	if and, isAnd := root.(AST.And); isAnd {
+		parts = and.FormList
+	}
+
+	for _, part := range parts.Slice() {
+		if !conjuncts.Contains(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks a GS3 proof of the formulas of roots. The rule applications that
+// cannot be checked do not stop the check: if the rest of the proof is valid,
+// the first of them is returned as an UncheckedError.
+func CheckProof(proof *GS3Sequent, roots *AST.FormList) error {
+	var unchecked error
+	if err := checkSequent(proof, roots, &unchecked); err != nil {
+		return err
+	}
+	return unchecked
+}
+
+// Checks a sequent whose hypotheses must be among the available formulas, and
+// then its children.
+func checkSequent(seq *GS3Sequent, available *AST.FormList, unchecked *error) error {
+	if seq.IsEmpty() {
+		if len(seq.children) == 0 {
+			return CheckError{seq.nodeId, "the branch is not closed"}
+		}
+		for _, child := range seq.children {
+			if err := checkSequent(child, available, unchecked); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, h := range seq.hypotheses.Slice() {
+		if !available.Contains(h) && !h.Equals(AST.EmptyPredEq) {
+			return CheckError{seq.nodeId, fmt.Sprintf("the hypothesis %s has not been derived", h.ToString())}
+		}
+	}
+	if seq.appliedOn < 0 || seq.appliedOn >= seq.hypotheses.Len() {
+		return CheckError{seq.nodeId, "the rule is applied on a missing hypothesis"}
+	}
+
+	if err := checkRule(seq); err != nil {
+		if !errors.As(err, new(UncheckedError)) {
+			return err
+		}
+		if *unchecked == nil {
+			*unchecked = err
+		}
+	}
+
+	for i, child := range seq.children {
+		childAvailable := seq.hypotheses.Copy()
+		if i < len(seq.formsGenerated) {
+			childAvailable.Append(seq.formsGenerated[i].Slice()...)
+		}
+		if err := checkSequent(child, childAvailable, unchecked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkRule(seq *GS3Sequent) error {
+	target := seq.GetTargetForm()
+	switch {
+	case seq.rule == AX:
+		return checkClosure(seq, target)
+	case seq.rule == ARI:
+		return checkArithmeticClosure(seq, target)
+	case IsAlphaRule(seq.rule), IsBetaRule(seq.rule):
+		expected, ok := expectedResults(seq.rule, target)
+		if !ok {
+			return seq.errorf("the rule cannot be applied on %s", target.ToString())
+		}
+		return seq.checkResults(expected)
+	case IsGammaRule(seq.rule):
+		return checkQuantifierRule(seq, target, false)
+	case IsDeltaRule(seq.rule):
+		return checkQuantifierRule(seq, target, true)
+	case seq.rule == W:
+		return seq.checkArity(1)
+	case seq.rule == REWRITE:
+		if seq.rewriteWith < 0 || seq.rewriteWith >= seq.hypotheses.Len() {
+			return seq.errorf("the rewrite rule is not an hypothesis")
+		}
+		if err := seq.checkArity(1); err != nil {
+			return err
+		}
+		return checkRewrite(seq, target)
+	case seq.rule == CUT:
+		if err := seq.checkArity(2); err != nil {
+			return err
+		}
+		if len(seq.formsGenerated) != 2 || seq.formsGenerated[1].Len() != 1 {
+			return seq.errorf("the cut formula is missing")
+		}
+		cut := seq.formsGenerated[1].Get(0)
+		if !sameForms(AST.NewFormList(AST.MakerNot(cut)), seq.formsGenerated[0]) {
+			return seq.errorf("the children do not have a formula and its negation")
+		}
+		return nil
+	}
+	return seq.errorf("unknown rule")
+}
+
+// A branch is closed by complementary literals, by an obviously false
+// formula, or by a step of equality reasoning when the branch has equalities:
+// two of its literals are then complementary modulo the equalities.
+func checkClosure(seq *GS3Sequent, target AST.Form) error {
+	if len(seq.children) != 0 {
+		return seq.errorf("a closed branch has no children")
+	}
+
+	switch nf := target.(type) {
+	case AST.Bot:
+		return nil
+	case AST.Not:
+		if _, isTop := nf.GetForm().(AST.Top); isTop {
+			return nil
+		}
+		if IsReflexiveEquality(nf.GetForm()) || seq.hypotheses.Contains(nf.GetForm()) {
+			return nil
+		}
+	default:
+		if seq.hypotheses.Contains(AST.MakerNot(target)) {
+			return nil
+		}
+	}
+
+	for _, h := range seq.hypotheses.Slice() {
+		if isEquality(h) {
+			return checkEqualityClosure(seq, target)
+		}
+	}
+	return seq.errorf("the branch is not closed by %s", target.ToString())
+}
+
+// The equalities having metavariables may have been used by the equality
+// reasoning with several instances of their metavariables, which the proof does
+// not give: when the branch is not closed without them, the step is unchecked.
+func checkEqualityClosure(seq *GS3Sequent, target AST.Form) error {
+	if isClosedModuloEquality(seq.hypotheses.Slice()) {
+		return nil
+	}
+	for _, h := range seq.hypotheses.Slice() {
+		if isEquality(h) && !h.GetMetas().IsEmpty() {
+			return UncheckedError{
+				seq.nodeId,
+				fmt.Sprintf("%s: the equality reasoning may use several instances of %s", seq.ruleToString(seq.rule), h.ToString()),
+			}
+		}
+	}
+	return seq.errorf("the branch is not closed by %s, even modulo its equalities", target.ToString())
+}
+
+// Decides whether a formula and the literals of a branch are contradictory by
+// arithmetic reasoning. It is set by the arithmetic module.
+var ArithmeticClosure = func(target AST.Form, hypotheses []AST.Form) bool {
+	return false
+}
+
+// The arithmetic closures are checked again by the decision procedure of the
+// arithmetic module, on the hypotheses of the sequent.
+func checkArithmeticClosure(seq *GS3Sequent, target AST.Form) error {
+	if len(seq.children) != 0 {
+		return seq.errorf("a closed branch has no children")
+	}
+	if !ArithmeticClosure(target, seq.hypotheses.Slice()) {
+		return seq.errorf("the branch is not closed by arithmetic reasoning on %s", target.ToString())
+	}
+	return nil
+}
+
+// The formulas generated by an alpha or a beta rule, for each child.
+func expectedResults(rule Rule, target AST.Form) ([]*AST.FormList, bool) {
+	not, isNot := target.(AST.Not)
+	var inner AST.Form
+	if isNot {
+		inner = not.GetForm()
+	}
+
+	switch rule {
+	case NNOT:
+		if nnot, ok := inner.(AST.Not); ok {
+			return []*AST.FormList{AST.NewFormList(nnot.GetForm())}, true
+		}
+	case AND:
+		if and, ok := target.(AST.And); ok {
+			return []*AST.FormList{AST.NewFormList(and.FormList.Slice()...)}, true
+		}
+	case NOR:
+		if or, ok := inner.(AST.Or); ok {
+			return []*AST.FormList{AST.NewFormList(negateAll(or.FormList.Slice())...)}, true
+		}
+	case NIMP:
+		if imp, ok := inner.(AST.Imp); ok {
+			return []*AST.FormList{AST.NewFormList(imp.GetF1(), AST.MakerNot(imp.GetF2()))}, true
+		}
+	case OR:
+		if or, ok := target.(AST.Or); ok {
+			return oneFormPerChild(or.FormList.Slice()), true
+		}
+	case NAND:
+		if and, ok := inner.(AST.And); ok {
+			return oneFormPerChild(negateAll(and.FormList.Slice())), true
+		}
+	case IMP:
+		if imp, ok := target.(AST.Imp); ok {
+			return oneFormPerChild([]AST.Form{AST.MakerNot(imp.GetF1()), imp.GetF2()}), true
+		}
+	case EQU:
+		if equ, ok := target.(AST.Equ); ok {
+			return []*AST.FormList{
+				AST.NewFormList(AST.MakerNot(equ.GetF1()), AST.MakerNot(equ.GetF2())),
+				AST.NewFormList(equ.GetF1(), equ.GetF2()),
+			}, true
+		}
+	case NEQU:
+		if equ, ok := inner.(AST.Equ); ok {
+			return []*AST.FormList{
+				AST.NewFormList(AST.MakerNot(equ.GetF1()), equ.GetF2()),
+				AST.NewFormList(equ.GetF1(), AST.MakerNot(equ.GetF2())),
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// Checks that a gamma or a delta rule instantiates the first variable of its
+// target with its term: a well-typed one for a gamma rule, and a fresh symbol
+// for a delta rule.
+func checkQuantifierRule(seq *GS3Sequent, target AST.Form, isDelta bool) error {
+	v, _, ok := openQuantifier(seq.rule, target)
+	if !ok {
+		return seq.errorf("the rule cannot be applied on %s", target.ToString())
+	}
+
+	term := seq.termGenerated
+	if term != nil {
+		if isDelta {
+			fun, isFun := term.(AST.Fun)
+			if !isFun {
+				return seq.errorf("the witness %s is not a symbol", term.ToString())
+			}
+			for _, h := range seq.hypotheses.Slice() {
+				if formContainsSymbol(h, fun.GetID()) {
+					return seq.errorf("the witness %s is not fresh", term.ToString())
+				}
+			}
+		} else if !isWellTyped(term, v) {
+			return seq.errorf("the term %s does not have the type of %s", term.ToString(), v.GetName())
+		}
+	}
+	return seq.checkResults([]*AST.FormList{AST.NewFormList(instantiate(seq.rule, target, term))})
+}
+
+// Returns the instance of the first variable of a quantified formula, or of the
+// negation of one, with the term. The variable is kept when there is no term.
+func instantiate(rule Rule, target AST.Form, term AST.Term) AST.Form {
+	v, body, _ := openQuantifier(rule, target)
+	if term != nil {
+		body, _ = body.ReplaceTermByTerm(v, term)
+	}
+	if _, isNot := target.(AST.Not); isNot {
+		body = AST.MakerNot(body)
+	}
+	return body
+}
+
+// Returns the first variable of a quantified formula, and the formula without
+// this variable.
+func openQuantifier(rule Rule, target AST.Form) (AST.Var, AST.Form, bool) {
+	form := target
+	if rule == NEX || rule == NALL {
+		not, isNot := target.(AST.Not)
+		if !isNot {
+			return AST.Var{}, nil, false
+		}
+		form = not.GetForm()
+	}
+
+	var varList []AST.Var
+	switch nf := form.(type) {
+	case AST.All:
+		if rule != ALL && rule != NALL {
+			return AST.Var{}, nil, false
+		}
+		varList = nf.GetVarList()
+	case AST.Ex:
+		if rule != EX && rule != NEX {
+			return AST.Var{}, nil, false
+		}
+		varList = nf.GetVarList()
+	default:
+		return AST.Var{}, nil, false
+	}
+
+	if len(varList) == 0 {
+		return AST.Var{}, nil, false
+	}
+	return varList[0], getNextFormula(form), true
+}
+
+func (seq *GS3Sequent) checkResults(expected []*AST.FormList) error {
+	if err := seq.checkArity(len(expected)); err != nil {
+		return err
+	}
+	for i, forms := range expected {
+		if i >= len(seq.formsGenerated) || !sameForms(forms, seq.formsGenerated[i]) {
+			return seq.errorf("the child %d should have %s", i, forms.ToString())
+		}
+	}
+	return nil
+}
+
+func (seq *GS3Sequent) checkArity(arity int) error {
+	if len(seq.children) != arity {
+		return seq.errorf("the rule has %d children instead of %d", len(seq.children), arity)
+	}
+	return nil
+}
+
+func (seq *GS3Sequent) errorf(format string, args ...any) error {
+	return CheckError{seq.nodeId, fmt.Sprintf("%s: ", seq.ruleToString(seq.rule)) + fmt.Sprintf(format, args...)}
+}
+
+// Compares two lists of formulas regardless of their order.
+func sameForms(expected, actual *AST.FormList) bool {
+	if expected.Len() != actual.Len() {
+		return false
+	}
+	for _, f := range expected.Slice() {
+		if !actual.Contains(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func oneFormPerChild(forms []AST.Form) []*AST.FormList {
+	return Glob.MapTo(forms, func(_ int, f AST.Form) *AST.FormList { return AST.NewFormList(f) })
+}
+
+func negateAll(forms []AST.Form) []AST.Form {
+	return Glob.MapTo(forms, func(_ int, f AST.Form) AST.Form { return AST.MakerNot(f) })
+}
+
+func isEquality(f AST.Form) bool {
+	if not, isNot := f.(AST.Not); isNot {
+		f = not.GetForm()
+	}
+	p, isPred := f.(AST.Pred)
+	return isPred && p.GetID().Equals(AST.Id_eq) && p.GetArgs().Len() == 2
+}
+
+// Whether the formula is an equality between two equal terms.
+func IsReflexiveEquality(f AST.Form) bool {
+	if p, isPred := f.(AST.Pred); isPred && p.GetID().Equals(AST.Id_eq) {
+		args := p.GetArgs().GetSlice()
+		return len(args) == 2 && args[0].Equals(args[1])
+	}
+	return false
+}
+
+// The type of the term is only compared with the one of the variable when
+// both of them are known.
+func isWellTyped(term AST.Term, v AST.Var) bool {
+	varType, isHint := v.GetTypeApp().(AST.TypeHint)
+	if !isHint {
+		return true
+	}
+
+	var termType AST.TypeScheme
+	switch t := term.(type) {
+	case AST.Meta:
+		termType = t.GetTypeHint()
+	case AST.Fun:
+		termType = t.GetTypeHint()
+		if apps, found := AST.GetGlobalContext()[t.GetName()]; found {
+			if len(apps) != 1 {
+				return true
+			}
+			termType = apps[0].App
+		}
+	default:
+		return true
+	}
+
+	if termType == nil {
+		return true
+	}
+	outType, isHint := AST.GetOutType(termType).(AST.TypeHint)
+	return !isHint || outType.Equals(varType)
+}
+
+func formContainsSymbol(form AST.Form, id AST.Id) bool {
+	switch nf := form.(type) {
+	case AST.Not:
+		return formContainsSymbol(nf.GetForm(), id)
+	case AST.And:
+		return formsContainSymbol(nf.FormList.Slice(), id)
+	case AST.Or:
+		return formsContainSymbol(nf.FormList.Slice(), id)
+	case AST.Imp:
+		return formsContainSymbol([]AST.Form{nf.GetF1(), nf.GetF2()}, id)
+	case AST.Equ:
+		return formsContainSymbol([]AST.Form{nf.GetF1(), nf.GetF2()}, id)
+	case AST.All:
+		return formContainsSymbol(nf.GetForm(), id)
+	case AST.Ex:
+		return formContainsSymbol(nf.GetForm(), id)
+	case AST.AllType:
+		return formContainsSymbol(nf.GetForm(), id)
+	case AST.Pred:
+		return termsContainSymbol(nf.GetArgs().GetSlice(), id)
+	}
+	return false
+}
+
+func formsContainSymbol(forms []AST.Form, id AST.Id) bool {
+	for _, f := range forms {
+		if formContainsSymbol(f, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func termsContainSymbol(terms []AST.Term, id AST.Id) bool {
+	for _, t := range terms {
+		if fun, isFun := t.(AST.Fun); isFun && (fun.GetID().Equals(id) || termsContainSymbol(fun.GetArgs().GetSlice(), id)) {
+			return true
+		}
+	}
+	return false
+}
+
+// A rewrite step adds to the branch the formula that a rewrite rule, i.e., an
+// axiom of the DMT, gives for its target once instantiated so that one of its
+// atoms is the atom of the target.
+func checkRewrite(seq *GS3Sequent, target AST.Form) error {
+	vars, body := openUniversals(seq.GetRewriteWith())
+	targetAtom, _, isLit := literalOf(target)
+	if !isLit {
+		return seq.errorf("the rewritten formula %s is not a literal", target.ToString())
+	}
+
+	for _, side := range rewriteSides(body) {
+		atom, _, isLit := literalOf(side)
+		if !isLit {
+			continue
+		}
+		subst := map[int]AST.Term{}
+		if !matchTerms(atom.GetArgs().GetSlice(), targetAtom.GetArgs().GetSlice(), subst) || !atom.GetID().Equals(targetAtom.GetID()) {
+			continue
+		}
+
+		instance := body
+		for _, v := range vars {
+			if t, found := subst[v.GetIndex()]; found {
+				instance, _ = instance.ReplaceTermByTerm(v, t)
+			}
+		}
+		if isRewriteOf(instance, target, seq.formsGenerated[0]) {
+			return nil
+		}
+	}
+	return seq.errorf("the result is not given by the rewrite rule %s", seq.GetRewriteWith().ToString())
+}
+
+func openUniversals(form AST.Form) ([]AST.Var, AST.Form) {
+	vars := []AST.Var{}
+	for {
+		all, isAll := form.(AST.All)
+		if !isAll {
+			return vars, form
+		}
+		vars = append(vars, all.GetVarList()...)
+		form = all.GetForm()
+	}
+}
+
+// The formulas of a rewrite rule that may be rewritten.
+func rewriteSides(body AST.Form) []AST.Form {
+	switch nf := body.(type) {
+	case AST.Equ:
+		return []AST.Form{nf.GetF1(), nf.GetF2()}
+	case AST.Imp:
+		return []AST.Form{nf.GetF1(), nf.GetF2()}
+	}
+	return []AST.Form{body}
+}
+
+// Binds the variables of the patterns so that they become the terms.
+func matchTerms(patterns, terms []AST.Term, subst map[int]AST.Term) bool {
+	if len(patterns) != len(terms) {
+		return false
+	}
+	for i, pattern := range patterns {
+		switch p := pattern.(type) {
+		case AST.Var:
+			if bound, found := subst[p.GetIndex()]; found && !bound.Equals(terms[i]) {
+				return false
+			}
+			subst[p.GetIndex()] = terms[i]
+		case AST.Fun:
+			fun, isFun := terms[i].(AST.Fun)
+			if !isFun || !fun.GetID().Equals(p.GetID()) || !matchTerms(p.GetArgs().GetSlice(), fun.GetArgs().GetSlice(), subst) {
+				return false
+			}
+		default:
+			if !pattern.Equals(terms[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Whether the results follow from the target and the instance of the rewrite
+// rule: an equivalence rewrites each side into the other one, with the same
+// sign, and an implication rewrites its hypothesis into its conclusion, or the
+// negation of its conclusion into the negation of its hypothesis. An atomic
+// rule rewrites its instance into $true, and its negation into $false.
+func isRewriteOf(instance, target AST.Form, results *AST.FormList) bool {
+	pairs := [][2]AST.Form{}
+	switch nf := instance.(type) {
+	case AST.Equ:
+		l, r := nf.GetF1(), nf.GetF2()
+		pairs = [][2]AST.Form{{l, r}, {r, l}, {AST.MakerNot(l), AST.MakerNot(r)}, {AST.MakerNot(r), AST.MakerNot(l)}}
+	case AST.Imp:
+		l, r := nf.GetF1(), nf.GetF2()
+		pairs = [][2]AST.Form{{l, r}, {AST.MakerNot(r), AST.MakerNot(l)}}
+	default:
+		pairs = [][2]AST.Form{{instance, AST.MakerTop()}, {AST.MakerNot(instance), AST.MakerBot()}}
+	}
+
+	for _, result := range results.Slice() {
+		found := false
+		for _, pair := range pairs {
+			if sameLiteral(pair[0], target) && sameLiteral(pair[1], result) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Compares two formulas up to double negations, and $false with ~$true.
+func sameLiteral(f1, f2 AST.Form) bool {
+	return normalizeNegations(f1).Equals(normalizeNegations(f2))
+}
+
+func normalizeNegations(form AST.Form) AST.Form {
+	not, isNot := form.(AST.Not)
+	if !isNot {
+		return form
+	}
+	switch inner := not.GetForm().(type) {
+	case AST.Not:
+		return normalizeNegations(inner.GetForm())
+	case AST.Top:
+		return AST.MakerBot()
+	case AST.Bot:
+		return AST.MakerTop()
+	}
+	return form
+}
@@ -0,0 +1,144 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file contains the tests of the proof checker.
+**/
+
+package gs3
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+func TestMain(m *testing.M) {
+	AST.Init()
+	m.Run()
+}
+
+func cst(symbol string) AST.Term {
+	return AST.MakerConst(AST.MakerId(symbol))
+}
+
+func app(symbol string, args ...AST.Term) AST.Term {
+	return AST.MakerFun(AST.MakerId(symbol), Lib.MkListV(args...), []AST.TypeApp{})
+}
+
+func pred(symbol string, args ...AST.Term) AST.Form {
+	return AST.MakerPred(AST.MakerId(symbol), Lib.MkListV(args...), []AST.TypeApp{})
+}
+
+func eq(t1, t2 AST.Term) AST.Form {
+	return AST.MakerPred(AST.Id_eq, Lib.MkListV(t1, t2), []AST.TypeApp{})
+}
+
+// A branch closed by the rule AX applied on its first hypothesis.
+func closure(hypotheses ...AST.Form) *GS3Sequent {
+	seq := MakeNewSequent()
+	seq.hypotheses = AST.NewFormList(hypotheses...)
+	seq.rule = AX
+	return seq
+}
+
+func TestCheckEqualityClosure(t *testing.T) {
+	a, b, c := cst("a"), cst("b"), cst("c")
+	tests := []struct {
+		name       string
+		hypotheses []AST.Form
+		closed     bool
+	}{
+		{"congruent literals", []AST.Form{eq(a, b), pred("p", a), AST.MakerNot(pred("p", b))}, true},
+		{"congruent terms", []AST.Form{eq(a, b), AST.MakerNot(eq(app("f", a), app("f", b)))}, true},
+		{"transitivity", []AST.Form{eq(a, b), eq(b, c), AST.MakerNot(pred("p", c)), pred("p", a)}, true},
+		{"open branch", []AST.Form{eq(a, b), pred("p", a), AST.MakerNot(pred("p", c))}, false},
+		{"other symbol", []AST.Form{eq(a, b), AST.MakerNot(eq(app("f", a), app("g", b)))}, false},
+	}
+
+	for _, test := range tests {
+		hypotheses := append(test.hypotheses, AST.EmptyPredEq)
+		err := CheckProof(closure(hypotheses...), AST.NewFormList(test.hypotheses...))
+		if test.closed && err != nil {
+			t.Errorf("%s: expected a valid closure, got %v", test.name, err)
+		}
+		if !test.closed {
+			var checkErr CheckError
+			if !errors.As(err, &checkErr) {
+				t.Errorf("%s: expected a CheckError, got %v", test.name, err)
+			}
+		}
+	}
+}
+
+func TestCheckEqualityClosureWithMetas(t *testing.T) {
+	x := AST.MakerMeta("X", -1)
+	hypotheses := []AST.Form{eq(app("f", x), app("g", x)), pred("p", app("f", cst("a"))), AST.MakerNot(pred("p", cst("c")))}
+
+	err := CheckProof(closure(hypotheses...), AST.NewFormList(hypotheses...))
+	var unchecked UncheckedError
+	if !errors.As(err, &unchecked) {
+		t.Errorf("expected an UncheckedError, got %v", err)
+	}
+}
+
+// A step that cannot be checked does not hide the invalid steps that follow it.
+func TestCheckAfterUncheckedStep(t *testing.T) {
+	x := AST.MakerMeta("X", -1)
+	q, r := pred("q"), pred("r")
+	hypotheses := []AST.Form{
+		eq(app("f", x), app("g", x)), pred("p", app("f", cst("a"))), AST.MakerNot(pred("p", cst("c"))),
+		AST.MakerOr(AST.NewFormList(q, r)), AST.MakerNot(q), AST.MakerNot(r),
+	}
+
+	seq := MakeNewSequent()
+	seq.hypotheses = AST.NewFormList(hypotheses...)
+	seq.rule = OR
+	seq.appliedOn = 3
+	seq.formsGenerated = []*AST.FormList{AST.NewFormList(q), AST.NewFormList(r)}
+	seq.children = []*GS3Sequent{closure(hypotheses[:3]...), closure(r, AST.MakerNot(q))}
+	seq.children[1].nodeId = 2
+
+	var checkErr CheckError
+	if err := CheckProof(seq, AST.NewFormList(hypotheses...)); !errors.As(err, &checkErr) || checkErr.NodeId != 2 {
+		t.Errorf("expected a CheckError at node 2, got %v", err)
+	}
+
+	// Without the invalid branch, the unchecked step is reported.
+	seq.children[1] = closure(r, AST.MakerNot(r))
+	var unchecked UncheckedError
+	if err := CheckProof(seq, AST.NewFormList(hypotheses...)); !errors.As(err, &unchecked) {
+		t.Errorf("expected an UncheckedError, got %v", err)
+	}
+}
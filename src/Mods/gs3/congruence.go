@@ -0,0 +1,180 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file checks the closures obtained by equality reasoning: the literals of
+* the branch are ground once the proof is found (the metavariables left are
+* constants), so the branch is closed modulo its equalities if the congruence
+* closure of the equalities makes two of its literals complementary.
+**/
+
+package gs3
+
+import (
+	"github.com/GoelandProver/Goeland/AST"
+)
+
+// The classes of the terms of a branch, by the string of the terms.
+type congruence struct {
+	parent map[string]string
+	terms  map[string]AST.Term
+}
+
+func makeCongruence(literals []AST.Form) *congruence {
+	c := &congruence{map[string]string{}, map[string]AST.Term{}}
+	for _, lit := range literals {
+		if pred, _, isLit := literalOf(lit); isLit {
+			for _, arg := range pred.GetArgs().GetSlice() {
+				c.add(arg)
+			}
+		}
+	}
+	for _, lit := range literals {
+		if pred, positive, isLit := literalOf(lit); isLit && positive && isEquality(pred) {
+			args := pred.GetArgs().GetSlice()
+			c.union(args[0], args[1])
+		}
+	}
+	c.close()
+	return c
+}
+
+func (c *congruence) add(t AST.Term) {
+	key := t.ToString()
+	if _, found := c.terms[key]; found {
+		return
+	}
+	c.terms[key] = t
+	c.parent[key] = key
+	if fun, isFun := t.(AST.Fun); isFun {
+		for _, arg := range fun.GetArgs().GetSlice() {
+			c.add(arg)
+		}
+	}
+}
+
+func (c *congruence) find(key string) string {
+	for c.parent[key] != key {
+		c.parent[key] = c.parent[c.parent[key]]
+		key = c.parent[key]
+	}
+	return key
+}
+
+func (c *congruence) union(t1, t2 AST.Term) bool {
+	r1, r2 := c.find(t1.ToString()), c.find(t2.ToString())
+	if r1 == r2 {
+		return false
+	}
+	c.parent[r1] = r2
+	return true
+}
+
+func (c *congruence) equal(t1, t2 AST.Term) bool {
+	c.add(t1)
+	c.add(t2)
+	return c.find(t1.ToString()) == c.find(t2.ToString())
+}
+
+func (c *congruence) equalArgs(args1, args2 []AST.Term) bool {
+	if len(args1) != len(args2) {
+		return false
+	}
+	for i := range args1 {
+		if !c.equal(args1[i], args2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merges the classes of the applications of a symbol to equal arguments,
+// until nothing changes.
+func (c *congruence) close() {
+	funs := []AST.Fun{}
+	for _, t := range c.terms {
+		if fun, isFun := t.(AST.Fun); isFun {
+			funs = append(funs, fun)
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i := range funs {
+			for j := i + 1; j < len(funs); j++ {
+				if funs[i].GetID().Equals(funs[j].GetID()) &&
+					c.equalArgs(funs[i].GetArgs().GetSlice(), funs[j].GetArgs().GetSlice()) &&
+					c.union(funs[i], funs[j]) {
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// Whether two literals of the branch are complementary modulo the equalities,
+// or a disequality is between equal terms.
+func isClosedModuloEquality(hypotheses []AST.Form) bool {
+	c := makeCongruence(hypotheses)
+
+	for i, h := range hypotheses {
+		pred, positive, isLit := literalOf(h)
+		if !isLit {
+			continue
+		}
+		args := pred.GetArgs().GetSlice()
+		if !positive && isEquality(pred) && c.equal(args[0], args[1]) {
+			return true
+		}
+		if !positive || isEquality(pred) {
+			continue
+		}
+		for j, other := range hypotheses {
+			otherPred, otherPositive, isOtherLit := literalOf(other)
+			if i != j && isOtherLit && !otherPositive && otherPred.GetID().Equals(pred.GetID()) &&
+				c.equalArgs(args, otherPred.GetArgs().GetSlice()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns the atom of a literal and its sign.
+func literalOf(form AST.Form) (AST.Pred, bool, bool) {
+	positive := true
+	if not, isNot := form.(AST.Not); isNot {
+		form, positive = not.GetForm(), false
+	}
+	pred, isPred := form.(AST.Pred)
+	return pred, positive, isPred
+}
@@ -0,0 +1,839 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file proves the miniscoping of the problem (see Core/miniscoping.go)
+* with the rules of GS3. The miniscoped problem is cut: the proof of the search
+* refutes it, and the problem is refuted with its negation.
+*
+* The refutation follows the miniscoping. Each quantifier pushed inside its
+* formula cuts the formulas it is pushed on, e.g., ∀x.A and ∀x.B for ∀x.(A ∧ B),
+* and the formulas that only differ by the names of their bound variables are
+* decomposed together.
+**/
+
+package gs3
+
+import (
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Core/Sko"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+// A branch of the refutation, whose rules build the sequents with its
+// hypotheses.
+type refutation struct {
+	hypotheses *AST.FormList
+	nodeId     int
+}
+
+// When the problem has been miniscoped, the proof splits the original problem
+// and cuts the miniscoped one, which the proof of the search refutes.
+func addMiniscopingStep(sequent *GS3Sequent, root Search.ProofStruct) *GS3Sequent {
+	form := root.GetFormula().GetForm()
+	original := Core.UnminiscopeProblem(form)
+	if original.Equals(form) {
+		return sequent
+	}
+
+	hypotheses := AST.NewFormList()
+	if Glob.IsLoaded("dmt") {
+		hypotheses.Append(dmt.GetRegisteredAxioms().Slice()...)
+	}
+	hypotheses.Append(original)
+
+	cutProblem := func(r refutation, conjuncts []AST.Form) *GS3Sequent {
+		return r.cut(
+			original,
+			form,
+			func(r refutation) *GS3Sequent { return r.refuteProblem(conjuncts, form) },
+			func(refutation) *GS3Sequent { return sequent },
+		)
+	}
+	r := refutation{hypotheses, root.Node_id}
+	if _, isAnd := original.(AST.And); isAnd {
+		return r.alpha(AND, original, cutProblem)
+	}
+	return cutProblem(r, []AST.Form{original})
+}
+
+// Refutes the formulas of the problem and the negation of the miniscoped
+// problem, whose conjunction may be flattened: their miniscoping is cut.
+func (r refutation) refuteProblem(conjuncts []AST.Form, form AST.Form) *GS3Sequent {
+	miniscoped := Glob.MapTo(conjuncts, func(_ int, f AST.Form) AST.Form { return Core.Miniscope(f) })
+	if len(conjuncts) == 1 {
+		return r.cut(
+			conjuncts[0],
+			miniscoped[0],
+			func(r refutation) *GS3Sequent { return r.refuteMiniscoping(conjuncts[0], miniscoped[0], true) },
+			func(r refutation) *GS3Sequent { return r.refuteAlpha(miniscoped[0], form) },
+		)
+	}
+
+	conjunction := AST.MakerAnd(AST.NewFormList(miniscoped...))
+	return r.cut(
+		conjuncts[0],
+		conjunction,
+		func(r refutation) *GS3Sequent {
+			return r.beta(NAND, AST.MakerNot(conjunction), func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+				return r.refuteMiniscoping(conjuncts[i], miniscoped[i], true)
+			})
+		},
+		func(r refutation) *GS3Sequent { return r.refuteAlpha(conjunction, form) },
+	)
+}
+
+// Refutes a formula and the negation of its miniscoping when forward, and the
+// miniscoping and the negation of the formula otherwise. The miniscoping may
+// differ from the one of Core.Miniscope by the names of its bound variables.
+func (r refutation) refuteMiniscoping(original, miniscoped AST.Form, forward bool) *GS3Sequent {
+	pos, neg := original, miniscoped
+	if !forward {
+		pos, neg = miniscoped, original
+	}
+	if Core.Miniscope(original).Equals(original) {
+		return r.refuteAlpha(pos, neg)
+	}
+
+	switch original.(type) {
+	case AST.All, AST.Ex:
+		return r.refuteQuantifier(original, miniscoped, forward)
+	}
+	return r.lockstep(pos, neg, false, refuteMiniscopings(forward))
+}
+
+// Refutes the children given by lockstep, whose original one is positive when
+// forward and not swapped, or when swapped and not forward.
+func refuteMiniscopings(forward bool) func(refutation, AST.Form, AST.Form, bool) *GS3Sequent {
+	return func(r refutation, pos, neg AST.Form, swapped bool) *GS3Sequent {
+		if forward != swapped {
+			return r.refuteMiniscoping(pos, neg, true)
+		}
+		return r.refuteMiniscoping(neg, pos, false)
+	}
+}
+
+// The miniscoping of Qx.A pushes Qx inside the miniscoping of A: the formula
+// Qx.A' where A' is the miniscoping of A is cut, unless A is already
+// miniscoped.
+func (r refutation) refuteQuantifier(original, miniscoped AST.Form, forward bool) *GS3Sequent {
+	x, rest, _ := openQuantifier(positiveQuantifierRule(original), original)
+	inner := Core.Miniscope(rest)
+	if inner.Equals(rest) {
+		return r.refutePush(original, x, rest, miniscoped, forward)
+	}
+
+	_, isAll := original.(AST.All)
+	quantified := quantify(isAll, x, inner)
+	if forward {
+		return r.cut(
+			original,
+			quantified,
+			func(r refutation) *GS3Sequent {
+				return r.lockstep(original, quantified, false, refuteMiniscopings(true))
+			},
+			func(r refutation) *GS3Sequent { return r.refutePush(quantified, x, inner, miniscoped, true) },
+		)
+	}
+	return r.cut(
+		AST.MakerNot(original),
+		quantified,
+		func(r refutation) *GS3Sequent { return r.refutePush(quantified, x, inner, miniscoped, false) },
+		func(r refutation) *GS3Sequent {
+			return r.lockstep(quantified, original, false, refuteMiniscopings(false))
+		},
+	)
+}
+
+// Refutes the formula quantified, Qx.form, and the negation of pushed, the
+// result of pushing Qx inside form, when forward, and pushed and the negation
+// of quantified otherwise. The formula form is miniscoped.
+func (r refutation) refutePush(quantified AST.Form, x AST.Var, form, pushed AST.Form, forward bool) *GS3Sequent {
+	_, isAll := quantified.(AST.All)
+	if !occurs(x, form) {
+		if forward {
+			return r.open(quantified, func(r refutation, _ AST.Term, instance AST.Form) *GS3Sequent {
+				return r.refuteAlpha(instance, pushed)
+			})
+		}
+		return r.open(AST.MakerNot(quantified), func(r refutation, _ AST.Term, instance AST.Form) *GS3Sequent {
+			return r.refuteAlpha(pushed, negated(instance))
+		})
+	}
+
+	switch nf := form.(type) {
+	case AST.And, AST.Or:
+		_, isAnd := form.(AST.And)
+		if isAnd == isAll {
+			return r.refuteDistribution(quantified, x, form, pushed, forward)
+		}
+		for _, f := range form.GetChildFormulas().Slice() {
+			if !occurs(x, f) {
+				return r.refuteSplit(quantified, x, form, pushed, forward)
+			}
+		}
+	case AST.Imp:
+		switch {
+		case !occurs(x, nf.GetF1()):
+			return r.refuteConclusion(quantified, x, nf, pushed, forward)
+		case !occurs(x, nf.GetF2()):
+			return r.refutePremise(quantified, x, nf, pushed, forward)
+		}
+	}
+
+	if forward {
+		return r.refuteAlpha(quantified, pushed)
+	}
+	return r.refuteAlpha(pushed, quantified)
+}
+
+// ∀x.(A1 ∧ ... ∧ An) and ∀x.A1 ∧ ... ∧ ∀x.An, or ∃x.(A1 ∨ ... ∨ An) and
+// ∃x.A1 ∨ ... ∨ ∃x.An: each Qx.Ai is cut, and Qx is pushed inside Ai.
+func (r refutation) refuteDistribution(quantified AST.Form, x AST.Var, form, pushed AST.Form, forward bool) *GS3Sequent {
+	held := heldFormula(quantified, forward)
+	_, isAll := quantified.(AST.All)
+	forms := form.GetChildFormulas().Slice()
+	results := pushed.GetChildFormulas().Slice()
+	cutPart := func(r refutation, i int, negative, positive func(refutation) *GS3Sequent) *GS3Sequent {
+		return r.cut(held, quantify(isAll, x, forms[i]), negative, positive)
+	}
+	part := func(i int) AST.Form { return quantify(isAll, x, forms[i]) }
+
+	switch {
+	case isAll && forward:
+		return r.beta(NAND, AST.MakerNot(pushed), func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+			return cutPart(r, i,
+				func(r refutation) *GS3Sequent {
+					return r.pair(quantified, part(i), func(r refutation, instance, _ AST.Form) *GS3Sequent {
+						return r.alpha(AND, instance, func(r refutation, instances []AST.Form) *GS3Sequent {
+							return r.close(instances[i])
+						})
+					})
+				},
+				func(r refutation) *GS3Sequent { return r.refutePush(part(i), x, forms[i], results[i], true) },
+			)
+		})
+	case isAll:
+		return r.alpha(AND, pushed, func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.open(AST.MakerNot(quantified), func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+				return r.beta(NAND, instance, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+					return cutPart(r, i,
+						func(r refutation) *GS3Sequent { return r.refutePush(part(i), x, forms[i], results[i], false) },
+						func(r refutation) *GS3Sequent {
+							return r.gamma(part(i), c, func(r refutation, instance AST.Form) *GS3Sequent { return r.close(instance) })
+						},
+					)
+				})
+			})
+		})
+	case forward:
+		return r.alpha(NOR, AST.MakerNot(pushed), func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.open(quantified, func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+				return r.beta(OR, instance, func(r refutation, i int, instances []AST.Form) *GS3Sequent {
+					return cutPart(r, i,
+						func(r refutation) *GS3Sequent {
+							return r.gamma(AST.MakerNot(part(i)), c, func(r refutation, _ AST.Form) *GS3Sequent {
+								return r.close(instances[0])
+							})
+						},
+						func(r refutation) *GS3Sequent { return r.refutePush(part(i), x, forms[i], results[i], true) },
+					)
+				})
+			})
+		})
+	}
+	return r.beta(OR, pushed, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+		return cutPart(r, i,
+			func(r refutation) *GS3Sequent { return r.refutePush(part(i), x, forms[i], results[i], false) },
+			func(r refutation) *GS3Sequent {
+				return r.pair(part(i), quantified, func(r refutation, instance, negInstance AST.Form) *GS3Sequent {
+					return r.alpha(NOR, AST.MakerNot(negInstance), func(r refutation, _ []AST.Form) *GS3Sequent {
+						return r.close(instance)
+					})
+				})
+			},
+		)
+	})
+}
+
+// ∀x.(A1 ∨ ... ∨ An) and B1 ∨ ... ∨ Bk ∨ ∀x.(C1 ∨ ... ∨ Cm), where x only
+// occurs in the formulas C, or ∃x.(A1 ∧ ... ∧ An) and B1 ∧ ... ∧ Bk ∧ ∃x.(C1
+// ∧ ... ∧ Cm): Qx.(C1 ∘ ... ∘ Cm) is cut, and Qx is pushed inside it.
+func (r refutation) refuteSplit(quantified AST.Form, x AST.Var, form, pushed AST.Form, forward bool) *GS3Sequent {
+	held := heldFormula(quantified, forward)
+	_, isAll := quantified.(AST.All)
+	forms := form.GetChildFormulas().Slice()
+	results := pushed.GetChildFormulas().Slice()
+
+	// The index in the results of each formula without x, and the formulas with x.
+	kept, occurring := make(map[int]int), []AST.Form{}
+	for i, f := range forms {
+		if occurs(x, f) {
+			occurring = append(occurring, f)
+		} else {
+			kept[i] = len(kept)
+		}
+	}
+	rest := occurring[0]
+	if len(occurring) > 1 {
+		if isAll {
+			rest = AST.MakerOr(AST.NewFormList(occurring...))
+		} else {
+			rest = AST.MakerAnd(AST.NewFormList(occurring...))
+		}
+	}
+	part := quantify(isAll, x, rest)
+	pushedPart := results[len(results)-1]
+
+	// Refutes the formulas of a branch with the formula of index i of form,
+	// and either the negations of all of the formulas of form or the negation
+	// of the formula of index i.
+	refuteForm := func(r refutation, i int, f AST.Form, formIsPositive bool) *GS3Sequent {
+		if j, isKept := kept[i]; isKept {
+			if formIsPositive {
+				return r.refuteAlpha(f, results[j])
+			}
+			return r.refuteAlpha(results[j], f)
+		}
+		return r.close(f)
+	}
+	// Decomposes the instance of the cut formula in the branches of form.
+	decomposeRest := func(r refutation, rule Rule, instance AST.Form, k func(refutation) *GS3Sequent) *GS3Sequent {
+		if len(occurring) == 1 {
+			return k(r)
+		}
+		return r.alpha(rule, instance, func(r refutation, _ []AST.Form) *GS3Sequent { return k(r) })
+	}
+
+	switch {
+	case isAll && forward:
+		return r.alpha(NOR, AST.MakerNot(pushed), func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.cut(held, part,
+				func(r refutation) *GS3Sequent {
+					return r.pair(quantified, part, func(r refutation, instance, negInstance AST.Form) *GS3Sequent {
+						return decomposeRest(r, NOR, AST.MakerNot(negInstance), func(r refutation) *GS3Sequent {
+							return r.beta(OR, instance, func(r refutation, i int, instances []AST.Form) *GS3Sequent {
+								return refuteForm(r, i, instances[0], true)
+							})
+						})
+					})
+				},
+				func(r refutation) *GS3Sequent { return r.refutePush(part, x, rest, pushedPart, true) },
+			)
+		})
+	case isAll:
+		return r.open(AST.MakerNot(quantified), func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+			return r.alpha(NOR, instance, func(r refutation, _ []AST.Form) *GS3Sequent {
+				return r.beta(OR, pushed, func(r refutation, j int, _ []AST.Form) *GS3Sequent {
+					if j < len(kept) {
+						return r.refuteAlpha(results[j], keptForm(forms, kept, j))
+					}
+					return r.cut(held, part,
+						func(r refutation) *GS3Sequent { return r.refutePush(part, x, rest, pushedPart, false) },
+						func(r refutation) *GS3Sequent {
+							return r.gamma(part, c, func(r refutation, instance AST.Form) *GS3Sequent {
+								if len(occurring) == 1 {
+									return r.close(instance)
+								}
+								return r.beta(OR, instance, func(r refutation, _ int, instances []AST.Form) *GS3Sequent {
+									return r.close(instances[0])
+								})
+							})
+						},
+					)
+				})
+			})
+		})
+	case forward:
+		return r.open(quantified, func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+			return r.alpha(AND, instance, func(r refutation, _ []AST.Form) *GS3Sequent {
+				return r.beta(NAND, AST.MakerNot(pushed), func(r refutation, j int, _ []AST.Form) *GS3Sequent {
+					if j < len(kept) {
+						return r.refuteAlpha(keptForm(forms, kept, j), results[j])
+					}
+					return r.cut(held, part,
+						func(r refutation) *GS3Sequent {
+							return r.gamma(AST.MakerNot(part), c, func(r refutation, instance AST.Form) *GS3Sequent {
+								if len(occurring) == 1 {
+									return r.close(negated(instance))
+								}
+								return r.beta(NAND, instance, func(r refutation, _ int, instances []AST.Form) *GS3Sequent {
+									return r.close(negated(instances[0]))
+								})
+							})
+						},
+						func(r refutation) *GS3Sequent { return r.refutePush(part, x, rest, pushedPart, true) },
+					)
+				})
+			})
+		})
+	}
+	return r.alpha(AND, pushed, func(r refutation, _ []AST.Form) *GS3Sequent {
+		return r.cut(held, part,
+			func(r refutation) *GS3Sequent { return r.refutePush(part, x, rest, pushedPart, false) },
+			func(r refutation) *GS3Sequent {
+				return r.pair(part, quantified, func(r refutation, instance, negInstance AST.Form) *GS3Sequent {
+					return decomposeRest(r, AND, instance, func(r refutation) *GS3Sequent {
+						return r.beta(NAND, AST.MakerNot(negInstance), func(r refutation, i int, instances []AST.Form) *GS3Sequent {
+							return refuteForm(r, i, negated(instances[0]), false)
+						})
+					})
+				})
+			},
+		)
+	})
+}
+
+// The formula of form whose index in the results is j.
+func keptForm(forms []AST.Form, kept map[int]int, j int) AST.Form {
+	for i, k := range kept {
+		if k == j {
+			return forms[i]
+		}
+	}
+	return nil
+}
+
+// ∀x.(A ⇒ B) and A ⇒ ∀x.B, or ∃x.(A ⇒ B) and A ⇒ ∃x.B, where x does not
+// occur in A: Qx.B is cut, and Qx is pushed inside B.
+func (r refutation) refuteConclusion(quantified AST.Form, x AST.Var, form AST.Imp, pushed AST.Form, forward bool) *GS3Sequent {
+	held := heldFormula(quantified, forward)
+	_, isAll := quantified.(AST.All)
+	premise, conclusion := form.GetF1(), form.GetF2()
+	pushedImp := pushed.(AST.Imp)
+	part := quantify(isAll, x, conclusion)
+	cutPart := func(r refutation, negative func(refutation) *GS3Sequent) *GS3Sequent {
+		return r.cut(held, part, negative, func(r refutation) *GS3Sequent {
+			return r.refutePush(part, x, conclusion, pushedImp.GetF2(), forward)
+		})
+	}
+	cutPartBack := func(r refutation, positive func(refutation) *GS3Sequent) *GS3Sequent {
+		return r.cut(held, part, func(r refutation) *GS3Sequent {
+			return r.refutePush(part, x, conclusion, pushedImp.GetF2(), false)
+		}, positive)
+	}
+
+	switch {
+	case isAll && forward:
+		return r.alpha(NIMP, AST.MakerNot(pushed), func(r refutation, _ []AST.Form) *GS3Sequent {
+			return cutPart(r, func(r refutation) *GS3Sequent {
+				return r.pair(quantified, part, func(r refutation, instance, _ AST.Form) *GS3Sequent {
+					return r.beta(IMP, instance, func(r refutation, i int, instances []AST.Form) *GS3Sequent {
+						if i == 0 {
+							return r.refuteAlpha(pushedImp.GetF1(), premise)
+						}
+						return r.close(instances[0])
+					})
+				})
+			})
+		})
+	case isAll:
+		return r.open(AST.MakerNot(quantified), func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+			return r.alpha(NIMP, instance, func(r refutation, _ []AST.Form) *GS3Sequent {
+				return r.beta(IMP, pushed, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+					if i == 0 {
+						return r.refuteAlpha(premise, pushedImp.GetF1())
+					}
+					return cutPartBack(r, func(r refutation) *GS3Sequent {
+						return r.gamma(part, c, func(r refutation, instance AST.Form) *GS3Sequent { return r.close(instance) })
+					})
+				})
+			})
+		})
+	case forward:
+		return r.alpha(NIMP, AST.MakerNot(pushed), func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.open(quantified, func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+				return r.beta(IMP, instance, func(r refutation, i int, instances []AST.Form) *GS3Sequent {
+					if i == 0 {
+						return r.refuteAlpha(pushedImp.GetF1(), premise)
+					}
+					return cutPart(r, func(r refutation) *GS3Sequent {
+						return r.gamma(AST.MakerNot(part), c, func(r refutation, _ AST.Form) *GS3Sequent {
+							return r.close(instances[0])
+						})
+					})
+				})
+			})
+		})
+	}
+	return r.beta(IMP, pushed, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+		if i == 0 {
+			return r.open(AST.MakerNot(quantified), func(r refutation, _ AST.Term, instance AST.Form) *GS3Sequent {
+				return r.alpha(NIMP, instance, func(r refutation, _ []AST.Form) *GS3Sequent {
+					return r.refuteAlpha(premise, pushedImp.GetF1())
+				})
+			})
+		}
+		return cutPartBack(r, func(r refutation) *GS3Sequent {
+			return r.pair(part, quantified, func(r refutation, instance, negInstance AST.Form) *GS3Sequent {
+				return r.alpha(NIMP, AST.MakerNot(negInstance), func(r refutation, _ []AST.Form) *GS3Sequent {
+					return r.close(instance)
+				})
+			})
+		})
+	})
+}
+
+// ∀x.(A ⇒ B) and ∃x.A ⇒ B, or ∃x.(A ⇒ B) and ∀x.A ⇒ B, where x does not occur
+// in B: the dual quantifier of A is cut, and pushed inside A.
+func (r refutation) refutePremise(quantified AST.Form, x AST.Var, form AST.Imp, pushed AST.Form, forward bool) *GS3Sequent {
+	held := heldFormula(quantified, forward)
+	_, isAll := quantified.(AST.All)
+	premise, conclusion := form.GetF1(), form.GetF2()
+	pushedImp := pushed.(AST.Imp)
+	part := quantify(!isAll, x, premise)
+	pushPart := func(forward bool) func(refutation) *GS3Sequent {
+		return func(r refutation) *GS3Sequent { return r.refutePush(part, x, premise, pushedImp.GetF1(), forward) }
+	}
+
+	switch {
+	case isAll && forward:
+		return r.alpha(NIMP, AST.MakerNot(pushed), func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.cut(held, part, pushPart(false), func(r refutation) *GS3Sequent {
+				return r.open(part, func(r refutation, c AST.Term, partInstance AST.Form) *GS3Sequent {
+					return r.gamma(quantified, c, func(r refutation, instance AST.Form) *GS3Sequent {
+						return r.beta(IMP, instance, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+							if i == 0 {
+								return r.close(partInstance)
+							}
+							return r.refuteAlpha(conclusion, pushedImp.GetF2())
+						})
+					})
+				})
+			})
+		})
+	case isAll:
+		return r.open(AST.MakerNot(quantified), func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+			return r.alpha(NIMP, instance, func(r refutation, instances []AST.Form) *GS3Sequent {
+				return r.beta(IMP, pushed, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+					if i == 1 {
+						return r.refuteAlpha(pushedImp.GetF2(), conclusion)
+					}
+					return r.cut(held, part, func(r refutation) *GS3Sequent {
+						return r.gamma(AST.MakerNot(part), c, func(r refutation, _ AST.Form) *GS3Sequent {
+							return r.close(instances[0])
+						})
+					}, pushPart(true))
+				})
+			})
+		})
+	case forward:
+		return r.alpha(NIMP, AST.MakerNot(pushed), func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.open(quantified, func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+				return r.beta(IMP, instance, func(r refutation, i int, instances []AST.Form) *GS3Sequent {
+					if i == 1 {
+						return r.refuteAlpha(conclusion, pushedImp.GetF2())
+					}
+					return r.cut(held, part, pushPart(false), func(r refutation) *GS3Sequent {
+						return r.gamma(part, c, func(r refutation, partInstance AST.Form) *GS3Sequent {
+							return r.close(partInstance)
+						})
+					})
+				})
+			})
+		})
+	}
+	return r.beta(IMP, pushed, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+		if i == 1 {
+			return r.open(AST.MakerNot(quantified), func(r refutation, _ AST.Term, instance AST.Form) *GS3Sequent {
+				return r.alpha(NIMP, instance, func(r refutation, _ []AST.Form) *GS3Sequent {
+					return r.refuteAlpha(pushedImp.GetF2(), conclusion)
+				})
+			})
+		}
+		return r.cut(held, part, func(r refutation) *GS3Sequent {
+			return r.open(AST.MakerNot(part), func(r refutation, c AST.Term, _ AST.Form) *GS3Sequent {
+				return r.gamma(AST.MakerNot(quantified), c, func(r refutation, instance AST.Form) *GS3Sequent {
+					return r.alpha(NIMP, instance, func(r refutation, instances []AST.Form) *GS3Sequent {
+						return r.close(instances[0])
+					})
+				})
+			})
+		}, pushPart(true))
+	})
+}
+
+// Refutes a formula and the negation of a formula equal up to the names of
+// their bound variables, the grouping of their quantifiers and the nesting of
+// their conjunctions and disjunctions (see Core.AlphaEquals).
+func (r refutation) refuteAlpha(pos, neg AST.Form) *GS3Sequent {
+	if pos.Equals(neg) {
+		return r.close(pos)
+	}
+	return r.lockstep(pos, neg, true, func(r refutation, pos, neg AST.Form, _ bool) *GS3Sequent {
+		return r.refuteAlpha(pos, neg)
+	})
+}
+
+// Refutes a formula and the negation of a formula with the same connective by
+// decomposing them together, and then refuting their corresponding children
+// with sub, which is told whether the positive child is the one of neg. The
+// nested conjunctions and disjunctions are decomposed at once when flatten.
+func (r refutation) lockstep(pos, neg AST.Form, flatten bool, sub func(refutation, AST.Form, AST.Form, bool) *GS3Sequent) *GS3Sequent {
+	notNeg := AST.MakerNot(neg)
+	switch nf := pos.(type) {
+	case AST.Not:
+		return r.alpha(NNOT, notNeg, func(r refutation, forms []AST.Form) *GS3Sequent {
+			return sub(r, forms[0], nf.GetForm(), true)
+		})
+	case AST.And:
+		return r.decompose(AND, pos, flatten, func(r refutation, forms []AST.Form) *GS3Sequent {
+			return r.split(NAND, notNeg, flatten, func(r refutation, i int, negForm AST.Form) *GS3Sequent {
+				return sub(r, forms[i], negated(negForm), false)
+			})
+		})
+	case AST.Or:
+		return r.decompose(NOR, notNeg, flatten, func(r refutation, negForms []AST.Form) *GS3Sequent {
+			return r.split(OR, pos, flatten, func(r refutation, i int, form AST.Form) *GS3Sequent {
+				return sub(r, form, negated(negForms[i]), false)
+			})
+		})
+	case AST.Imp:
+		negImp := neg.(AST.Imp)
+		return r.alpha(NIMP, notNeg, func(r refutation, _ []AST.Form) *GS3Sequent {
+			return r.beta(IMP, pos, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+				if i == 0 {
+					return sub(r, negImp.GetF1(), nf.GetF1(), true)
+				}
+				return sub(r, nf.GetF2(), negImp.GetF2(), false)
+			})
+		})
+	case AST.Equ:
+		negEqu := neg.(AST.Equ)
+		// The children of NEQU are ¬B1, B2 and B1, ¬B2, those of EQU are ¬A1,
+		// ¬A2 and A1, A2.
+		return r.beta(NEQU, notNeg, func(r refutation, i int, _ []AST.Form) *GS3Sequent {
+			return r.beta(EQU, pos, func(r refutation, j int, _ []AST.Form) *GS3Sequent {
+				switch {
+				case i == 0 && j == 0:
+					return sub(r, negEqu.GetF2(), nf.GetF2(), true)
+				case i == 0:
+					return sub(r, nf.GetF1(), negEqu.GetF1(), false)
+				case j == 0:
+					return sub(r, negEqu.GetF1(), nf.GetF1(), true)
+				}
+				return sub(r, nf.GetF2(), negEqu.GetF2(), false)
+			})
+		})
+	case AST.All, AST.Ex:
+		return r.pair(pos, neg, func(r refutation, instance, negInstance AST.Form) *GS3Sequent {
+			return sub(r, instance, negInstance, false)
+		})
+	}
+	return r.close(pos)
+}
+
+/*** Rules ***/
+
+// Applies the rule on the target and builds the children with the formulas it
+// generates for them.
+func (r refutation) apply(rule Rule, target AST.Form, term AST.Term, generated []*AST.FormList, children ...func(refutation) *GS3Sequent) *GS3Sequent {
+	seq := MakeNewSequent()
+	seq.setHypotheses(r.hypotheses)
+	seq.nodeId = r.nodeId
+	seq.setAppliedRule(rule)
+	seq.setAppliedOn(target)
+	seq.setTermGenerated(term)
+	seq.setFormsGenerated(generated)
+	for i, child := range children {
+		hypotheses := r.hypotheses.Copy()
+		hypotheses.Append(generated[i].Slice()...)
+		seq.addChild(child(refutation{hypotheses, r.nodeId}))
+	}
+	return seq
+}
+
+// Closes the branch, which has the formula and its negation.
+func (r refutation) close(form AST.Form) *GS3Sequent {
+	return r.apply(AX, AST.MakerNot(form), nil, nil)
+}
+
+// Cuts the formula: the first child has its negation, the second one has it.
+func (r refutation) cut(target, form AST.Form, negative, positive func(refutation) *GS3Sequent) *GS3Sequent {
+	generated := []*AST.FormList{AST.NewFormList(AST.MakerNot(form)), AST.NewFormList(form)}
+	return r.apply(CUT, target, nil, generated, negative, positive)
+}
+
+func (r refutation) alpha(rule Rule, target AST.Form, k func(refutation, []AST.Form) *GS3Sequent) *GS3Sequent {
+	generated, _ := expectedResults(rule, target)
+	return r.apply(rule, target, nil, generated, func(r refutation) *GS3Sequent {
+		return k(r, generated[0].Slice())
+	})
+}
+
+func (r refutation) beta(rule Rule, target AST.Form, k func(refutation, int, []AST.Form) *GS3Sequent) *GS3Sequent {
+	generated, _ := expectedResults(rule, target)
+	children := make([]func(refutation) *GS3Sequent, len(generated))
+	for i := range generated {
+		i := i
+		children[i] = func(r refutation) *GS3Sequent { return k(r, i, generated[i].Slice()) }
+	}
+	return r.apply(rule, target, nil, generated, children...)
+}
+
+// Applies the alpha rule on the target and, when flatten, on the formulas it
+// generates to which it applies, in order.
+func (r refutation) decompose(rule Rule, target AST.Form, flatten bool, k func(refutation, []AST.Form) *GS3Sequent) *GS3Sequent {
+	return r.alpha(rule, target, func(r refutation, forms []AST.Form) *GS3Sequent {
+		return r.decomposeAll(rule, forms, []AST.Form{}, flatten, k)
+	})
+}
+
+func (r refutation) decomposeAll(rule Rule, forms, done []AST.Form, flatten bool, k func(refutation, []AST.Form) *GS3Sequent) *GS3Sequent {
+	if len(forms) == 0 {
+		return k(r, done)
+	}
+	if _, applies := expectedResults(rule, forms[0]); flatten && applies {
+		return r.alpha(rule, forms[0], func(r refutation, generated []AST.Form) *GS3Sequent {
+			return r.decomposeAll(rule, append(append([]AST.Form{}, generated...), forms[1:]...), done, flatten, k)
+		})
+	}
+	return r.decomposeAll(rule, forms[1:], append(append([]AST.Form{}, done...), forms[0]), flatten, k)
+}
+
+// Applies the beta rule on the target and, when flatten, on the formulas it
+// generates to which it applies. k gets the index of each branch, in order.
+func (r refutation) split(rule Rule, target AST.Form, flatten bool, k func(refutation, int, AST.Form) *GS3Sequent) *GS3Sequent {
+	next := 0
+	return r.splitFrom(rule, target, flatten, &next, k)
+}
+
+func (r refutation) splitFrom(rule Rule, target AST.Form, flatten bool, next *int, k func(refutation, int, AST.Form) *GS3Sequent) *GS3Sequent {
+	return r.beta(rule, target, func(r refutation, _ int, forms []AST.Form) *GS3Sequent {
+		if _, applies := expectedResults(rule, forms[0]); flatten && applies {
+			return r.splitFrom(rule, forms[0], flatten, next, k)
+		}
+		*next++
+		return k(r, *next-1, forms[0])
+	})
+}
+
+// Instantiates a formula that is universal, or the negation of an existential
+// one, with the term.
+func (r refutation) gamma(target AST.Form, term AST.Term, k func(refutation, AST.Form) *GS3Sequent) *GS3Sequent {
+	rule := ALL
+	if Glob.Is[AST.Not](target) {
+		rule = NEX
+	}
+	instance := instantiate(rule, target, term)
+	return r.apply(rule, target, term, []*AST.FormList{AST.NewFormList(instance)}, func(r refutation) *GS3Sequent {
+		return k(r, instance)
+	})
+}
+
+// Instantiates a formula that is existential, or the negation of a universal
+// one, with a fresh symbol.
+func (r refutation) delta(target AST.Form, k func(refutation, AST.Term, AST.Form) *GS3Sequent) *GS3Sequent {
+	rule := EX
+	if Glob.Is[AST.Not](target) {
+		rule = NALL
+	}
+	x, _, _ := openQuantifier(rule, target)
+	witness := Sko.MkSkolemTerm(x, Lib.NewList[AST.Meta]())
+	instance := instantiate(rule, target, witness)
+	return r.apply(rule, target, witness, []*AST.FormList{AST.NewFormList(instance)}, func(r refutation) *GS3Sequent {
+		return k(r, witness, instance)
+	})
+}
+
+// Instantiates a quantified formula, or the negation of one, alone: the delta
+// rules give their witness, and the gamma rules take any term.
+func (r refutation) open(target AST.Form, k func(refutation, AST.Term, AST.Form) *GS3Sequent) *GS3Sequent {
+	if Glob.Is[AST.All](target) || Glob.Is[AST.Ex](negatedOrNil(target)) {
+		return r.gamma(target, nil, func(r refutation, instance AST.Form) *GS3Sequent { return k(r, nil, instance) })
+	}
+	return r.delta(target, k)
+}
+
+// Instantiates a quantified formula and the negation of one with the same
+// quantifier with the same term, the witness of the delta rule. k gets the
+// instances without the negation.
+func (r refutation) pair(pos, neg AST.Form, k func(refutation, AST.Form, AST.Form) *GS3Sequent) *GS3Sequent {
+	notNeg := AST.MakerNot(neg)
+	if Glob.Is[AST.All](pos) {
+		return r.delta(notNeg, func(r refutation, c AST.Term, negInstance AST.Form) *GS3Sequent {
+			return r.gamma(pos, c, func(r refutation, instance AST.Form) *GS3Sequent {
+				return k(r, instance, negated(negInstance))
+			})
+		})
+	}
+	return r.delta(pos, func(r refutation, c AST.Term, instance AST.Form) *GS3Sequent {
+		return r.gamma(notNeg, c, func(r refutation, negInstance AST.Form) *GS3Sequent {
+			return k(r, instance, negated(negInstance))
+		})
+	})
+}
+
+/*** Formulas ***/
+
+// The rule eliminating the quantifier of a formula.
+func positiveQuantifierRule(form AST.Form) Rule {
+	if Glob.Is[AST.All](form) {
+		return ALL
+	}
+	return EX
+}
+
+func quantify(isAll bool, x AST.Var, form AST.Form) AST.Form {
+	if isAll {
+		return AST.MakerAll([]AST.Var{x}, form)
+	}
+	return AST.MakerEx([]AST.Var{x}, form)
+}
+
+func negated(form AST.Form) AST.Form {
+	return form.(AST.Not).GetForm()
+}
+
+// The quantified formula of refutePush, or its negation, in the branch.
+func heldFormula(quantified AST.Form, forward bool) AST.Form {
+	if forward {
+		return quantified
+	}
+	return AST.MakerNot(quantified)
+}
+
+func negatedOrNil(form AST.Form) AST.Form {
+	if not, isNot := form.(AST.Not); isNot {
+		return not.GetForm()
+	}
+	return nil
+}
+
+func occurs(x AST.Var, form AST.Form) bool {
+	_, found := form.ReplaceTermByTerm(Glob.To[AST.Term](x), Glob.To[AST.Term](x))
+	return found
+}
@@ -0,0 +1,111 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file contains the tests of the proofs of the miniscoping.
+**/
+
+package gs3
+
+import (
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+)
+
+func and(forms ...AST.Form) AST.Form {
+	return AST.MakerAnd(AST.NewFormList(forms...))
+}
+
+func or(forms ...AST.Form) AST.Form {
+	return AST.MakerOr(AST.NewFormList(forms...))
+}
+
+func all(x AST.Var, form AST.Form) AST.Form {
+	return AST.MakerAll([]AST.Var{x}, form)
+}
+
+func ex(x AST.Var, form AST.Form) AST.Form {
+	return AST.MakerEx([]AST.Var{x}, form)
+}
+
+// The formula and the negation of its miniscoping are refuted, as well as the
+// miniscoping and the negation of the formula.
+func TestRefuteMiniscoping(t *testing.T) {
+	x, y := AST.MakerVar("X"), AST.MakerVar("Y")
+	p, q := pred("p", x), pred("q", y)
+	r, s := pred("r"), pred("s", x, y)
+	tests := []struct {
+		name string
+		form AST.Form
+	}{
+		{"drop", all(x, r)},
+		{"distribute all", all(x, and(p, r, pred("t", x)))},
+		{"distribute ex", ex(x, or(p, r))},
+		{"split all", all(x, or(r, p, pred("t", x)))},
+		{"split ex", ex(x, and(p, r))},
+		{"split several", ex(x, and(p, r, pred("t", x)))},
+		{"conclusion", all(x, AST.MakerImp(r, p))},
+		{"premise", all(x, AST.MakerImp(p, r))},
+		{"ex conclusion", ex(x, AST.MakerImp(r, p))},
+		{"ex premise", ex(x, AST.MakerImp(p, r))},
+		{"kept", all(y, and(q, all(x, AST.MakerImp(p, pred("t", x)))))},
+		{"several variables", AST.MakerAll([]AST.Var{x, y}, and(p, q))},
+		{"nested", all(x, ex(y, and(p, q, s)))},
+		{"pushed twice", AST.MakerEx([]AST.Var{x, y}, AST.MakerImp(and(p, r), or(q, s)))},
+		{"dual", all(x, AST.MakerImp(ex(y, and(p, q)), r))},
+		{"inner", AST.MakerNot(ex(x, all(y, or(AST.MakerEqu(p, r), q))))},
+		{"connectives", AST.MakerEqu(all(x, and(p, r)), AST.MakerImp(ex(y, or(q, r)), r))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			miniscoped := Core.Miniscope(tt.form)
+			if miniscoped.Equals(tt.form) {
+				t.Fatalf("Error: %s is already miniscoped.", tt.form.ToString())
+			}
+
+			forward := refutation{AST.NewFormList(tt.form, AST.MakerNot(miniscoped)), 0}
+			proof := forward.refuteMiniscoping(tt.form, miniscoped, true)
+			if err := CheckProof(proof, forward.hypotheses); err != nil {
+				t.Fatalf("Error: the refutation of %s and the negation of %s is not valid, %v", tt.form.ToString(), miniscoped.ToString(), err)
+			}
+
+			backward := refutation{AST.NewFormList(miniscoped, AST.MakerNot(tt.form)), 0}
+			proof = backward.refuteMiniscoping(tt.form, miniscoped, false)
+			if err := CheckProof(proof, backward.hypotheses); err != nil {
+				t.Fatalf("Error: the refutation of %s and the negation of %s is not valid, %v", miniscoped.ToString(), tt.form.ToString(), err)
+			}
+		})
+	}
+}
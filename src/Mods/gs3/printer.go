@@ -0,0 +1,226 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file walks a GS3 proof for the outputs to proof assistants: it keeps
+* track of the hypotheses of each branch and of the variables introduced by
+* the delta rules, and a printer gives the steps of each language.
+**/
+
+package gs3
+
+import (
+	"fmt"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+)
+
+// The problem proven by a GS3 proof. The axioms are the first hypotheses of
+// the proof, in this order, and the negation of the conjecture follows them.
+// The conjecture is nil when the axioms are refuted.
+type Theorem struct {
+	Axioms     *AST.FormList
+	Conjecture AST.Form
+}
+
+// The hypotheses of a branch, named after their indices, and the skolem
+// symbols that the delta rules of the branch introduced as variables.
+type Branch struct {
+	Hypotheses *AST.FormList
+	Constants  []AST.Term
+}
+
+// A premise of a rule: the branch of a child, with the indices of the
+// formulas the rule introduces in it, and the proof of the child. The delta
+// rules also introduce the variable named Variable.
+type Premise[T any] struct {
+	Branch
+	Introduced []int
+	Variable   string
+	Proof      T
+}
+
+// The steps of a proof assistant for the rules of GS3. The proofs of the
+// premises are printed before their rule, which nests them as its language
+// needs. The targets are the indices of the hypotheses the rules apply on, -1
+// when they are not among them.
+type Printer[T any] struct {
+	// The name of the output in the errors.
+	Name string
+	// Closes a branch on its target formula.
+	Closure func(target AST.Form, branch Branch) T
+	// Applies an alpha, beta, gamma or delta rule, or a cut, whose target is
+	// the formula it helps decomposing.
+	Rule func(seq *GS3Sequent, target int, branch Branch, premises []Premise[T]) T
+	// Weakens the target, which is True in the premise unless a skolem symbol
+	// is weakened. The proof of the premise is kept when it is nil.
+	Weaken func(seq *GS3Sequent, target int, premise Premise[T]) T
+	// Rewrites the target with the hypothesis of index rule.
+	Rewrite func(seq *GS3Sequent, target, rule int, branch Branch, premise Premise[T]) T
+	// Whether the rewritten formula is introduced as a new hypothesis, the
+	// target becoming True, instead of replacing the target.
+	RewriteAsNew bool
+	// The name of the variable introduced for a skolem symbol.
+	VariableName func(id AST.Id) string
+	// Stands for the proof of a rule that the output does not support.
+	Unsupported T
+}
+
+type walker[T any] struct {
+	printer Printer[T]
+	dummy   int
+}
+
+// Prints the proof of the problem at the root of the GS3 proof, which refutes
+// the hypotheses of the theorem, with the printer.
+func PrintProof[T any](proof *GS3Sequent, printer Printer[T]) (Theorem, T) {
+	theorem, isConjunction := processMainFormula(proof.GetTargetForm())
+	if Glob.IsLoaded("dmt") {
+		theorem.Axioms.Append(dmt.GetRegisteredAxioms().Slice()...)
+	}
+	if isConjunction {
+		proof = proof.Child(0)
+	}
+
+	hypotheses := theorem.Axioms.Copy()
+	if theorem.Conjecture != nil {
+		hypotheses.Append(AST.MakerNot(theorem.Conjecture))
+	}
+	w := &walker[T]{printer: printer}
+	return theorem, w.walk(proof, Branch{hypotheses, []AST.Term{}})
+}
+
+// Processes the formula that was proven by Goéland. The last formula of the
+// conjunction is the negated conjecture if there is one.
+func processMainFormula(form AST.Form) (Theorem, bool) {
+	switch nf := form.(type) {
+	case AST.Not:
+		return Theorem{AST.NewFormList(), nf.GetForm()}, false
+	case AST.And:
+		last := nf.FormList.Len() - 1
+		if not, isNot := nf.FormList.Get(last).(AST.Not); isNot {
+			return Theorem{AST.NewFormList(nf.FormList.GetElements(0, last)...), not.GetForm()}, true
+		}
+		return Theorem{AST.NewFormList(nf.FormList.Slice()...), nil}, true
+	}
+	return Theorem{AST.NewFormList(form), nil}, false
+}
+
+func (w *walker[T]) walk(seq *GS3Sequent, branch Branch) T {
+	if seq.IsEmpty() {
+		if len(seq.Children()) == 0 {
+			return w.printer.Closure(seq.GetTargetForm(), branch)
+		}
+		return w.walk(seq.Child(0), branch)
+	}
+
+	target, _ := branch.Hypotheses.GetIndexOf(seq.GetTargetForm())
+	switch rule := seq.Rule(); {
+	case rule == AX:
+		return w.printer.Closure(seq.GetTargetForm(), branch)
+
+	case IsAlphaRule(rule), IsBetaRule(rule), IsGammaRule(rule), IsDeltaRule(rule), rule == CUT:
+		return w.printer.Rule(seq, target, branch, w.premises(seq, branch))
+
+	case rule == W:
+		premise := Premise[T]{Branch: branch.copy()}
+		if seq.TermGenerated() == nil && target != -1 {
+			premise.Hypotheses.Set(target, AST.MakerTop())
+		}
+		premise.Proof = w.walk(seq.Child(0), premise.copy())
+		if w.printer.Weaken == nil {
+			return premise.Proof
+		}
+		return w.printer.Weaken(seq, target, premise)
+
+	case rule == REWRITE:
+		rewriteRule, _ := branch.Hypotheses.GetIndexOf(seq.GetRewriteWith())
+		premise := Premise[T]{Branch: branch.copy()}
+		rewritten := seq.GetResultFormulasOfChild(0).Get(0)
+		if w.printer.RewriteAsNew {
+			premise.Introduced = introduce(AST.NewFormList(rewritten), premise.Hypotheses)
+			premise.Hypotheses.Set(target, AST.MakerTop())
+		} else {
+			premise.Hypotheses.Set(target, rewritten)
+		}
+		premise.Proof = w.walk(seq.Child(0), premise.copy())
+		return w.printer.Rewrite(seq, target, rewriteRule, branch, premise)
+	}
+
+	Glob.PrintError(w.printer.Name, fmt.Sprintf("Unsupported rule on %s", seq.GetTargetForm().ToString()))
+	return w.printer.Unsupported
+}
+
+// The children are walked on copies of their branches, so that the premises
+// keep the hypotheses as the rule introduced them.
+func (w *walker[T]) premises(seq *GS3Sequent, branch Branch) []Premise[T] {
+	premises := make([]Premise[T], len(seq.Children()))
+	for i, child := range seq.Children() {
+		premise := Premise[T]{Branch: branch.copy()}
+		premise.Introduced = introduce(seq.GetResultFormulasOfChild(i), premise.Hypotheses)
+		if IsDeltaRule(seq.Rule()) {
+			premise.Variable = w.introduceVariable(seq.TermGenerated(), &premise.Branch)
+		}
+		premise.Proof = w.walk(child, premise.copy())
+		premises[i] = premise
+	}
+	return premises
+}
+
+// Names the variable of a delta rule after its skolem symbol, which is then
+// printed as the variable in the branch, or with a fresh name.
+func (w *walker[T]) introduceVariable(term AST.Term, branch *Branch) string {
+	if term == nil {
+		w.dummy++
+		return fmt.Sprintf("x%d", w.dummy-1)
+	}
+	branch.Constants = append(branch.Constants, term)
+	return w.printer.VariableName(term.(AST.Fun).GetID())
+}
+
+// Appends the formulas to the hypotheses, and returns their indices.
+func introduce(forms, hypotheses *AST.FormList) []int {
+	indices := make([]int, forms.Len())
+	for i, form := range forms.Slice() {
+		indices[i] = hypotheses.Len()
+		hypotheses.Append(form)
+	}
+	return indices
+}
+
+func (branch Branch) copy() Branch {
+	constants := make([]AST.Term, len(branch.Constants))
+	copy(constants, branch.Constants)
+	return Branch{branch.Hypotheses.Copy(), constants}
+}
@@ -116,7 +116,7 @@ var MakeGS3Proof = func(proof []Search.ProofStruct) *GS3Sequent {
 		gs3Proof.branchForms.Append(proof[0].Formula.GetForm())
 	}
 	sequent := gs3Proof.makeProof(proof)
-	return sequent
+	return addMiniscopingStep(sequent, proof[0])
 }
 
 func (gs GS3Proof) Copy() GS3Proof {
@@ -183,7 +183,7 @@ func (gs *GS3Proof) makeProofOneStep(proofStep Search.ProofStruct, parent *GS3Se
 	// TODO: manage rewrite rules: second return value of proofStructRuleToGS3Rule
 	switch rule {
 	// Immediate, just apply the rule.
-	case NNOT, NOR, NIMP, AND, NAND, NEQU, OR, IMP, EQU, AX, REWRITE:
+	case NNOT, NOR, NIMP, AND, NAND, NEQU, OR, IMP, EQU, AX, ARI, REWRITE:
 		seq.setAppliedRule(rule)
 		seq.setAppliedOn(form)
 		if rule == REWRITE {
@@ -72,6 +72,8 @@ const (
 	NALL
 	R
 	REWRITE
+	CUT
+	ARI
 )
 
 func MakeNewSequent() *GS3Sequent {
@@ -131,6 +133,12 @@ func (seq *GS3Sequent) GetResultFormulasOfChildren() []*AST.FormList {
 	return result
 }
 
+// The formula cut by the CUT rule: the first child has its negation, and the
+// second one has it.
+func (seq *GS3Sequent) GetCutFormula() AST.Form {
+	return seq.formsGenerated[1].Get(0)
+}
+
 func (seq *GS3Sequent) TermGenerated() AST.Term {
 	return seq.termGenerated
 }
@@ -243,7 +251,10 @@ func (seq *GS3Sequent) ruleToString(rule Rule) string {
 		NALL: "NOT_FORALL (delta)",
 		EX:   "EXISTS (delta)",
 		AX:   "AXIOM",
+		ARI:  "ARITHMETIC",
 		W:    "WEAKEN",
+
+		CUT: "CUT",
 	}
 	return mapping[rule]
 }
@@ -254,23 +265,24 @@ func (seq *GS3Sequent) setFormsGenerated(forms []*AST.FormList) {
 
 func proofStructRuleToGS3Rule(rule string) Rule {
 	mapping := map[string]Rule{
-		"ALPHA_NOT_NOT":    NNOT,
-		"ALPHA_NOT_OR":     NOR,
-		"ALPHA_NOT_IMPLY":  NIMP,
-		"ALPHA_AND":        AND,
-		"BETA_NOT_AND":     NAND,
-		"BETA_NOT_EQUIV":   NEQU,
-		"BETA_OR":          OR,
-		"BETA_IMPLY":       IMP,
-		"BETA_EQUIV":       EQU,
-		"GAMMA_NOT_EXISTS": NEX,
-		"GAMMA_FORALL":     ALL,
-		"DELTA_NOT_FORALL": NALL,
-		"DELTA_EXISTS":     EX,
-		"CLOSURE":          AX,
-		"WEAKEN":           W,
-		"Reintroduction":   R,
-		"Rewrite":          REWRITE,
+		"ALPHA_NOT_NOT":      NNOT,
+		"ALPHA_NOT_OR":       NOR,
+		"ALPHA_NOT_IMPLY":    NIMP,
+		"ALPHA_AND":          AND,
+		"BETA_NOT_AND":       NAND,
+		"BETA_NOT_EQUIV":     NEQU,
+		"BETA_OR":            OR,
+		"BETA_IMPLY":         IMP,
+		"BETA_EQUIV":         EQU,
+		"GAMMA_NOT_EXISTS":   NEX,
+		"GAMMA_FORALL":       ALL,
+		"DELTA_NOT_FORALL":   NALL,
+		"DELTA_EXISTS":       EX,
+		"CLOSURE":            AX,
+		"ARITHMETIC_CLOSURE": ARI,
+		"WEAKEN":             W,
+		"Reintroduction":     R,
+		"Rewrite":            REWRITE,
 	}
 	return mapping[rule]
 }
@@ -290,8 +302,10 @@ func ruleToTableauxString(rule Rule) string {
 		NALL:    "DELTA_NOT_FORALL",
 		EX:      "DELTA_EXISTS",
 		AX:      "CLOSURE",
+		ARI:     "ARITHMETIC_CLOSURE",
 		W:       "WEAKEN",
 		REWRITE: "REWRITE",
+		CUT:     "CUT",
 	}
 	return mapping[rule]
 }
@@ -0,0 +1,242 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides Isabelle's context for a proof.
+**/
+
+package isabelle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+)
+
+func makeContextIfNeeded(root AST.Form, metaList Lib.List[AST.Meta]) string {
+	if !GetContextEnabled() {
+		return ""
+	}
+
+	resultingString := contextPreamble()
+
+	if Glob.IsLoaded("dmt") {
+		registeredAxioms := dmt.GetRegisteredAxioms()
+		registeredAxioms.Append(root)
+		root = AST.MakerAnd(registeredAxioms)
+	}
+
+	if !AST.EmptyGlobalContext() {
+		resultingString += strings.Join(getTypesFromGlobalContext(), "\n") + "\n"
+	}
+
+	resultingString += strings.Join(getContextFromFormula(root), "\n") + "\n"
+
+	if metaList.Len() > 0 {
+		resultingString += contextualizeMetas(metaList)
+	}
+	return resultingString
+}
+
+func contextPreamble() string {
+	imports := "Main"
+	if Glob.GetArithModule() {
+		imports = "Complex_Main"
+	}
+	str := fmt.Sprintf("theory %s\n  imports %s\nbegin\n\n", theoryName(), imports)
+	str += "typedecl goeland_U (* goeland's universe *)\n"
+	str += "consts goeland_I :: goeland_U (* an individual in the universe. *)\n"
+	str += lemmas
+	return str
+}
+
+// The types declared in the TFF signature.
+func getTypesFromGlobalContext() []string {
+	result := []string{}
+	for k, v := range AST.GetGlobalContext() {
+		if len(v) == 0 {
+			continue
+		}
+		if typed, ok := v[0].App.(AST.TypeHint); ok && k[0] != '$' && k == typed.ToString() {
+			if name := typeToString(typed); name == sanitize(k) {
+				result = append(result, "typedecl "+name)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func getContextFromFormula(root AST.Form) []string {
+	result := []string{}
+	switch nf := root.(type) {
+	case AST.All:
+		result = getContextFromFormula(nf.GetForm())
+	case AST.Ex:
+		result = getContextFromFormula(nf.GetForm())
+	case AST.AllType:
+		result = getContextFromFormula(nf.GetForm())
+	case AST.And:
+		for _, f := range nf.FormList.Slice() {
+			result = append(result, clean(result, getContextFromFormula(f))...)
+		}
+	case AST.Or:
+		for _, f := range nf.FormList.Slice() {
+			result = append(result, clean(result, getContextFromFormula(f))...)
+		}
+	case AST.Imp:
+		result = clean(result, getContextFromFormula(nf.GetF1()))
+		result = append(result, clean(result, getContextFromFormula(nf.GetF2()))...)
+	case AST.Equ:
+		result = clean(result, getContextFromFormula(nf.GetF1()))
+		result = append(result, clean(result, getContextFromFormula(nf.GetF2()))...)
+	case AST.Not:
+		result = clean(result, getContextFromFormula(nf.GetForm()))
+	case AST.Pred:
+		if !nf.GetID().Equals(AST.Id_eq) {
+			result = append(result, declaration(nf.GetID(), nf.GetType()))
+		}
+		for _, term := range nf.GetArgs().GetSlice() {
+			result = append(result, clean(result, getContextFromTerm(term))...)
+		}
+	}
+	return result
+}
+
+func getContextFromTerm(trm AST.Term) []string {
+	result := []string{}
+	if fun, isFun := trm.(AST.Fun); isFun {
+		result = append(result, declaration(fun.GetID(), fun.GetTypeHint()))
+		for _, term := range fun.GetArgs().GetSlice() {
+			result = append(result, clean(result, getContextFromTerm(term))...)
+		}
+	}
+	return result
+}
+
+// Declares a symbol with the type of the TFF signature when there is one, and
+// with the type of its occurrence otherwise.
+func declaration(id AST.Id, type_ AST.TypeScheme) string {
+	if apps, found := AST.GetGlobalContext()[id.GetName()]; found && len(apps) == 1 {
+		type_ = apps[0].App
+	}
+	return fmt.Sprintf("consts %s :: \"%s\"", symbolName(id), typeToString(type_))
+}
+
+// Returns everything in add not in set
+func clean(set, add []string) []string {
+	result := []string{}
+	for _, str := range add {
+		found := false
+		for _, s := range set {
+			if s == str {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+func contextualizeMetas(metaList Lib.List[AST.Meta]) string {
+	result := []string{}
+	for _, meta := range metaList.GetSlice() {
+		result = append(result, fmt.Sprintf("consts %s :: \"%s\"", metaName(meta), typeToString(meta.GetTypeHint())))
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+func theoryName() string {
+	return "goeland_proof_of_" + sanitize(Glob.GetProblemName())
+}
+
+var lemmas = `
+lemma goeland_notnot: "P ==> ~ P ==> False"
+  by blast
+
+lemma goeland_nottrue: "~ True ==> False"
+  by blast
+
+lemma goeland_noteq: "~ (t = t) ==> False"
+  by blast
+
+lemma goeland_nnot: "(P ==> False) ==> ~ ~ P ==> False"
+  by blast
+
+lemma goeland_and: "(P ==> Q ==> False) ==> P & Q ==> False"
+  by blast
+
+lemma goeland_or: "(P ==> False) ==> (Q ==> False) ==> P | Q ==> False"
+  by blast
+
+lemma goeland_imply: "(~ P ==> False) ==> (Q ==> False) ==> (P --> Q) ==> False"
+  by blast
+
+lemma goeland_equiv: "(~ P ==> ~ Q ==> False) ==> (P ==> Q ==> False) ==> (P <-> Q) ==> False"
+  by blast
+
+lemma goeland_notand: "(~ P ==> False) ==> (~ Q ==> False) ==> ~ (P & Q) ==> False"
+  by blast
+
+lemma goeland_notor: "(~ P ==> ~ Q ==> False) ==> ~ (P | Q) ==> False"
+  by blast
+
+lemma goeland_notimply: "(P ==> ~ Q ==> False) ==> ~ (P --> Q) ==> False"
+  by blast
+
+lemma goeland_notequiv: "(~ P ==> Q ==> False) ==> (P ==> ~ Q ==> False) ==> ~ (P <-> Q) ==> False"
+  by blast
+
+lemma goeland_ex: "(!!z. P z ==> False) ==> (EX x. P x) ==> False"
+  by blast
+
+lemma goeland_all: "(P t ==> False) ==> (ALL x. P x) ==> False"
+  by blast
+
+lemma goeland_notex: "(~ P t ==> False) ==> ~ (EX x. P x) ==> False"
+  by blast
+
+lemma goeland_notall: "(!!z. ~ P z ==> False) ==> ~ (ALL x. P x) ==> False"
+  by blast
+
+lemma goeland_cut: "(~ P ==> False) ==> (P ==> False) ==> False"
+  by blast
+
+`
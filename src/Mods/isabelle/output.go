@@ -0,0 +1,226 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides an Isabelle/Isar output for Goeland's proofs.
+**/
+
+package isabelle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+var contextEnabled bool = false
+
+var IsabelleOutputProofStruct = &Search.OutputProofStruct{ProofOutput: MakeIsabelleOutput, Name: "Isabelle", Extension: ".thy"}
+
+// ----------------------------------------------------------------------------
+// Plugin initialisation and main function to call.
+
+// Section: init
+// Functions: MakeIsabelleOutput
+// Main functions of the isabelle module.
+
+func MakeIsabelleOutput(prf []Search.ProofStruct, meta Lib.List[AST.Meta]) string {
+	if len(prf) == 0 {
+		Glob.PrintError("Isabelle", "Nothing to output")
+		return ""
+	}
+
+	// Transform tableaux's proof in GS3 proof
+	return MakeIsabelleProof(gs3.MakeGS3Proof(prf), meta)
+}
+
+var MakeIsabelleProof = func(proof *gs3.GS3Sequent, meta Lib.List[AST.Meta]) string {
+	contextString := makeContextIfNeeded(proof.GetTargetForm(), meta)
+	proofString := makeIsabelleProofFromGS3(proof)
+	if GetContextEnabled() {
+		return contextString + "\n" + proofString + "\nend\n"
+	}
+	return proofString
+}
+
+// ----------------------------------------------------------------------------
+// Printing of formulas, terms and types in Isabelle's ASCII syntax.
+
+// Prints a formula. The skolem symbols of constantsCreated are the variables
+// fixed by the delta steps: they are printed without their arguments.
+func formToString(form AST.Form, constantsCreated []AST.Term) string {
+	switch nf := form.(type) {
+	case AST.Top:
+		return "True"
+	case AST.Bot:
+		return "False"
+	case AST.Not:
+		return "(~ " + formToString(nf.GetForm(), constantsCreated) + ")"
+	case AST.And:
+		return "(" + formListToString(nf.FormList, " & ", constantsCreated) + ")"
+	case AST.Or:
+		return "(" + formListToString(nf.FormList, " | ", constantsCreated) + ")"
+	case AST.Imp:
+		return "(" + formToString(nf.GetF1(), constantsCreated) + " --> " + formToString(nf.GetF2(), constantsCreated) + ")"
+	case AST.Equ:
+		return "(" + formToString(nf.GetF1(), constantsCreated) + " <-> " + formToString(nf.GetF2(), constantsCreated) + ")"
+	case AST.All:
+		return quantifierToString("ALL", nf.GetVarList(), nf.GetForm(), constantsCreated)
+	case AST.Ex:
+		return quantifierToString("EX", nf.GetVarList(), nf.GetForm(), constantsCreated)
+	case AST.AllType:
+		return formToString(nf.GetForm(), constantsCreated)
+	case AST.Pred:
+		args := nf.GetArgs().GetSlice()
+		if nf.GetID().Equals(AST.Id_eq) && len(args) == 2 {
+			return "(" + termToString(args[0], constantsCreated) + " = " + termToString(args[1], constantsCreated) + ")"
+		}
+		return applicationToString(symbolName(nf.GetID()), args, constantsCreated)
+	}
+	Glob.PrintError("Isabelle", fmt.Sprintf("Unexpected formula %s", form.ToString()))
+	return "False"
+}
+
+func formListToString(fl *AST.FormList, sep string, constantsCreated []AST.Term) string {
+	return strings.Join(Glob.MapTo(fl.Slice(), func(_ int, f AST.Form) string { return formToString(f, constantsCreated) }), sep)
+}
+
+func quantifierToString(quant string, varList []AST.Var, form AST.Form, constantsCreated []AST.Term) string {
+	vars := Glob.MapTo(varList, func(_ int, v AST.Var) string {
+		return sanitize(v.GetName()) + "::" + typeToString(v.GetTypeHint())
+	})
+	return "(" + quant + " " + strings.Join(vars, " ") + ". " + formToString(form, constantsCreated) + ")"
+}
+
+func termToString(term AST.Term, constantsCreated []AST.Term) string {
+	switch t := term.(type) {
+	case AST.Fun:
+		if hasBeenCreated(constantsCreated, t) {
+			return symbolName(t.GetID())
+		}
+		return applicationToString(symbolName(t.GetID()), t.GetArgs().GetSlice(), constantsCreated)
+	case AST.Var:
+		return sanitize(t.GetName())
+	case AST.Meta:
+		return metaName(t)
+	case AST.Id:
+		return symbolName(t)
+	}
+	return sanitize(term.ToString())
+}
+
+func applicationToString(head string, args []AST.Term, constantsCreated []AST.Term) string {
+	if len(args) == 0 {
+		return head
+	}
+	return "(" + head + " " + strings.Join(Glob.MapTo(args, func(_ int, t AST.Term) string { return termToString(t, constantsCreated) }), " ") + ")"
+}
+
+// Maps TPTP's types to Isabelle's types. Functional types are curried.
+func typeToString(ts AST.TypeScheme) string {
+	switch t := ts.(type) {
+	case AST.TypeHint:
+		switch t.ToString() {
+		case "$i", "i":
+			return "goeland_U"
+		case "$o", "o":
+			return "bool"
+		case "$int":
+			return "int"
+		case "$rat":
+			return "rat"
+		case "$real":
+			return "real"
+		}
+		return sanitize(t.ToString())
+	case AST.TypeCross:
+		return strings.Join(Glob.MapTo(t.GetAllUnderlyingTypes(), func(_ int, ta AST.TypeApp) string {
+			return typeToString(Glob.To[AST.TypeScheme](ta))
+		}), " => ")
+	case AST.TypeArrow:
+		types := Glob.MapTo(AST.GetInputType(t), func(_ int, ta AST.TypeApp) string {
+			return typeToString(Glob.To[AST.TypeScheme](ta))
+		})
+		types = append(types, typeToString(Glob.To[AST.TypeScheme](AST.GetOutType(t))))
+		return "(" + strings.Join(types, " => ") + ")"
+	}
+	return "goeland_U"
+}
+
+func symbolName(id AST.Id) string {
+	return sanitize(AST.ToStringId(id))
+}
+
+func metaName(meta AST.Meta) string {
+	return sanitize(fmt.Sprintf("%s_%d", meta.GetName(), meta.GetIndex()))
+}
+
+// Turns a TPTP name into an Isabelle identifier.
+func sanitize(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteRune('_')
+		}
+	}
+	result := builder.String()
+	if result == "" || !((result[0] >= 'a' && result[0] <= 'z') || (result[0] >= 'A' && result[0] <= 'Z')) {
+		result = "g" + result
+	}
+	return result
+}
+
+func hasBeenCreated(constantsCreated []AST.Term, fun AST.Fun) bool {
+	for _, t := range constantsCreated {
+		if created, isFun := t.(AST.Fun); isFun && created.GetID().Equals(fun.GetID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Context flag utility function
+func GetContextEnabled() bool {
+	return contextEnabled
+}
+
+// Context flag utility function
+func SetContextEnabled(ce bool) {
+	contextEnabled = ce
+}
@@ -0,0 +1,236 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides an Isar proof from Goéland's proof. Every GS3 step
+* refutes its hypotheses: its goal is False, and it is replayed with the
+* lemma of its rule (see context.go), whose premises are the goals of its
+* children.
+**/
+
+package isabelle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+)
+
+// An Isar proof of False from the hypotheses. The proofs of the premises are
+// indented when they are nested in the proof of their rule.
+var printer = gs3.Printer[string]{
+	Name:         "Isabelle",
+	Closure:      closureStep,
+	Rule:         ruleStep,
+	Rewrite:      rewriteStep,
+	RewriteAsNew: true,
+	VariableName: symbolName,
+	Unsupported:  "sorry\n",
+}
+
+// The lemmas of the rules, see context.go.
+var ruleLemmas = map[gs3.Rule]string{
+	gs3.NNOT: "goeland_nnot",
+	gs3.AND:  "goeland_and",
+	gs3.NOR:  "goeland_notor",
+	gs3.NIMP: "goeland_notimply",
+	gs3.NAND: "goeland_notand",
+	gs3.NEQU: "goeland_notequiv",
+	gs3.OR:   "goeland_or",
+	gs3.IMP:  "goeland_imply",
+	gs3.EQU:  "goeland_equiv",
+	gs3.NALL: "goeland_notall",
+	gs3.EX:   "goeland_ex",
+	gs3.ALL:  "goeland_all",
+	gs3.NEX:  "goeland_notex",
+}
+
+func makeIsabelleProofFromGS3(proof *gs3.GS3Sequent) string {
+	theorem, proofString := gs3.PrintProof(proof, printer)
+	resultingString := makeTheorem(theorem.Axioms, theorem.Conjecture)
+
+	if theorem.Conjecture == nil {
+		return resultingString + proofString
+	}
+
+	index := theorem.Axioms.Len()
+	resultingString += "proof (rule ccontr)\n"
+	resultingString += fmt.Sprintf("  assume %s: \"%s\"\n", introName(index), formToString(AST.MakerNot(theorem.Conjecture), []AST.Term{}))
+	resultingString += "  show False\n"
+	resultingString += indent(proofString, 2)
+	return resultingString + "qed\n"
+}
+
+// Applies the lemma of the rule, and proves each of its premises with the
+// corresponding child. The delta rules fix the skolem constant they generate
+// before assuming their result, and the cuts give their formula.
+func ruleStep(proof *gs3.GS3Sequent, target int, branch gs3.Branch, premises []gs3.Premise[string]) string {
+	lemma := ruleLemmas[proof.Rule()]
+	switch {
+	case proof.Rule() == gs3.CUT:
+		lemma = fmt.Sprintf("goeland_cut[where P = \"%s\"]", formToString(proof.GetCutFormula(), branch.Constants))
+	case gs3.IsBetaRule(proof.Rule()):
+		lemma = fmt.Sprintf("%s[OF _ _ %s]", lemma, introName(target))
+	case gs3.IsGammaRule(proof.Rule()):
+		lemma = fmt.Sprintf("%s[where t = \"%s\", OF _ %s]", lemma, instanceName(proof.TermGenerated(), branch.Constants), introName(target))
+	default:
+		lemma = fmt.Sprintf("%s[OF _ %s]", lemma, introName(target))
+	}
+
+	resultingString := "proof (rule " + lemma + ")\n"
+	for i, premise := range premises {
+		if i > 0 {
+			resultingString += "next\n"
+		}
+		if gs3.IsDeltaRule(proof.Rule()) {
+			resultingString += fmt.Sprintf("  fix %s :: \"%s\"\n", premise.Variable, typeToString(quantifiedVarType(proof.GetTargetForm())))
+		}
+		resultingString += indent(assumptions(premise), 1)
+		resultingString += "  show False\n"
+		resultingString += indent(premise.Proof, 2)
+	}
+	return resultingString + "qed\n"
+}
+
+func rewriteStep(proof *gs3.GS3Sequent, target, rule int, branch gs3.Branch, premise gs3.Premise[string]) string {
+	index := premise.Introduced[0]
+	resultingString := "proof -\n"
+	resultingString += fmt.Sprintf(
+		"  have %s: \"%s\" using %s %s by blast\n",
+		introName(index),
+		formToString(premise.Hypotheses.Get(index), premise.Constants),
+		introName(rule),
+		introName(target),
+	)
+	resultingString += "  show False\n"
+	resultingString += indent(premise.Proof, 2)
+	return resultingString + "qed\n"
+}
+
+// Closes a branch: with the complement of the target formula if it is among
+// the hypotheses, and with metis on the whole branch otherwise.
+func closureStep(target AST.Form, branch gs3.Branch) string {
+	index, _ := branch.Hypotheses.GetIndexOf(target)
+	switch nf := target.(type) {
+	case AST.Bot:
+		return fmt.Sprintf("by (rule %s)\n", introName(index))
+	case AST.Not:
+		if _, isTop := nf.GetForm().(AST.Top); isTop {
+			return fmt.Sprintf("by (rule goeland_nottrue[OF %s])\n", introName(index))
+		}
+		if gs3.IsReflexiveEquality(nf.GetForm()) {
+			return fmt.Sprintf("by (rule goeland_noteq[OF %s])\n", introName(index))
+		}
+		if positive, _ := branch.Hypotheses.GetIndexOf(nf.GetForm()); positive != -1 && index != -1 {
+			return fmt.Sprintf("by (rule goeland_notnot[OF %s %s])\n", introName(positive), introName(index))
+		}
+	default:
+		if negative, _ := branch.Hypotheses.GetIndexOf(AST.MakerNot(target)); negative != -1 && index != -1 {
+			return fmt.Sprintf("by (rule goeland_notnot[OF %s %s])\n", introName(index), introName(negative))
+		}
+	}
+
+	names := []string{}
+	for i, form := range branch.Hypotheses.Slice() {
+		if _, isTop := form.(AST.Top); !isTop {
+			names = append(names, introName(i))
+		}
+	}
+	return "using " + strings.Join(names, " ") + " by metis\n"
+}
+
+// Prints the theorem's name and its statement: the axioms are assumed, and
+// False is shown when there is no conjecture.
+func makeTheorem(axioms *AST.FormList, conjecture AST.Form) string {
+	resultingString := "theorem " + theoryName() + ":\n"
+	for i, form := range axioms.Slice() {
+		keyword := "and"
+		if i == 0 {
+			keyword = "assumes"
+		}
+		resultingString += fmt.Sprintf("  %s %s: \"%s\"\n", keyword, introName(i), formToString(form, []AST.Term{}))
+	}
+	if conjecture == nil {
+		return resultingString + "  shows False\n"
+	}
+	return resultingString + fmt.Sprintf("  shows \"%s\"\n", formToString(conjecture, []AST.Term{}))
+}
+
+// Assumes the formulas that the rule introduces in the premise.
+func assumptions(premise gs3.Premise[string]) string {
+	assumed := Glob.MapTo(premise.Introduced, func(_ int, index int) string {
+		return fmt.Sprintf("%s: \"%s\"", introName(index), formToString(premise.Hypotheses.Get(index), premise.Constants))
+	})
+	return "assume " + strings.Join(assumed, " and ") + "\n"
+}
+
+// Indents every line of the proof by depth levels.
+func indent(proof string, depth int) string {
+	prefix := strings.Repeat("  ", depth)
+	lines := strings.SplitAfter(proof, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// Makes an Isabelle's name for a new hypothesis.
+func introName(i int) string {
+	return fmt.Sprintf("H%d", i)
+}
+
+// The type of the variable eliminated by a delta rule.
+func quantifiedVarType(form AST.Form) AST.TypeScheme {
+	if not, isNot := form.(AST.Not); isNot {
+		form = not.GetForm()
+	}
+	switch nf := form.(type) {
+	case AST.All:
+		return nf.GetVarList()[0].GetTypeHint()
+	case AST.Ex:
+		return nf.GetVarList()[0].GetTypeHint()
+	}
+	return AST.MkTypeHint("$i")
+}
+
+// The term instantiating a gamma rule, undefined when any term fits.
+func instanceName(term AST.Term, constantsCreated []AST.Term) string {
+	if term == nil {
+		return "undefined"
+	}
+	return termToString(term, constantsCreated)
+}
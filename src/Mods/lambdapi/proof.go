@@ -97,6 +97,10 @@ func makeProofStep(proof *gs3.GS3Sequent) string {
 	case gs3.NEX:
 		resultingString = gammaNotEx(proof)
 
+	// Cut rule: ¬C is C → ⊥, so the refutation of ¬C is applied on the one of C.
+	case gs3.CUT:
+		resultingString = getRecursionUnivStr(proof.Children(), proof.GetResultFormulasOfChildren())
+
 	// Weakening rule
 	case gs3.W:
 		Glob.PrintError("LP", "Trying to do a weakening rule but it's not implemented yet")
@@ -111,12 +115,12 @@ func closureAxiom(proof *gs3.GS3Sequent) string {
 	result := ""
 
 	switch target.(type) {
-	case AST.Pred:
-		result = fmt.Sprintf("GS3axiom (%s) (%s) (%s)\n", toCorrectString(target), getFromContext(target), getFromContext(notTarget))
 	case AST.Top:
 		result = fmt.Sprintf("GS3ntop (%s)\n", getFromContext(notTarget))
 	case AST.Bot:
 		result = fmt.Sprintf("GS3bot (%s)\n", getFromContext(target))
+	default:
+		result = fmt.Sprintf("GS3axiom (%s) (%s) (%s)\n", toCorrectString(target), getFromContext(target), getFromContext(notTarget))
 	}
 
 	return result
@@ -0,0 +1,263 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides Lean's context for a proof: the declarations of the
+* signature, and the lemmas and tactics that replay the GS3 rules.
+**/
+
+package lean
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+)
+
+func makeContextIfNeeded(root AST.Form, metaList Lib.List[AST.Meta]) string {
+	if !GetContextEnabled() {
+		return ""
+	}
+
+	resultingString := contextPreamble()
+
+	if Glob.IsLoaded("dmt") {
+		registeredAxioms := dmt.GetRegisteredAxioms()
+		registeredAxioms.Append(root)
+		root = AST.MakerAnd(registeredAxioms)
+	}
+
+	if !AST.EmptyGlobalContext() {
+		resultingString += strings.Join(getTypesFromGlobalContext(), "\n") + "\n"
+	}
+
+	resultingString += strings.Join(getContextFromFormula(root), "\n") + "\n"
+
+	if metaList.Len() > 0 {
+		resultingString += contextualizeMetas(metaList)
+	}
+	return resultingString
+}
+
+func contextPreamble() string {
+	str := lemmas
+	str += "axiom goeland_U : Type -- goeland's universe\n"
+	str += "axiom goeland_I : goeland_U -- an individual in the universe.\n"
+	str += "instance : Inhabited goeland_U := ⟨goeland_I⟩\n\n"
+	return str
+}
+
+// The types declared in the TFF signature. They are inhabited, so that any
+// term can be chosen to instantiate a gamma formula.
+func getTypesFromGlobalContext() []string {
+	result := []string{}
+	for k, v := range AST.GetGlobalContext() {
+		if len(v) == 0 {
+			continue
+		}
+		if typed, ok := v[0].App.(AST.TypeHint); ok && k[0] != '$' && k == typed.ToString() {
+			if name := typeToString(typed); name == sanitize(k) {
+				result = append(result, fmt.Sprintf("axiom %s : Type\naxiom %s : Inhabited %s\nattribute [instance] %s", name, inhabitedName(k), name, inhabitedName(k)))
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func getContextFromFormula(root AST.Form) []string {
+	result := []string{}
+	switch nf := root.(type) {
+	case AST.All:
+		result = getContextFromFormula(nf.GetForm())
+	case AST.Ex:
+		result = getContextFromFormula(nf.GetForm())
+	case AST.AllType:
+		result = getContextFromFormula(nf.GetForm())
+	case AST.And:
+		for _, f := range nf.FormList.Slice() {
+			result = append(result, clean(result, getContextFromFormula(f))...)
+		}
+	case AST.Or:
+		for _, f := range nf.FormList.Slice() {
+			result = append(result, clean(result, getContextFromFormula(f))...)
+		}
+	case AST.Imp:
+		result = clean(result, getContextFromFormula(nf.GetF1()))
+		result = append(result, clean(result, getContextFromFormula(nf.GetF2()))...)
+	case AST.Equ:
+		result = clean(result, getContextFromFormula(nf.GetF1()))
+		result = append(result, clean(result, getContextFromFormula(nf.GetF2()))...)
+	case AST.Not:
+		result = clean(result, getContextFromFormula(nf.GetForm()))
+	case AST.Pred:
+		if !nf.GetID().Equals(AST.Id_eq) {
+			result = append(result, declaration(nf.GetID(), nf.GetType()))
+		}
+		for _, term := range nf.GetArgs().GetSlice() {
+			result = append(result, clean(result, getContextFromTerm(term))...)
+		}
+	}
+	return result
+}
+
+func getContextFromTerm(trm AST.Term) []string {
+	result := []string{}
+	if fun, isFun := trm.(AST.Fun); isFun {
+		result = append(result, declaration(fun.GetID(), fun.GetTypeHint()))
+		for _, term := range fun.GetArgs().GetSlice() {
+			result = append(result, clean(result, getContextFromTerm(term))...)
+		}
+	}
+	return result
+}
+
+// Declares a symbol with the type of the TFF signature when there is one, and
+// with the type of its occurrence otherwise.
+func declaration(id AST.Id, type_ AST.TypeScheme) string {
+	if apps, found := AST.GetGlobalContext()[id.GetName()]; found && len(apps) == 1 {
+		type_ = apps[0].App
+	}
+	return fmt.Sprintf("axiom %s : %s", symbolName(id), typeToString(type_))
+}
+
+// Returns everything in add not in set
+func clean(set, add []string) []string {
+	result := []string{}
+	for _, str := range add {
+		found := false
+		for _, s := range set {
+			if s == str {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+func contextualizeMetas(metaList Lib.List[AST.Meta]) string {
+	result := []string{}
+	for _, meta := range metaList.GetSlice() {
+		result = append(result, fmt.Sprintf("axiom %s : %s", metaName(meta), typeToString(meta.GetTypeHint())))
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+func inhabitedName(typeName string) string {
+	return sanitize("goeland_inhabited_" + typeName)
+}
+
+var lemmas = `-- Every GS3 rule refutes its hypothesis: the premises of its lemma are the
+-- goals of its children.
+
+theorem goeland_notnot {P : Prop} (h : P) (hn : ¬P) : False := hn h
+
+theorem goeland_nottrue (h : ¬True) : False := h trivial
+
+theorem goeland_noteq {T : Type} {t : T} (h : ¬(t = t)) : False := h rfl
+
+theorem goeland_nnot {P : Prop} (k : P → False) (h : ¬¬P) : False := h k
+
+theorem goeland_and {P Q : Prop} (k : P → Q → False) (h : P ∧ Q) : False := k h.1 h.2
+
+theorem goeland_or {P Q : Prop} (k1 : P → False) (k2 : Q → False) (h : P ∨ Q) : False :=
+  h.elim k1 k2
+
+theorem goeland_imply {P Q : Prop} (k1 : ¬P → False) (k2 : Q → False) (h : P → Q) : False :=
+  (Classical.em P).elim (fun hp => k2 (h hp)) k1
+
+theorem goeland_equiv {P Q : Prop} (k1 : ¬P → ¬Q → False) (k2 : P → Q → False) (h : P ↔ Q) : False :=
+  (Classical.em P).elim (fun hp => k2 hp (h.mp hp)) (fun hnp => k1 hnp (fun hq => hnp (h.mpr hq)))
+
+theorem goeland_notand {P Q : Prop} (k1 : ¬P → False) (k2 : ¬Q → False) (h : ¬(P ∧ Q)) : False :=
+  k1 (fun hp => k2 (fun hq => h ⟨hp, hq⟩))
+
+theorem goeland_notor {P Q : Prop} (k : ¬P → ¬Q → False) (h : ¬(P ∨ Q)) : False :=
+  k (fun hp => h (Or.inl hp)) (fun hq => h (Or.inr hq))
+
+theorem goeland_notimply {P Q : Prop} (k : P → ¬Q → False) (h : ¬(P → Q)) : False :=
+  (Classical.em P).elim (fun hp => k hp (fun hq => h (fun _ => hq))) (fun hnp => h (fun hp => absurd hp hnp))
+
+theorem goeland_notequiv {P Q : Prop} (k1 : ¬P → Q → False) (k2 : P → ¬Q → False) (h : ¬(P ↔ Q)) : False :=
+  (Classical.em P).elim
+    (fun hp => k2 hp (fun hq => h ⟨fun _ => hq, fun _ => hp⟩))
+    (fun hnp => (Classical.em Q).elim (fun hq => k1 hnp hq) (fun hnq => h ⟨fun hp => absurd hp hnp, fun hq => absurd hq hnq⟩))
+
+theorem goeland_ex {T : Type} {P : T → Prop} (k : ∀ z, P z → False) (h : ∃ x, P x) : False :=
+  h.elim k
+
+theorem goeland_all {T : Type} {P : T → Prop} (t : T) (k : P t → False) (h : ∀ x, P x) : False :=
+  k (h t)
+
+theorem goeland_notex {T : Type} {P : T → Prop} (t : T) (k : ¬P t → False) (h : ¬∃ x, P x) : False :=
+  k (fun hp => h ⟨t, hp⟩)
+
+theorem goeland_notall {T : Type} {P : T → Prop} (k : ∀ z, ¬P z → False) (h : ¬∀ x, P x) : False :=
+  h (fun x => Classical.byContradiction (k x))
+
+theorem goeland_cut (P : Prop) (k1 : ¬P → False) (k2 : P → False) : False :=
+  k1 (fun hp => k2 hp)
+
+-- Alpha rules: the hypothesis h is decomposed in a single branch.
+macro "goeland_alpha " l:ident h:term:max : tactic => ` + "`" + `(tactic| refine $l ?_ $h)
+
+-- Beta rules: the hypothesis h is split in two branches.
+macro "goeland_beta " l:ident h:term:max : tactic => ` + "`" + `(tactic| refine $l ?_ ?_ $h)
+
+-- Cuts: the formula p is refuted in the first branch, and its negation in the
+-- second one.
+macro "goeland_cut " p:term:max : tactic => ` + "`" + `(tactic| refine goeland_cut $p ?_ ?_)
+
+-- Gamma rules, also used to reintroduce a gamma formula: the hypothesis h is
+-- instantiated with t.
+macro "goeland_gamma " l:ident h:term:max t:term:max : tactic => ` + "`" + `(tactic| refine $l $t ?_ $h)
+
+-- Delta rules: the witness of the hypothesis h is introduced by the next intro.
+macro "goeland_delta " l:ident h:term:max : tactic => ` + "`" + `(tactic| refine $l ?_ $h)
+
+-- Rewrite steps of the deduction modulo theory: h is rewritten with the rule r.
+macro "goeland_rewrite " r:term:max " at " h:ident : tactic =>
+  ` + "`" + `(tactic| first | rw [$r:term] at $h:ident | simp only [$r:term] at $h:ident)
+
+-- Closure of a branch that needs reasoning on the equalities.
+macro "goeland_auto" : tactic => ` + "`" + `(tactic| first | contradiction | (subst_vars; contradiction) | simp_all)
+
+`
@@ -0,0 +1,243 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides a Lean 4 output for Goeland's proofs.
+**/
+
+package lean
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+var contextEnabled bool = false
+
+// Whether the proof uses the rationals or the reals, which are defined in
+// Mathlib and not in the core library of Lean.
+var usesMathlib bool = false
+
+const mathlibImport = "import Mathlib.Data.Real.Basic\n"
+
+var LeanOutputProofStruct = &Search.OutputProofStruct{ProofOutput: MakeLeanOutput, Name: "Lean", Extension: ".lean"}
+
+// ----------------------------------------------------------------------------
+// Plugin initialisation and main function to call.
+
+// Section: init
+// Functions: MakeLeanOutput
+// Main functions of the lean module.
+
+func MakeLeanOutput(prf []Search.ProofStruct, meta Lib.List[AST.Meta]) string {
+	if len(prf) == 0 {
+		Glob.PrintError("Lean", "Nothing to output")
+		return ""
+	}
+
+	// Transform tableaux's proof in GS3 proof
+	return MakeLeanProof(gs3.MakeGS3Proof(prf), meta)
+}
+
+var MakeLeanProof = func(proof *gs3.GS3Sequent, meta Lib.List[AST.Meta]) string {
+	usesMathlib = false
+	contextString := makeContextIfNeeded(proof.GetTargetForm(), meta)
+	proofString := makeLeanProofFromGS3(proof)
+	if GetContextEnabled() && usesMathlib {
+		// Imports come first in a Lean file.
+		return mathlibImport + contextString + "\n" + proofString
+	}
+	return contextString + "\n" + proofString
+}
+
+// ----------------------------------------------------------------------------
+// Printing of formulas, terms and types in Lean's syntax.
+
+// Prints a formula. The skolem symbols of constantsCreated are the variables
+// introduced by the delta steps: they are printed without their arguments.
+func formToString(form AST.Form, constantsCreated []AST.Term) string {
+	switch nf := form.(type) {
+	case AST.Top:
+		return "True"
+	case AST.Bot:
+		return "False"
+	case AST.Not:
+		return "(¬ " + formToString(nf.GetForm(), constantsCreated) + ")"
+	case AST.And:
+		return "(" + formListToString(nf.FormList, " ∧ ", constantsCreated) + ")"
+	case AST.Or:
+		return "(" + formListToString(nf.FormList, " ∨ ", constantsCreated) + ")"
+	case AST.Imp:
+		return "(" + formToString(nf.GetF1(), constantsCreated) + " → " + formToString(nf.GetF2(), constantsCreated) + ")"
+	case AST.Equ:
+		return "(" + formToString(nf.GetF1(), constantsCreated) + " ↔ " + formToString(nf.GetF2(), constantsCreated) + ")"
+	case AST.All:
+		return quantifierToString("∀", nf.GetVarList(), nf.GetForm(), constantsCreated)
+	case AST.Ex:
+		return quantifierToString("∃", nf.GetVarList(), nf.GetForm(), constantsCreated)
+	case AST.AllType:
+		return formToString(nf.GetForm(), constantsCreated)
+	case AST.Pred:
+		args := nf.GetArgs().GetSlice()
+		if nf.GetID().Equals(AST.Id_eq) && len(args) == 2 {
+			return "(" + termToString(args[0], constantsCreated) + " = " + termToString(args[1], constantsCreated) + ")"
+		}
+		return applicationToString(symbolName(nf.GetID()), args, constantsCreated)
+	}
+	Glob.PrintError("Lean", fmt.Sprintf("Unexpected formula %s", form.ToString()))
+	return "False"
+}
+
+func formListToString(fl *AST.FormList, sep string, constantsCreated []AST.Term) string {
+	return strings.Join(Glob.MapTo(fl.Slice(), func(_ int, f AST.Form) string { return formToString(f, constantsCreated) }), sep)
+}
+
+func quantifierToString(quant string, varList []AST.Var, form AST.Form, constantsCreated []AST.Term) string {
+	vars := Glob.MapTo(varList, func(_ int, v AST.Var) string {
+		return "(" + sanitize(v.GetName()) + " : " + typeToString(v.GetTypeHint()) + ")"
+	})
+	return "(" + quant + " " + strings.Join(vars, " ") + ", " + formToString(form, constantsCreated) + ")"
+}
+
+func termToString(term AST.Term, constantsCreated []AST.Term) string {
+	switch t := term.(type) {
+	case AST.Fun:
+		if hasBeenCreated(constantsCreated, t) {
+			return symbolName(t.GetID())
+		}
+		return applicationToString(symbolName(t.GetID()), t.GetArgs().GetSlice(), constantsCreated)
+	case AST.Var:
+		return sanitize(t.GetName())
+	case AST.Meta:
+		return metaName(t)
+	case AST.Id:
+		return symbolName(t)
+	}
+	return sanitize(term.ToString())
+}
+
+func applicationToString(head string, args []AST.Term, constantsCreated []AST.Term) string {
+	if len(args) == 0 {
+		return head
+	}
+	return "(" + head + " " + strings.Join(Glob.MapTo(args, func(_ int, t AST.Term) string { return termToString(t, constantsCreated) }), " ") + ")"
+}
+
+// Maps TPTP's types to Lean's types. Functional types are curried.
+func typeToString(ts AST.TypeScheme) string {
+	switch t := ts.(type) {
+	case AST.TypeHint:
+		switch t.ToString() {
+		case "$i", "i":
+			return "goeland_U"
+		case "$o", "o":
+			return "Prop"
+		case "$int":
+			return "Int"
+		case "$rat":
+			usesMathlib = true
+			return "Rat"
+		case "$real":
+			usesMathlib = true
+			return "Real"
+		}
+		return sanitize(t.ToString())
+	case AST.TypeCross:
+		return strings.Join(Glob.MapTo(t.GetAllUnderlyingTypes(), func(_ int, ta AST.TypeApp) string {
+			return typeToString(Glob.To[AST.TypeScheme](ta))
+		}), " → ")
+	case AST.TypeArrow:
+		types := Glob.MapTo(AST.GetInputType(t), func(_ int, ta AST.TypeApp) string {
+			return typeToString(Glob.To[AST.TypeScheme](ta))
+		})
+		types = append(types, typeToString(Glob.To[AST.TypeScheme](AST.GetOutType(t))))
+		return "(" + strings.Join(types, " → ") + ")"
+	}
+	return "goeland_U"
+}
+
+func symbolName(id AST.Id) string {
+	return sanitize(AST.ToStringId(id))
+}
+
+func metaName(meta AST.Meta) string {
+	return sanitize(fmt.Sprintf("%s_%d", meta.GetName(), meta.GetIndex()))
+}
+
+var keywords = map[string]bool{
+	"at": true, "axiom": true, "by": true, "def": true, "do": true, "else": true, "end": true,
+	"example": true, "fun": true, "from": true, "have": true, "if": true, "import": true, "in": true,
+	"instance": true, "let": true, "match": true, "namespace": true, "open": true, "show": true,
+	"then": true, "theorem": true, "universe": true, "variable": true, "where": true, "with": true,
+	"Prop": true, "Sort": true, "Type": true, "True": true, "False": true,
+}
+
+// Turns a TPTP name into a Lean identifier, between guillemets when it is not
+// a plain identifier.
+func sanitize(name string) string {
+	plain := name != "" && !keywords[name]
+	for i, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (i == 0 || !((r >= '0' && r <= '9') || r == '_' || r == '\'')) {
+			plain = false
+		}
+	}
+	if plain {
+		return name
+	}
+	return "«" + strings.NewReplacer("«", "", "»", "").Replace(name) + "»"
+}
+
+func hasBeenCreated(constantsCreated []AST.Term, fun AST.Fun) bool {
+	for _, t := range constantsCreated {
+		if created, isFun := t.(AST.Fun); isFun && created.GetID().Equals(fun.GetID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Context flag utility function
+func GetContextEnabled() bool {
+	return contextEnabled
+}
+
+// Context flag utility function
+func SetContextEnabled(ce bool) {
+	contextEnabled = ce
+}
@@ -0,0 +1,190 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides a Lean proof from Goéland's proof. The goal of every GS3
+* step is False, and each rule is replayed with the tactic of its kind (see
+* context.go).
+**/
+
+package lean
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+)
+
+// The tactics proving False from the hypotheses, one per line.
+var printer = gs3.Printer[[]string]{
+	Name:         "Lean",
+	Closure:      closureStep,
+	Rule:         ruleStep,
+	Rewrite:      rewriteStep,
+	VariableName: symbolName,
+	Unsupported:  []string{"sorry"},
+}
+
+// The lemmas of the rules, see context.go.
+var ruleLemmas = map[gs3.Rule]string{
+	gs3.NNOT: "goeland_nnot",
+	gs3.AND:  "goeland_and",
+	gs3.NOR:  "goeland_notor",
+	gs3.NIMP: "goeland_notimply",
+	gs3.NAND: "goeland_notand",
+	gs3.NEQU: "goeland_notequiv",
+	gs3.OR:   "goeland_or",
+	gs3.IMP:  "goeland_imply",
+	gs3.EQU:  "goeland_equiv",
+	gs3.NALL: "goeland_notall",
+	gs3.EX:   "goeland_ex",
+	gs3.ALL:  "goeland_all",
+	gs3.NEX:  "goeland_notex",
+}
+
+func makeLeanProofFromGS3(proof *gs3.GS3Sequent) string {
+	theorem, steps := gs3.PrintProof(proof, printer)
+	resultingString := makeTheorem(theorem.Axioms, theorem.Conjecture)
+
+	if theorem.Conjecture != nil {
+		steps = append([]string{"apply Classical.byContradiction", "intro " + introName(theorem.Axioms.Len())}, steps...)
+	}
+	for _, step := range steps {
+		resultingString += "  " + step + "\n"
+	}
+	return resultingString
+}
+
+// Each branch of a beta rule or of a cut is a bullet.
+func ruleStep(proof *gs3.GS3Sequent, target int, branch gs3.Branch, premises []gs3.Premise[[]string]) []string {
+	lemma := ruleLemmas[proof.Rule()]
+	switch {
+	case gs3.IsBetaRule(proof.Rule()), proof.Rule() == gs3.CUT:
+		steps := []string{fmt.Sprintf("goeland_beta %s %s", lemma, introName(target))}
+		if proof.Rule() == gs3.CUT {
+			steps = []string{fmt.Sprintf("goeland_cut (%s)", formToString(proof.GetCutFormula(), branch.Constants))}
+		}
+		for _, premise := range premises {
+			for j, step := range append([]string{"intro " + introNames(premise.Introduced)}, premise.Proof...) {
+				if j == 0 {
+					steps = append(steps, "· "+step)
+				} else {
+					steps = append(steps, "  "+step)
+				}
+			}
+		}
+		return steps
+	case gs3.IsDeltaRule(proof.Rule()):
+		steps := []string{
+			fmt.Sprintf("goeland_delta %s %s", lemma, introName(target)),
+			"intro " + premises[0].Variable + " " + introNames(premises[0].Introduced),
+		}
+		return append(steps, premises[0].Proof...)
+	case gs3.IsGammaRule(proof.Rule()):
+		steps := []string{
+			fmt.Sprintf("goeland_gamma %s %s %s", lemma, introName(target), instanceName(proof.TermGenerated(), branch.Constants)),
+			"intro " + introNames(premises[0].Introduced),
+		}
+		return append(steps, premises[0].Proof...)
+	}
+	steps := []string{fmt.Sprintf("goeland_alpha %s %s", lemma, introName(target)), "intro " + introNames(premises[0].Introduced)}
+	return append(steps, premises[0].Proof...)
+}
+
+// The hypothesis is rewritten in place, as in the Coq output.
+func rewriteStep(proof *gs3.GS3Sequent, target, rule int, branch gs3.Branch, premise gs3.Premise[[]string]) []string {
+	return append([]string{fmt.Sprintf("goeland_rewrite %s at %s", introName(rule), introName(target))}, premise.Proof...)
+}
+
+// Closes a branch: with the complement of the target formula if it is among
+// the hypotheses, and with goeland_auto otherwise.
+func closureStep(target AST.Form, branch gs3.Branch) []string {
+	index, _ := branch.Hypotheses.GetIndexOf(target)
+	if index == -1 {
+		return []string{"goeland_auto"}
+	}
+	switch nf := target.(type) {
+	case AST.Bot:
+		return []string{"exact " + introName(index)}
+	case AST.Not:
+		if _, isTop := nf.GetForm().(AST.Top); isTop {
+			return []string{"exact goeland_nottrue " + introName(index)}
+		}
+		if gs3.IsReflexiveEquality(nf.GetForm()) {
+			return []string{"exact goeland_noteq " + introName(index)}
+		}
+		if positive, _ := branch.Hypotheses.GetIndexOf(nf.GetForm()); positive != -1 {
+			return []string{fmt.Sprintf("exact goeland_notnot %s %s", introName(positive), introName(index))}
+		}
+	default:
+		if negative, _ := branch.Hypotheses.GetIndexOf(AST.MakerNot(target)); negative != -1 {
+			return []string{fmt.Sprintf("exact goeland_notnot %s %s", introName(index), introName(negative))}
+		}
+	}
+	return []string{"goeland_auto"}
+}
+
+// Prints the theorem's name and its statement: the axioms are its hypotheses,
+// and its conclusion is False when there is no conjecture.
+func makeTheorem(axioms *AST.FormList, conjecture AST.Form) string {
+	problemName := sanitize("goeland_proof_of_" + strings.NewReplacer(".", "_", "=", "_", "+", "_", "-", "_").Replace(Glob.GetProblemName()))
+	resultingString := "theorem " + problemName
+	for i, form := range axioms.Slice() {
+		resultingString += fmt.Sprintf("\n    (%s : %s)", introName(i), formToString(form, []AST.Term{}))
+	}
+	conclusion := "False"
+	if conjecture != nil {
+		conclusion = formToString(conjecture, []AST.Term{})
+	}
+	return resultingString + " :\n    " + conclusion + " := by\n"
+}
+
+// Makes a Lean's name for a new hypothesis.
+func introName(i int) string {
+	return fmt.Sprintf("H%d", i)
+}
+
+func introNames(il []int) string {
+	return strings.Join(Glob.MapTo(il, func(_ int, f int) string { return introName(f) }), " ")
+}
+
+// The term instantiating a gamma rule, the default inhabitant of its type when
+// any term fits.
+func instanceName(term AST.Term, constantsCreated []AST.Term) string {
+	if term == nil {
+		return "default"
+	}
+	return termToString(term, constantsCreated)
+}
@@ -0,0 +1,584 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides a checker of SC-TPTP proofs, e.g., the ones of the -osctptp
+* output. Every step is checked against the rules of the output (see makeStep).
+**/
+
+package tptp
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+// Error of an SC-TPTP proof, raised at the given step.
+type CheckError struct {
+	Step string
+	Msg  string
+}
+
+func (e CheckError) Error() string {
+	return fmt.Sprintf("step %s: %s", e.Step, e.Msg)
+}
+
+// Checks an SC-TPTP proof. Every step must be a valid application of a rule on
+// its premises, which are steps or axioms, no step may depend on itself, and the
+// conjecture must be proven without hypotheses (or the empty sequent when there
+// is no conjecture). The terms are not type-checked.
+func CheckSCTPTPProof(statements []Parser.PSCTPTPStatement) error {
+	sequents := make(map[string]Parser.PSequent)
+	names := make(map[string]bool)
+	steps := []Parser.PSCTPTPStatement{}
+	conjecture := Lib.MkNone[Parser.PForm]()
+
+	for _, stmt := range statements {
+		if names[stmt.Name()] {
+			return CheckError{stmt.Name(), "the name is already used"}
+		}
+		names[stmt.Name()] = true
+
+		switch sequent := stmt.Sequent().(type) {
+		case Lib.Some[Parser.PSequent]:
+			if _, isStep := stmt.Inference().(Lib.Some[Parser.PInference]); !isStep {
+				return CheckError{stmt.Name(), "the sequent has no inference"}
+			}
+			sequents[stmt.Name()] = normalizeSequent(sequent.Val)
+			steps = append(steps, stmt)
+		case Lib.None[Parser.PSequent]:
+			form := normalizeForm(stmt.Form().(Lib.Some[Parser.PForm]).Val)
+			switch Parser.PFormulaRoleFromStr(stmt.Role()) {
+			case Parser.Axiom:
+				sequents[stmt.Name()] = Parser.MkPSequent([]Parser.PForm{}, []Parser.PForm{form})
+			case Parser.Conjecture:
+				conjecture = Lib.MkSome(form)
+			default:
+				return CheckError{stmt.Name(), fmt.Sprintf("a %s record should be a sequent", stmt.Role())}
+			}
+		}
+	}
+
+	for _, stmt := range steps {
+		if err := checkStep(stmt, sequents); err != nil {
+			return err
+		}
+	}
+
+	if err := checkAcyclic(steps); err != nil {
+		return err
+	}
+
+	return checkProven(steps, sequents, conjecture)
+}
+
+func checkStep(stmt Parser.PSCTPTPStatement, sequents map[string]Parser.PSequent) error {
+	inference := stmt.Inference().(Lib.Some[Parser.PInference]).Val
+	step := scStep{stmt.Name(), sequents[stmt.Name()], []Parser.PGeneralTerm{}, inference.Premises(), []Parser.PSequent{}}
+
+	rule, found := sctptpRules[inference.Rule()]
+	if !found {
+		return step.fail("unknown rule %s", inference.Rule())
+	}
+
+	for _, name := range inference.Premises() {
+		premise, found := sequents[name]
+		if !found {
+			return step.fail("unknown premise %s", name)
+		}
+		step.premises = append(step.premises, premise)
+	}
+
+	if len(step.premises) != rule.premises {
+		return step.fail("the rule %s expects %d premises, got %d", inference.Rule(), rule.premises, len(step.premises))
+	}
+
+	// The status is not a parameter of the rule.
+	for _, param := range inference.Params() {
+		if fun, isFun := param.(Parser.PGeneralFun); !isFun || fun.Symbol() != "status" {
+			step.params = append(step.params, param)
+		}
+	}
+
+	return rule.check(step)
+}
+
+func checkAcyclic(steps []Parser.PSCTPTPStatement) error {
+	premises := make(map[string][]string)
+	for _, stmt := range steps {
+		premises[stmt.Name()] = stmt.Inference().(Lib.Some[Parser.PInference]).Val.Premises()
+	}
+
+	// 0: not visited, 1: being visited, 2: done
+	state := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			return CheckError{name, "the step depends on itself"}
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, premise := range premises[name] {
+			if err := visit(premise); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		return nil
+	}
+
+	for _, stmt := range steps {
+		if err := visit(stmt.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkProven(steps []Parser.PSCTPTPStatement, sequents map[string]Parser.PSequent, conjecture Lib.Option[Parser.PForm]) error {
+	goal := []Parser.PForm{}
+	if form, isSome := conjecture.(Lib.Some[Parser.PForm]); isSome {
+		goal = append(goal, form.Val)
+	}
+
+	for _, stmt := range steps {
+		sequent := sequents[stmt.Name()]
+		if len(sequent.Left()) == 0 && len(sequent.Right()) == len(goal) && (len(goal) == 0 || equalForms(sequent.Right()[0], goal[0])) {
+			return nil
+		}
+	}
+
+	if len(goal) == 0 {
+		return CheckError{"-", "no step proves the empty sequent"}
+	}
+	return CheckError{"-", "no step proves the conjecture without hypotheses"}
+}
+
+/*** Steps ***/
+
+type scStep struct {
+	name         string
+	conclusion   Parser.PSequent
+	params       []Parser.PGeneralTerm
+	premiseNames []string
+	premises     []Parser.PSequent
+}
+
+func (step scStep) fail(format string, args ...any) error {
+	return CheckError{step.name, fmt.Sprintf(format, args...)}
+}
+
+func (step scStep) intParam(i int) (int, error) {
+	if i >= len(step.params) {
+		return -1, step.fail("the parameter %d is missing", i)
+	}
+	if word, isWord := step.params[i].(Parser.PGeneralWord); isWord {
+		if n, err := strconv.Atoi(word.Word()); err == nil {
+			return n, nil
+		}
+	}
+	return -1, step.fail("the parameter %d should be an integer, got %s", i, step.params[i].ToString())
+}
+
+// Returns the formula of the given side whose index is the i-th parameter.
+func (step scStep) formParam(i int, forms []Parser.PForm, side string) (int, Parser.PForm, error) {
+	index, err := step.intParam(i)
+	if err != nil {
+		return -1, nil, err
+	}
+	if index < 0 || index >= len(forms) {
+		return -1, nil, step.fail("there is no formula %d in the %s-hand side", index, side)
+	}
+	return index, forms[index], nil
+}
+
+func (step scStep) leftParam(i int) (int, Parser.PForm, error) {
+	return step.formParam(i, step.conclusion.Left(), "left")
+}
+
+func (step scStep) rightParam(i int) (int, Parser.PForm, error) {
+	return step.formParam(i, step.conclusion.Right(), "right")
+}
+
+// The premise can only contain the formulas of the conclusion and the ones
+// introduced by the rule.
+func (step scStep) checkPremise(i int, left, right []Parser.PForm) error {
+	premise := step.premises[i]
+	for j, form := range premise.Left() {
+		if !containsForm(step.conclusion.Left(), form) && !containsForm(left, form) {
+			return step.fail("the formula %d of the left-hand side of %s is neither in the conclusion nor introduced by the rule", j, step.premiseNames[i])
+		}
+	}
+	for j, form := range premise.Right() {
+		if !containsForm(step.conclusion.Right(), form) && !containsForm(right, form) {
+			return step.fail("the formula %d of the right-hand side of %s is neither in the conclusion nor introduced by the rule", j, step.premiseNames[i])
+		}
+	}
+	return nil
+}
+
+/*** Rules ***/
+
+type scRule struct {
+	premises int
+	check    func(scStep) error
+}
+
+// The rules of the SC-TPTP output, with their number of premises.
+var sctptpRules = map[string]scRule{
+	// Closure
+	"hyp":        {0, checkHyp},
+	"leftHyp":    {0, checkLeftHyp},
+	"congruence": {0, checkCongruence},
+
+	// Structural rules
+	"cut":        {2, checkCut},
+	"rightNot":   {1, checkRightNot},
+	"leftWeaken": {1, checkLeftWeaken},
+
+	// Alpha rules
+	"leftNotNot":     connectiveRule(1, "a double negation", decomposeNotNot),
+	"leftAnd":        connectiveRule(1, "a conjunction", decomposeAnd),
+	"leftNotOr":      connectiveRule(1, "a negated disjunction", decomposeNotOr),
+	"leftNotImplies": connectiveRule(1, "a negated implication", decomposeNotImplies),
+	"leftIff":        connectiveRule(1, "an equivalence", decomposeIff),
+
+	// Beta rules
+	"leftOr":     connectiveRule(2, "a disjunction", decomposeOr),
+	"leftNotAnd": connectiveRule(2, "a negated conjunction", decomposeNotAnd),
+	"leftImp2":   connectiveRule(2, "an implication", decomposeImplies),
+	"leftNotIff": connectiveRule(2, "a negated equivalence", decomposeNotIff),
+
+	// Delta rules
+	"leftExists": quantifierRule(true, false, Parser.PQuantEx),
+	"leftNotAll": quantifierRule(true, true, Parser.PQuantAll),
+
+	// Gamma rules
+	"leftForall": quantifierRule(false, false, Parser.PQuantAll),
+	"leftNotEx":  quantifierRule(false, true, Parser.PQuantEx),
+}
+
+// hyp: Γ, A --> A, Δ
+func checkHyp(step scStep) error {
+	i, form, err := step.leftParam(0)
+	if err != nil {
+		return err
+	}
+
+	if len(step.params) > 1 {
+		j, right, err := step.rightParam(1)
+		if err != nil {
+			return err
+		}
+		if !equalForms(form, right) {
+			return step.fail("the formula %d of the left-hand side is not the formula %d of the right-hand side", i, j)
+		}
+	} else if !containsForm(step.conclusion.Right(), form) {
+		return step.fail("the formula %d of the left-hand side is not in the right-hand side", i)
+	}
+	return nil
+}
+
+// leftHyp: Γ, A, ~A --> Δ, or Γ, $false --> Δ
+func checkLeftHyp(step scStep) error {
+	i, form, err := step.leftParam(0)
+	if err != nil {
+		return err
+	}
+
+	if isFalse(form) {
+		return nil
+	}
+
+	if len(step.params) > 1 {
+		j, other, err := step.leftParam(1)
+		if err != nil {
+			return err
+		}
+		if !areComplementary(form, other) {
+			return step.fail("the formulas %d and %d of the left-hand side are not complementary", i, j)
+		}
+		return nil
+	}
+
+	for _, other := range step.conclusion.Left() {
+		if areComplementary(form, other) {
+			return nil
+		}
+	}
+	return step.fail("the formula %d of the left-hand side has no complement in it", i)
+}
+
+// congruence: the literals of Γ are contradictory modulo the equalities of Γ.
+func checkCongruence(step scStep) error {
+	if !isClosedByCongruence(step.conclusion.Left()) {
+		return step.fail("the left-hand side is not contradictory modulo its equalities")
+	}
+	return nil
+}
+
+// cut: from Γ --> A, Δ and Γ, A --> Δ, the cut formula A being the i-th one of
+// the right-hand side of the first premise.
+func checkCut(step scStep) error {
+	i, err := step.intParam(0)
+	if err != nil {
+		return err
+	}
+
+	right := step.premises[0].Right()
+	if i < 0 || i >= len(right) {
+		return step.fail("there is no formula %d in the right-hand side of %s", i, step.premiseNames[0])
+	}
+
+	cut := []Parser.PForm{right[i]}
+	if err := step.checkPremise(0, nil, cut); err != nil {
+		return err
+	}
+	return step.checkPremise(1, cut, nil)
+}
+
+// rightNot: from Γ, A --> Δ to Γ --> ~A, Δ
+func checkRightNot(step scStep) error {
+	i, form, err := step.rightParam(0)
+	if err != nil {
+		return err
+	}
+
+	negated, isNot := notForm(form)
+	if !isNot {
+		return step.fail("the formula %d of the right-hand side is not a negation", i)
+	}
+	return step.checkPremise(0, []Parser.PForm{negated}, nil)
+}
+
+// leftWeaken: from Γ --> Δ to Γ, A --> Δ
+func checkLeftWeaken(step scStep) error {
+	if _, _, err := step.leftParam(0); err != nil {
+		return err
+	}
+	return step.checkPremise(0, nil, nil)
+}
+
+// The rules decomposing the i-th formula of the left-hand side, which adds the
+// resulting formulas to the left-hand side of each premise.
+func connectiveRule(premises int, shape string, decompose func(Parser.PForm) ([][]Parser.PForm, bool)) scRule {
+	return scRule{premises, func(step scStep) error {
+		i, form, err := step.leftParam(0)
+		if err != nil {
+			return err
+		}
+
+		results, matches := decompose(form)
+		if !matches {
+			return step.fail("the formula %d of the left-hand side is not %s", i, shape)
+		}
+
+		for j := range step.premises {
+			if err := step.checkPremise(j, results[j], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+}
+
+// The rules instantiating the quantifier of the i-th formula of the left-hand
+// side (under a negation if negated) with the term given as second parameter,
+// which must be a fresh constant for a delta rule.
+func quantifierRule(delta, negated bool, quantifier Parser.PQuantifier) scRule {
+	return scRule{1, func(step scStep) error {
+		i, form, err := step.leftParam(0)
+		if err != nil {
+			return err
+		}
+
+		if negated {
+			form, _ = notForm(form)
+		}
+		quant, isQuant := form.(Parser.PQuant)
+		if !isQuant || quant.PQuantifier != quantifier {
+			return step.fail("the formula %d of the left-hand side is not %s", i, quantifierShape(negated, quantifier))
+		}
+
+		term, err := step.termParam(1, delta)
+		if err != nil {
+			return err
+		}
+
+		result := substituteForm(quant.PForm, quant.Vars()[0].Fst, term)
+		if negated {
+			result = Parser.MkPNeg(result)
+		}
+		return step.checkPremise(0, []Parser.PForm{result}, nil)
+	}}
+}
+
+func quantifierShape(negated bool, quantifier Parser.PQuantifier) string {
+	shape := "a universal formula"
+	if quantifier == Parser.PQuantEx {
+		shape = "an existential formula"
+	}
+	if negated {
+		return "the negation of " + shape
+	}
+	return shape
+}
+
+// The term of a gamma rule is given as $fot(t). The constant of a delta rule is
+// given by its name or as $fot(c), and must not occur in the conclusion.
+func (step scStep) termParam(i int, delta bool) (Parser.PTerm, error) {
+	if i >= len(step.params) {
+		return nil, step.fail("the parameter %d is missing", i)
+	}
+
+	var term Parser.PTerm
+	switch param := step.params[i].(type) {
+	case Parser.PGeneralTermData:
+		term = param.Term()
+	case Parser.PGeneralWord:
+		if delta {
+			term = Parser.MkFunConst(param.Word())
+		}
+	}
+
+	if !delta {
+		if term == nil {
+			return nil, step.fail("the parameter %d should be a term $fot(t), got %s", i, step.params[i].ToString())
+		}
+		return term, nil
+	}
+
+	name, isConstant := constantName(term)
+	if !isConstant {
+		return nil, step.fail("the parameter %d should be a constant, got %s", i, step.params[i].ToString())
+	}
+	if sequentContainsSymbol(step.conclusion, name) {
+		return nil, step.fail("the constant %s is not fresh", name)
+	}
+	return term, nil
+}
+
+/*** Decomposition of the formulas ***/
+
+func notForm(f Parser.PForm) (Parser.PForm, bool) {
+	if not, isNot := f.(Parser.PUnary); isNot && not.PUnaryOp == Parser.PUnaryNeg {
+		return not.PForm, true
+	}
+	return nil, false
+}
+
+func binForm(f Parser.PForm, op Parser.PBinOp) (Parser.PForm, Parser.PForm, bool) {
+	if bin, isBin := f.(Parser.PBin); isBin && bin.Operator() == op {
+		return bin.Left(), bin.Right(), true
+	}
+	return nil, nil, false
+}
+
+func notBinForm(f Parser.PForm, op Parser.PBinOp) (Parser.PForm, Parser.PForm, bool) {
+	if negated, isNot := notForm(f); isNot {
+		return binForm(negated, op)
+	}
+	return nil, nil, false
+}
+
+func decomposeNotNot(f Parser.PForm) ([][]Parser.PForm, bool) {
+	if negated, isNot := notForm(f); isNot {
+		if form, isNotNot := notForm(negated); isNotNot {
+			return [][]Parser.PForm{{form}}, true
+		}
+	}
+	return nil, false
+}
+
+func decomposeAnd(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := binForm(f, Parser.PBinaryAnd)
+	return [][]Parser.PForm{{a, b}}, matches
+}
+
+func decomposeNotOr(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := notBinForm(f, Parser.PBinaryOr)
+	return [][]Parser.PForm{{Parser.MkPNeg(a), Parser.MkPNeg(b)}}, matches
+}
+
+func decomposeNotImplies(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := notBinForm(f, Parser.PBinaryImp)
+	return [][]Parser.PForm{{a, Parser.MkPNeg(b)}}, matches
+}
+
+func decomposeIff(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := binForm(f, Parser.PBinaryEqu)
+	return [][]Parser.PForm{{Parser.MkPImp(a, b), Parser.MkPImp(b, a)}}, matches
+}
+
+func decomposeOr(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := binForm(f, Parser.PBinaryOr)
+	return [][]Parser.PForm{{a}, {b}}, matches
+}
+
+func decomposeNotAnd(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := notBinForm(f, Parser.PBinaryAnd)
+	return [][]Parser.PForm{{Parser.MkPNeg(a)}, {Parser.MkPNeg(b)}}, matches
+}
+
+func decomposeImplies(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := binForm(f, Parser.PBinaryImp)
+	return [][]Parser.PForm{{Parser.MkPNeg(a)}, {b}}, matches
+}
+
+func decomposeNotIff(f Parser.PForm) ([][]Parser.PForm, bool) {
+	a, b, matches := notBinForm(f, Parser.PBinaryEqu)
+	return [][]Parser.PForm{{Parser.MkPNeg(Parser.MkPImp(a, b))}, {Parser.MkPNeg(Parser.MkPImp(b, a))}}, matches
+}
+
+func isFalse(f Parser.PForm) bool {
+	if c, isConst := f.(Parser.PConst); isConst {
+		return c.PConstant == Parser.PBot
+	}
+	if negated, isNot := notForm(f); isNot {
+		c, isConst := negated.(Parser.PConst)
+		return isConst && c.PConstant == Parser.PTop
+	}
+	return false
+}
+
+func areComplementary(f, g Parser.PForm) bool {
+	if negated, isNot := notForm(f); isNot && equalForms(negated, g) {
+		return true
+	}
+	negated, isNot := notForm(g)
+	return isNot && equalForms(f, negated)
+}
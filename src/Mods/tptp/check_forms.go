@@ -0,0 +1,423 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides the operations on the parsed formulas of an SC-TPTP proof
+* that are needed by its checker: alpha-equivalence, substitution and
+* congruence closure. The free variables of the sequents, e.g., the metavariables
+* of Goéland, are handled as constants.
+**/
+
+package tptp
+
+import (
+	"fmt"
+
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+/*** Normalisation ***/
+
+func normalizeSequent(sequent Parser.PSequent) Parser.PSequent {
+	return Parser.MkPSequent(normalizeForms(sequent.Left()), normalizeForms(sequent.Right()))
+}
+
+func normalizeForms(forms []Parser.PForm) []Parser.PForm {
+	res := []Parser.PForm{}
+	for _, form := range forms {
+		res = append(res, normalizeForm(form))
+	}
+	return res
+}
+
+// Splits the quantifiers over several variables, e.g., ! [X, Y] : A becomes
+// ! [X] : ! [Y] : A, as the rules instantiate one variable at a time.
+func normalizeForm(f Parser.PForm) Parser.PForm {
+	switch nf := f.(type) {
+	case Parser.PUnary:
+		return Parser.MkPNeg(normalizeForm(nf.PForm))
+	case Parser.PBin:
+		return makeBin(nf.Operator(), normalizeForm(nf.Left()), normalizeForm(nf.Right()))
+	case Parser.PQuant:
+		form := normalizeForm(nf.PForm)
+		vars := nf.Vars()
+		for i := len(vars) - 1; i >= 0; i-- {
+			form = makeQuant(nf.PQuantifier, vars[i:i+1], form)
+		}
+		return form
+	}
+	return f
+}
+
+func makeBin(op Parser.PBinOp, left, right Parser.PForm) Parser.PForm {
+	switch op {
+	case Parser.PBinaryOr:
+		return Parser.MkPOr(left, right)
+	case Parser.PBinaryAnd:
+		return Parser.MkPAnd(left, right)
+	case Parser.PBinaryImp:
+		return Parser.MkPImp(left, right)
+	}
+	return Parser.MkPEqu(left, right)
+}
+
+func makeQuant(quantifier Parser.PQuantifier, vars []Lib.Pair[string, Parser.PAtomicType], f Parser.PForm) Parser.PForm {
+	if quantifier == Parser.PQuantAll {
+		return Parser.MkPAll(vars, f)
+	}
+	return Parser.MkPEx(vars, f)
+}
+
+/*** Alpha-equivalence ***/
+
+func containsForm(forms []Parser.PForm, f Parser.PForm) bool {
+	for _, form := range forms {
+		if equalForms(form, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalForms(f, g Parser.PForm) bool {
+	return alphaEqualForms(f, g, []string{}, []string{})
+}
+
+// The bound variables of f and g are given from the outermost one.
+func alphaEqualForms(f, g Parser.PForm, fVars, gVars []string) bool {
+	switch nf := f.(type) {
+	case Parser.PConst:
+		ng, isConst := g.(Parser.PConst)
+		return isConst && nf.PConstant == ng.PConstant
+	case Parser.PPred:
+		ng, isPred := g.(Parser.PPred)
+		return isPred && nf.Symbol() == ng.Symbol() && alphaEqualTermLists(nf.Args(), ng.Args(), fVars, gVars)
+	case Parser.PUnary:
+		ng, isUnary := g.(Parser.PUnary)
+		return isUnary && nf.PUnaryOp == ng.PUnaryOp && alphaEqualForms(nf.PForm, ng.PForm, fVars, gVars)
+	case Parser.PBin:
+		ng, isBin := g.(Parser.PBin)
+		return isBin && nf.Operator() == ng.Operator() &&
+			alphaEqualForms(nf.Left(), ng.Left(), fVars, gVars) &&
+			alphaEqualForms(nf.Right(), ng.Right(), fVars, gVars)
+	case Parser.PQuant:
+		ng, isQuant := g.(Parser.PQuant)
+		if !isQuant || nf.PQuantifier != ng.PQuantifier || len(nf.Vars()) != len(ng.Vars()) {
+			return false
+		}
+		for i := range nf.Vars() {
+			fVars = append(fVars, nf.Vars()[i].Fst)
+			gVars = append(gVars, ng.Vars()[i].Fst)
+		}
+		return alphaEqualForms(nf.PForm, ng.PForm, fVars, gVars)
+	}
+	return false
+}
+
+func alphaEqualTermLists(ts, us []Parser.PTerm, tVars, uVars []string) bool {
+	if len(ts) != len(us) {
+		return false
+	}
+	for i := range ts {
+		if !alphaEqualTerms(ts[i], us[i], tVars, uVars) {
+			return false
+		}
+	}
+	return true
+}
+
+func alphaEqualTerms(t, u Parser.PTerm, tVars, uVars []string) bool {
+	i, j := boundIndex(t, tVars), boundIndex(u, uVars)
+	if i != -1 || j != -1 {
+		return i == j
+	}
+
+	if tName, isConstant := constantName(t); isConstant {
+		uName, isConstant := constantName(u)
+		return isConstant && tName == uName
+	}
+
+	nt, isFun := t.(Parser.PFun)
+	nu, isFunToo := u.(Parser.PFun)
+	return isFun && isFunToo && nt.Symbol() == nu.Symbol() && alphaEqualTermLists(nt.Args(), nu.Args(), tVars, uVars)
+}
+
+// Returns the index of the binder of a bound variable, -1 if the term is not one.
+func boundIndex(t Parser.PTerm, vars []string) int {
+	if v, isVar := t.(Parser.PVar); isVar {
+		for i := len(vars) - 1; i >= 0; i-- {
+			if vars[i] == v.Name() {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// A free variable or a function without arguments.
+func constantName(t Parser.PTerm) (string, bool) {
+	switch nt := t.(type) {
+	case Parser.PVar:
+		return nt.Name(), true
+	case Parser.PFun:
+		return nt.Symbol(), len(nt.Args()) == 0
+	}
+	return "", false
+}
+
+/*** Substitution ***/
+
+// Replaces the free occurrences of the variable x by t in f. The bound variables
+// of f that occur in t are renamed.
+func substituteForm(f Parser.PForm, x string, t Parser.PTerm) Parser.PForm {
+	switch nf := f.(type) {
+	case Parser.PPred:
+		return Parser.MkPPred(nf.Symbol(), substituteTerms(nf.Args(), x, t))
+	case Parser.PUnary:
+		return Parser.MkPNeg(substituteForm(nf.PForm, x, t))
+	case Parser.PBin:
+		return makeBin(nf.Operator(), substituteForm(nf.Left(), x, t), substituteForm(nf.Right(), x, t))
+	case Parser.PQuant:
+		vars := append([]Lib.Pair[string, Parser.PAtomicType]{}, nf.Vars()...)
+		form := nf.PForm
+		for i, v := range vars {
+			if v.Fst == x {
+				return f
+			}
+			if termContainsSymbol(t, v.Fst) {
+				fresh := freshName(v.Fst, t, form)
+				form = substituteForm(form, v.Fst, Parser.MkPVar(fresh))
+				vars[i] = Lib.MkPair(fresh, v.Snd)
+			}
+		}
+		return makeQuant(nf.PQuantifier, vars, substituteForm(form, x, t))
+	}
+	return f
+}
+
+func substituteTerms(ts []Parser.PTerm, x string, t Parser.PTerm) []Parser.PTerm {
+	res := []Parser.PTerm{}
+	for _, term := range ts {
+		res = append(res, substituteTerm(term, x, t))
+	}
+	return res
+}
+
+func substituteTerm(term Parser.PTerm, x string, t Parser.PTerm) Parser.PTerm {
+	switch nt := term.(type) {
+	case Parser.PVar:
+		if nt.Name() == x {
+			return t
+		}
+	case Parser.PFun:
+		if len(nt.Args()) > 0 {
+			return Parser.MkPFun(nt.Symbol(), substituteTerms(nt.Args(), x, t))
+		}
+	}
+	return term
+}
+
+func freshName(name string, t Parser.PTerm, f Parser.PForm) string {
+	for i := 0; ; i++ {
+		fresh := fmt.Sprintf("%s_%d", name, i)
+		if !termContainsSymbol(t, fresh) && !formContainsSymbol(f, fresh, []string{}) {
+			return fresh
+		}
+	}
+}
+
+// Whether the name is a free variable or a function symbol of the sequent.
+func sequentContainsSymbol(sequent Parser.PSequent, name string) bool {
+	for _, form := range append(append([]Parser.PForm{}, sequent.Left()...), sequent.Right()...) {
+		if formContainsSymbol(form, name, []string{}) {
+			return true
+		}
+	}
+	return false
+}
+
+func formContainsSymbol(f Parser.PForm, name string, bound []string) bool {
+	switch nf := f.(type) {
+	case Parser.PPred:
+		for _, arg := range nf.Args() {
+			if boundIndex(arg, bound) == -1 && termContainsSymbol(arg, name) {
+				return true
+			}
+		}
+	case Parser.PUnary:
+		return formContainsSymbol(nf.PForm, name, bound)
+	case Parser.PBin:
+		return formContainsSymbol(nf.Left(), name, bound) || formContainsSymbol(nf.Right(), name, bound)
+	case Parser.PQuant:
+		for _, v := range nf.Vars() {
+			bound = append(bound, v.Fst)
+		}
+		return formContainsSymbol(nf.PForm, name, bound)
+	}
+	return false
+}
+
+func termContainsSymbol(t Parser.PTerm, name string) bool {
+	switch nt := t.(type) {
+	case Parser.PVar:
+		return nt.Name() == name
+	case Parser.PFun:
+		if nt.Symbol() == name {
+			return true
+		}
+		for _, arg := range nt.Args() {
+			if termContainsSymbol(arg, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*** Congruence closure ***/
+
+// Whether the literals of the formulas are contradictory modulo their
+// equalities: a disequality between equal terms, or two complementary atoms
+// whose arguments are equal.
+func isClosedByCongruence(forms []Parser.PForm) bool {
+	closure := congruence{make(map[string]string), make(map[string]Parser.PFun)}
+	positives, negatives := []Parser.PPred{}, []Parser.PPred{}
+
+	for _, form := range forms {
+		if atom, isAtom := form.(Parser.PPred); isAtom {
+			positives = append(positives, atom)
+		} else if negated, isNot := notForm(form); isNot {
+			if atom, isAtom := negated.(Parser.PPred); isAtom {
+				negatives = append(negatives, atom)
+			}
+		}
+	}
+
+	for _, atom := range append(append([]Parser.PPred{}, positives...), negatives...) {
+		for _, arg := range atom.Args() {
+			closure.add(arg)
+		}
+	}
+	for _, atom := range positives {
+		if atom.Symbol() == Parser.PEqSymbol && len(atom.Args()) == 2 {
+			closure.union(termKey(atom.Args()[0]), termKey(atom.Args()[1]))
+		}
+	}
+	closure.close()
+
+	for _, negative := range negatives {
+		if negative.Symbol() == Parser.PEqSymbol && len(negative.Args()) == 2 &&
+			closure.find(termKey(negative.Args()[0])) == closure.find(termKey(negative.Args()[1])) {
+			return true
+		}
+		for _, positive := range positives {
+			if positive.Symbol() == negative.Symbol() && closure.sameArgs(positive.Args(), negative.Args()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type congruence struct {
+	parent map[string]string
+	// The terms with arguments, by key.
+	funs map[string]Parser.PFun
+}
+
+// The key of a term, where a free variable and a constant of the same name are
+// the same term.
+func termKey(t Parser.PTerm) string {
+	if name, isConstant := constantName(t); isConstant {
+		return name
+	}
+	fun := t.(Parser.PFun)
+	args := Lib.MkListV(fun.Args()...)
+	return fmt.Sprintf("%s(%s)", fun.Symbol(), args.ToString(termKey, ",", ""))
+}
+
+func (c congruence) add(t Parser.PTerm) {
+	key := termKey(t)
+	if _, found := c.parent[key]; found {
+		return
+	}
+	c.parent[key] = key
+	if fun, isFun := t.(Parser.PFun); isFun && len(fun.Args()) > 0 {
+		c.funs[key] = fun
+		for _, arg := range fun.Args() {
+			c.add(arg)
+		}
+	}
+}
+
+func (c congruence) find(key string) string {
+	for c.parent[key] != key {
+		key = c.parent[key]
+	}
+	return key
+}
+
+func (c congruence) union(key1, key2 string) bool {
+	root1, root2 := c.find(key1), c.find(key2)
+	if root1 == root2 {
+		return false
+	}
+	c.parent[root1] = root2
+	return true
+}
+
+func (c congruence) sameArgs(ts, us []Parser.PTerm) bool {
+	if len(ts) != len(us) {
+		return false
+	}
+	for i := range ts {
+		if c.find(termKey(ts[i])) != c.find(termKey(us[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merges the terms whose arguments are equal until a fixpoint is reached.
+func (c congruence) close() {
+	for changed := true; changed; {
+		changed = false
+		for key1, fun1 := range c.funs {
+			for key2, fun2 := range c.funs {
+				if fun1.Symbol() == fun2.Symbol() && c.find(key1) != c.find(key2) && c.sameArgs(fun1.Args(), fun2.Args()) {
+					changed = c.union(key1, key2) || changed
+				}
+			}
+		}
+	}
+}
@@ -46,7 +46,7 @@ import (
 	"github.com/GoelandProver/Goeland/Search"
 )
 
-var TptpOutputProofStruct = &Search.OutputProofStruct{ProofOutput: MakeTptpOutput, Name: "TPTP", Extension: ".p"}
+var TptpOutputProofStruct = &Search.OutputProofStruct{ProofOutput: MakeTptpOutput, Name: "TPTP", Extension: ".p", Arithmetic: true}
 
 // ----------------------------------------------------------------------------
 // Plugin initialisation and main function to call.
@@ -153,6 +153,12 @@ func makeStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, new_current_id in
 				targetPos,
 				"")
 		}
+	case gs3.ARI:
+		resultingString = fmt.Sprintf("fof("+prefix_step+"%d, plain, [%s] --> [], inference(%s, [status(thm)], [%s])).",
+			proof.GetId(),
+			mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), Glob.GetTypeProof())),
+			"arithmetic",
+			"")
 
 	// Alpha rules
 	case gs3.NNOT:
@@ -196,6 +202,10 @@ func makeStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, new_current_id in
 			resultingString, childrenHypotheses, next_child_weakened_id = weakenStep(proof, hypotheses, target, "leftWeaken")
 		}
 
+	// Cut rule
+	case gs3.CUT:
+		resultingString, childrenHypotheses = cutStep(proof, hypotheses)
+
 	case gs3.REWRITE:
 		resultingString, childrenHypotheses = rewriteStep(proof.GetRewriteWith(), hypotheses, target, proof.GetResultFormulasOfChild(0).Get(0))
 	}
@@ -212,13 +222,14 @@ func alphaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, form
 		c.SetId(new_id)
 	}
 
-	resultingString := fmt.Sprintf("fof(%s%d, plain, [%s] --> [], inference(%s, [status(thm), %d], [%s])).",
-		prefix_step,
-		proof.GetId(),
-		mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), Glob.GetTypeProof())),
-		format,
-		target,
-		Glob.IntListToString(children_id, prefix_step))
+	resultingString := naryStep(fmt.Sprintf("%s%d", prefix_step, proof.GetId()), hypotheses, target, false, children_id, func(id string, hypotheses *AST.FormList, target int, premises string) string {
+		return fmt.Sprintf("fof(%s, plain, [%s] --> [], inference(%s, [status(thm), %d], [%s])).",
+			id,
+			mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), Glob.GetTypeProof())),
+			format,
+			target,
+			premises)
+	})
 
 	newHypotheses := hypotheses.Copy()
 	newHypotheses.AppendIfNotContains(proof.GetResultFormulasOfChild(0).Slice()...)
@@ -238,17 +249,59 @@ func betaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, forma
 		resultHyps = append(resultHyps, newHypotheses)
 	}
 
-	resultingString := fmt.Sprintf("fof(%s%d, plain, [%s] --> [], inference(%s, [status(thm), %d], [%s])).",
-		prefix_step,
-		proof.GetId(),
-		mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), false)),
-		format,
-		target,
-		Glob.IntListToString(children_id, prefix_step))
+	resultingString := naryStep(fmt.Sprintf("%s%d", prefix_step, proof.GetId()), hypotheses, target, true, children_id, func(id string, hypotheses *AST.FormList, target int, premises string) string {
+		return fmt.Sprintf("fof(%s, plain, [%s] --> [], inference(%s, [status(thm), %d], [%s])).",
+			id,
+			mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), false)),
+			format,
+			target,
+			premises)
+	})
 
 	return resultingString, resultHyps
 }
 
+// The rules of SC-TPTP are binary, and read a conjunction or a disjunction of
+// n formulas as nested on the left: it is decomposed by n-1 steps, the last
+// formula first. The steps of a beta rule split the premises between them.
+func naryStep(id string, hypotheses *AST.FormList, target int, isBeta bool, children_id []int, step func(string, *AST.FormList, int, string) string) string {
+	form := hypotheses.Get(target)
+	not, isNot := form.(AST.Not)
+	if isNot {
+		form = not.GetForm()
+	}
+
+	var forms []AST.Form
+	var rest AST.Form
+	switch nf := form.(type) {
+	case AST.And:
+		forms = nf.FormList.Slice()
+		rest = AST.MakerAnd(AST.NewFormList(forms[:len(forms)-1]...))
+	case AST.Or:
+		forms = nf.FormList.Slice()
+		rest = AST.MakerOr(AST.NewFormList(forms[:len(forms)-1]...))
+	}
+	if len(forms) <= 2 {
+		return step(id, hypotheses, target, Glob.IntListToString(children_id, prefix_step))
+	}
+
+	last := forms[len(forms)-1]
+	if isNot {
+		rest, last = AST.MakerNot(rest), AST.MakerNot(last)
+	}
+	ext_id := id + "ext1"
+	newHypotheses := hypotheses.Copy()
+	if isBeta {
+		newHypotheses.AppendIfNotContains(rest)
+		lastChild := children_id[len(children_id)-1]
+		return naryStep(ext_id, newHypotheses, get(rest, newHypotheses), isBeta, children_id[:len(children_id)-1], step) + "\n\n" +
+			step(id, hypotheses, target, fmt.Sprintf("%s, %s%d", ext_id, prefix_step, lastChild))
+	}
+	newHypotheses.AppendIfNotContains(rest, last)
+	return naryStep(ext_id, newHypotheses, get(rest, newHypotheses), isBeta, children_id, step) + "\n\n" +
+		step(id, hypotheses, target, ext_id)
+}
+
 func deltaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, format string) (string, []*AST.FormList) {
 	children_id := []int{}
 	for _, c := range proof.Children() {
@@ -286,13 +339,19 @@ func gammaStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, form
 
 	get(proof.GetTargetForm(), hypotheses)
 
+	// Without a term, the instance keeps the variable.
+	term := findInConstants(proof.TermGenerated())
+	if proof.TermGenerated() == nil {
+		term = quantifiedVariable(proof.GetTargetForm())
+	}
+
 	resultingString := fmt.Sprintf("fof(%s%d, plain, [%s] --> [], inference(%s, [status(thm), %d, $fot(%s)], [%s])).",
 		prefix_step,
 		proof.GetId(),
 		mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), Glob.GetTypeProof())),
 		format,
 		target,
-		findInConstants(proof.TermGenerated()).ToMappedString(tptpMapConnectors(), Glob.GetTypeProof()),
+		term.ToMappedString(tptpMapConnectors(), Glob.GetTypeProof()),
 		Glob.IntListToString(children_id, prefix_step))
 
 	newHypotheses := hypotheses.Copy()
@@ -320,6 +379,46 @@ func weakenStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList, target int, for
 	return resultingString, []*AST.FormList{hypotheses}, child_id
 }
 
+// The formula C is cut: the first child refutes ~C, and the second one refutes
+// C, which proves ~C on the right-hand side.
+func cutStep(proof *gs3.GS3Sequent, hypotheses *AST.FormList) (string, []*AST.FormList) {
+	resultHyps := []*AST.FormList{}
+	children_id := []int{}
+
+	for i, c := range proof.Children() {
+		new_id := incrByOne(&id_proof_step, &mutex_proof_step)
+		children_id = append(children_id, new_id)
+		c.SetId(new_id)
+		newHypotheses := hypotheses.Copy()
+		newHypotheses.AppendIfNotContains(proof.GetResultFormulasOfChild(i).Slice()...)
+		resultHyps = append(resultHyps, newHypotheses)
+	}
+
+	// from C |- to |- ~C
+	notCut := AST.MakerNot(proof.GetCutFormula())
+	s1_id := fmt.Sprintf("%s%dext1", prefix_step, proof.GetId())
+	resultingString := fmt.Sprintf("fof(%s, plain, [%s] --> [%s], inference(%s, [status(thm), %d], [%s%d])).\n\n",
+		s1_id,
+		mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), Glob.GetTypeProof())),
+		mapDefault(notCut.ToMappedString(tptpMapConnectors(), Glob.GetTypeProof())),
+		"rightNot",
+		0,
+		prefix_step,
+		children_id[1])
+
+	resultingString += fmt.Sprintf("fof(%s%d, plain, [%s] --> [], inference(%s, [status(thm), %d], [%s, %s%d])).",
+		prefix_step,
+		proof.GetId(),
+		mapDefault(AST.ListToMappedString(hypotheses.Slice(), ", ", "", tptpMapConnectors(), Glob.GetTypeProof())),
+		"cut",
+		0,
+		s1_id,
+		prefix_step,
+		children_id[0])
+
+	return resultingString, resultHyps
+}
+
 func rewriteStep(rewriteRule AST.Form, hypotheses *AST.FormList, target int, replacementForm AST.Form) (string, []*AST.FormList) {
 	// resultingString := fmt.Sprintf("rewrite %s in %s.", introName(get(rewriteRule, hypotheses)), introName(target))
 	// hypotheses[target] = replacementForm
@@ -673,6 +772,20 @@ func createNewConstant(term AST.Term) AST.Term {
 	return new_term
 }
 
+// The first variable of a universal formula, or of a negated existential one.
+func quantifiedVariable(form AST.Form) AST.Term {
+	if not, isNot := form.(AST.Not); isNot {
+		form = not.GetForm()
+	}
+	switch nf := form.(type) {
+	case AST.All:
+		return nf.GetVarList()[0]
+	case AST.Ex:
+		return nf.GetVarList()[0]
+	}
+	return dummyTerm
+}
+
 func findInConstants(term AST.Term) AST.Term {
 	if term == nil {
 		return dummyTerm
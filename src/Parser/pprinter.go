@@ -102,6 +102,27 @@ func (q PQuant) ToString() string {
 	return fmt.Sprintf("%s{%s, %s}", prefix, vars.ToString(pairStr, ", ", ""), q.PForm.ToString())
 }
 
+func (a PApp) ToString() string {
+	return fmt.Sprintf("App{%s, %s}", a.fun.ToString(), a.arg.ToString())
+}
+
+func (q PHOQuant) ToString() string {
+	prefix := ""
+	switch q.PHOBinder {
+	case PHOLambda:
+		prefix = "Lambda"
+	case PHOAll:
+		prefix = "All"
+	case PHOEx:
+		prefix = "Ex"
+	}
+	vars := Lib.MkListV(q.vars...)
+	pairStr := func(p Lib.Pair[string, PType]) string {
+		return "(" + p.Fst + ": " + p.Snd.ToString() + ")"
+	}
+	return fmt.Sprintf("%s{%s, %s}", prefix, vars.ToString(pairStr, ", ", ""), q.PForm.ToString())
+}
+
 func (v PTypeVar) ToString() string {
 	return fmt.Sprintf("%s", v.name)
 }
@@ -152,6 +173,8 @@ func (stmt PStatement) ToString() string {
 		role = "conjecture"
 	case NegatedConjecture:
 		role = "negated_conjecture"
+	case Question:
+		role = "question"
 	case Type:
 		role = "type"
 	case Unknown:
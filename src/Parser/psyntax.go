@@ -35,6 +35,8 @@
 package Parser
 
 import (
+	"slices"
+
 	"github.com/GoelandProver/Goeland/Lib"
 )
 
@@ -159,6 +161,14 @@ func (v PVar) Name() string { return v.name }
 func (PFun) isPTerm() {}
 func (PVar) isPTerm() {}
 
+func MkPVar(name string) PTerm {
+	return PVar{name}
+}
+
+func MkPFun(symbol string, args []PTerm) PTerm {
+	return PFun{symbol, args, Lib.MkNone[PTypeFun]()}
+}
+
 func MkFunConst(symbol string) PTerm {
 	return PFun{symbol, []PTerm{}, Lib.MkNone[PTypeFun]()}
 }
@@ -286,6 +296,10 @@ func MkPNotAnd(left, right PForm) PForm {
 	return MkPNeg(MkPAnd(left, right))
 }
 
+func MkPPred(symbol string, args []PTerm) PForm {
+	return PPred{symbol, args}
+}
+
 func MkPTop() PForm {
 	return PConst{PTop}
 }
@@ -302,6 +316,103 @@ func MkPEx(vars []Lib.Pair[string, PAtomicType], f PForm) PForm {
 	return PQuant{PQuantEx, vars, f}
 }
 
+// Returns the free variables of f, in order of first occurrence.
+func FreeVariables(f PForm) []string {
+	return freeVariables(f, []string{}, []string{})
+}
+
+func freeVariables(f PForm, bound, acc []string) []string {
+	switch pform := f.(type) {
+	case PPred:
+		for _, arg := range pform.Args() {
+			acc = freeVariablesOfTerm(arg, bound, acc)
+		}
+	case PUnary:
+		acc = freeVariables(pform.PForm, bound, acc)
+	case PBin:
+		acc = freeVariables(pform.Left(), bound, acc)
+		acc = freeVariables(pform.Right(), bound, acc)
+	case PQuant:
+		newBound := append([]string{}, bound...)
+		for _, v := range pform.Vars() {
+			newBound = append(newBound, v.Fst)
+		}
+		acc = freeVariables(pform.PForm, newBound, acc)
+	}
+	return acc
+}
+
+func freeVariablesOfTerm(t PTerm, bound, acc []string) []string {
+	switch pterm := t.(type) {
+	case PVar:
+		if !slices.Contains(bound, pterm.Name()) && !slices.Contains(acc, pterm.Name()) {
+			acc = append(acc, pterm.Name())
+		}
+	case PFun:
+		for _, arg := range pterm.Args() {
+			acc = freeVariablesOfTerm(arg, bound, acc)
+		}
+	}
+	return acc
+}
+
+// TPTP THF (TH0) formulas at parsing time. Formulas are terms of type $o, so THF
+// formulas extend the FOL ones with variables, applications and binders whose
+// variables may have any type:
+//   e, e'  ::=  F | x | e @ e' | ^[x1: A1, ..., xn: An] : e |
+//               ![x1: A1, ..., xn: An] : e | ?[x1: A1, ..., xn: An] : e
+// Equality e = e' is represented as the application (=) @ e @ e'.
+
+type PHOBinder int
+
+const (
+	PHOLambda PHOBinder = iota
+	PHOAll
+	PHOEx
+)
+
+type PApp struct {
+	fun PForm
+	arg PForm
+}
+
+func (a PApp) Fun() PForm { return a.fun }
+func (a PApp) Arg() PForm { return a.arg }
+
+type PHOQuant struct {
+	PHOBinder
+	vars []Lib.Pair[string, PType]
+	PForm
+}
+
+func (q PHOQuant) Vars() []Lib.Pair[string, PType] {
+	return q.vars
+}
+
+func (PVar) isPForm()     {}
+func (PApp) isPForm()     {}
+func (PHOQuant) isPForm() {}
+
+func MkPApp(fun, arg PForm) PForm {
+	return PApp{fun, arg}
+}
+
+func MkPHOEq(left, right PForm) PForm {
+	return MkPApp(MkPApp(PPred{PEqSymbol, []PTerm{}}, left), right)
+}
+
+func MkPLambda(vars []Lib.Pair[string, PType], f PForm) PForm {
+	return PHOQuant{PHOLambda, vars, f}
+}
+
+func MkPHOAll(vars []Lib.Pair[string, PType], f PForm) PForm {
+	return PHOQuant{PHOAll, vars, f}
+}
+
+func MkPHOEx(vars []Lib.Pair[string, PType], f PForm) PForm {
+	return PHOQuant{PHOEx, vars, f}
+}
+
 type PFormulaRole int
 
 const (
@@ -311,19 +422,37 @@ const (
 	Type
 	Unknown
 	Include
+	Question
+)
+
+// The kind of annotated formula a statement comes from. Includes are tagged as fof.
+type PLanguage int
+
+const (
+	PFOF PLanguage = iota
+	PTFF
+	PTPI
+	PCNF
+	PTHF
 )
 
 type PStatement struct {
-	name string
-	role PFormulaRole
-	form Lib.Option[PForm]
-	ty   Lib.Option[Lib.Pair[string, PType]]
+	name     string
+	role     PFormulaRole
+	form     Lib.Option[PForm]
+	ty       Lib.Option[Lib.Pair[string, PType]]
+	language PLanguage
 }
 
 func (s PStatement) Name() string                                    { return s.name }
 func (s PStatement) Role() PFormulaRole                              { return s.role }
 func (s PStatement) Form() Lib.Option[PForm]                         { return s.form }
 func (s PStatement) TypedConst() Lib.Option[Lib.Pair[string, PType]] { return s.ty }
+func (s PStatement) Language() PLanguage                             { return s.language }
+
+// A statement coming from a cnf record is a clause: its variables are free and
+// implicitly universally quantified.
+func (s PStatement) IsClause() bool { return s.language == PCNF }
 
 /*
 A function to get a FormulaRole from a String
@@ -336,6 +465,7 @@ A function to get a FormulaRole from a String
   - "theorem"s are more important than "lemma"s from the user perspective.
   - "conjecture"s are to be proven from the "axiom"(-like) formulae. A problem is solved only when all "conjecture"s are proven.
   - "negated_conjecture"s are formed from negation of a "conjecture" (usually in a FOF to CNF conversion).
+  - "question"s are existentially quantified "conjecture"s whose proof must give the values of the variables (the answers).
   - "plain"s have no specified user semantics.
   - "fi_domain", "fi_functors", and "fi_predicates" are used to record the domain, interpretation of functors, and interpretation of predicates, for a finite interpretation.
   - "type" defines the type globally for one symbol; treat as $true.
@@ -349,6 +479,8 @@ func PFormulaRoleFromStr(role string) PFormulaRole {
 		return Conjecture
 	case "negated_conjecture":
 		return NegatedConjecture
+	case "question":
+		return Question
 	case "type":
 		return Type
 	default:
@@ -0,0 +1,436 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides a parser for SC-TPTP proofs, i.e., TPTP files whose plain
+* records are sequent-calculus steps:
+*	fof(name, plain, [A1, ..., An] --> [B1, ..., Bm], inference(rule, [params], [premises])).
+* The records are split with the TPTP lexer, and their formulas are parsed with
+* the TPTP grammar.
+**/
+
+package Parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+// A sequent A1, ..., An --> B1, ..., Bm.
+type PSequent struct {
+	left  []PForm
+	right []PForm
+}
+
+func (s PSequent) Left() []PForm  { return s.left }
+func (s PSequent) Right() []PForm { return s.right }
+
+func MkPSequent(left, right []PForm) PSequent {
+	return PSequent{left, right}
+}
+
+// The parameters of an inference are general terms: words and numbers, e.g.,
+// 2 or 'Sko_0', functions, e.g., status(thm), lists and formula data, e.g.,
+// $fot(a) or $fof(p(a)).
+type PGeneralTerm interface {
+	isPGeneralTerm()
+	ToString() string
+}
+
+type PGeneralWord struct {
+	word string
+}
+
+func (w PGeneralWord) Word() string { return w.word }
+
+type PGeneralFun struct {
+	symbol    string
+	arguments []PGeneralTerm
+}
+
+func (f PGeneralFun) Symbol() string       { return f.symbol }
+func (f PGeneralFun) Args() []PGeneralTerm { return f.arguments }
+
+type PGeneralList struct {
+	elements []PGeneralTerm
+}
+
+func (l PGeneralList) Elements() []PGeneralTerm { return l.elements }
+
+type PGeneralTermData struct {
+	term PTerm
+}
+
+func (d PGeneralTermData) Term() PTerm { return d.term }
+
+type PGeneralFormData struct {
+	form PForm
+}
+
+func (d PGeneralFormData) Form() PForm { return d.form }
+
+func (PGeneralWord) isPGeneralTerm()     {}
+func (PGeneralFun) isPGeneralTerm()      {}
+func (PGeneralList) isPGeneralTerm()     {}
+func (PGeneralTermData) isPGeneralTerm() {}
+func (PGeneralFormData) isPGeneralTerm() {}
+
+func (w PGeneralWord) ToString() string { return w.word }
+
+func (f PGeneralFun) ToString() string {
+	args := Lib.MkListV(f.arguments...)
+	return fmt.Sprintf("%s(%s)", f.symbol, args.ToString(PGeneralTerm.ToString, ", ", ""))
+}
+
+func (l PGeneralList) ToString() string {
+	elements := Lib.MkListV(l.elements...)
+	return fmt.Sprintf("[%s]", elements.ToString(PGeneralTerm.ToString, ", ", ""))
+}
+
+func (d PGeneralTermData) ToString() string { return fmt.Sprintf("$fot(%s)", d.term.ToString()) }
+func (d PGeneralFormData) ToString() string { return fmt.Sprintf("$fof(%s)", d.form.ToString()) }
+
+// The inference(rule, [params], [premises]) annotation of a step.
+type PInference struct {
+	rule     string
+	params   []PGeneralTerm
+	premises []string
+}
+
+func (i PInference) Rule() string           { return i.rule }
+func (i PInference) Params() []PGeneralTerm { return i.params }
+func (i PInference) Premises() []string     { return i.premises }
+
+// A record of an SC-TPTP file. The records that are not steps, e.g., the axioms
+// and the conjecture, have a formula instead of a sequent, and may have no
+// inference.
+type PSCTPTPStatement struct {
+	name      string
+	role      string
+	line      int
+	form      Lib.Option[PForm]
+	sequent   Lib.Option[PSequent]
+	inference Lib.Option[PInference]
+}
+
+func (s PSCTPTPStatement) Name() string                      { return s.name }
+func (s PSCTPTPStatement) Role() string                      { return s.role }
+func (s PSCTPTPStatement) Line() int                         { return s.line }
+func (s PSCTPTPStatement) Form() Lib.Option[PForm]           { return s.form }
+func (s PSCTPTPStatement) Sequent() Lib.Option[PSequent]     { return s.sequent }
+func (s PSCTPTPStatement) Inference() Lib.Option[PInference] { return s.inference }
+
+// Parses the SC-TPTP proof in the given file, or on the standard input if the
+// name is StdinName.
+func ParseSCTPTPFile(filename string) []PSCTPTPStatement {
+	var data []byte
+	var err error
+
+	if filename == StdinName {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+
+	if err != nil {
+		Glob.Fatal(parse_label, err.Error())
+	}
+
+	return ParseSCTPTPString(string(data))
+}
+
+// Parses an SC-TPTP proof given as a string.
+func ParseSCTPTPString(input string) []PSCTPTPStatement {
+	oldLine := yylineno
+	yylineno = 1
+	defer func() { yylineno = oldLine }()
+
+	// The lexer needs a character after the last token.
+	parser := sctptpParser{lexer: &TPTPLex{s: input + "\n"}, forms: make(map[string]PForm)}
+	parser.next()
+
+	statements := []PSCTPTPStatement{}
+	for parser.token != FAILURE_TOKEN {
+		statements = append(statements, parser.statement())
+	}
+
+	if parser.lexer.pos < len(parser.lexer.s) {
+		parser.lexer.Error("unexpected character")
+	}
+
+	return statements
+}
+
+type sctptpParser struct {
+	lexer *TPTPLex
+	token int
+	val   TPTPSymType
+	// Position in the input of the end of the previous token.
+	start    int
+	language string
+	// Formulas already parsed, as a sequent is often repeated in the next steps.
+	forms map[string]PForm
+}
+
+func (p *sctptpParser) next() {
+	p.start = p.lexer.pos
+	p.val.str = ""
+	if p.lexer.pos >= len(p.lexer.s) {
+		p.token = FAILURE_TOKEN
+	} else {
+		p.token = p.lexer.Lex(&p.val)
+	}
+}
+
+func (p *sctptpParser) expect(token int, what string) {
+	if p.token != token {
+		p.lexer.Error(fmt.Sprintf("%s expected", what))
+	}
+	p.next()
+}
+
+// <annotated_formula> with a formula or a sequent, and optional annotations.
+func (p *sctptpParser) statement() PSCTPTPStatement {
+	stmt := PSCTPTPStatement{
+		line:      yylineno,
+		form:      Lib.MkNone[PForm](),
+		sequent:   Lib.MkNone[PSequent](),
+		inference: Lib.MkNone[PInference](),
+	}
+
+	switch p.token {
+	case FOF:
+		p.language = "fof"
+	case TFF:
+		p.language = "tff"
+	default:
+		p.lexer.Error("fof or tff record expected")
+	}
+	p.next()
+	p.expect(LEFT_PAREN, "(")
+
+	stmt.name = p.name()
+	p.expect(COMMA, ",")
+	stmt.role = p.val.str
+	p.expect(LOWER_WORD, "role")
+	p.expect(COMMA, ",")
+
+	if p.token == LEFT_BRACKET {
+		stmt.sequent = Lib.MkSome(p.sequent())
+	} else {
+		stmt.form = Lib.MkSome(p.form())
+	}
+
+	if p.token == COMMA {
+		p.next()
+		if inference, isInference := p.generalTerm().(PGeneralFun); isInference && inference.symbol == "inference" {
+			stmt.inference = Lib.MkSome(makeInference(inference, p.lexer))
+		}
+		// Useful info
+		if p.token == COMMA {
+			p.next()
+			p.generalTerm()
+		}
+	}
+
+	p.expect(RIGHT_PAREN, ")")
+	p.expect(DOT, ".")
+	return stmt
+}
+
+func (p *sctptpParser) name() string {
+	name := p.val.str
+	switch p.token {
+	case LOWER_WORD, UPPER_WORD, SINGLE_QUOTED, INTEGER:
+		p.next()
+	default:
+		p.lexer.Error("name expected")
+	}
+	return name
+}
+
+// [A1, ..., An] --> [B1, ..., Bm]
+func (p *sctptpParser) sequent() PSequent {
+	left := p.formList()
+	p.expect(DASH, "-->")
+	p.expect(DASH, "-->")
+	p.expect(ARROW, "-->")
+	right := p.formList()
+	return PSequent{left, right}
+}
+
+func (p *sctptpParser) formList() []PForm {
+	forms := []PForm{}
+	p.expect(LEFT_BRACKET, "[")
+	for p.token != RIGHT_BRACKET {
+		forms = append(forms, p.form())
+		if p.token != RIGHT_BRACKET {
+			p.expect(COMMA, ",")
+		}
+	}
+	p.next()
+	return forms
+}
+
+// A formula spans until the next comma, closing bracket or closing parenthesis
+// that is not nested in it. Its text is given to the TPTP grammar.
+func (p *sctptpParser) form() PForm {
+	text, line := p.span()
+	if form, found := p.forms[text]; found {
+		return form
+	}
+	form := parseSCTPTPText(fmt.Sprintf("%s(f, plain, %s).\n", p.language, text), line).form.(Lib.Some[PForm]).Val
+	p.forms[text] = form
+	return form
+}
+
+func (p *sctptpParser) term() PTerm {
+	text, line := p.span()
+	form := parseSCTPTPText(fmt.Sprintf("%s(f, plain, p(%s)).\n", p.language, text), line).form.(Lib.Some[PForm]).Val
+	return form.(PPred).arguments[0]
+}
+
+func (p *sctptpParser) span() (string, int) {
+	begin, line, depth := p.start, yylineno, 0
+	for depth > 0 || (p.token != COMMA && p.token != RIGHT_BRACKET && p.token != RIGHT_PAREN) {
+		switch p.token {
+		case FAILURE_TOKEN:
+			p.lexer.Error("unexpected end of formula")
+		case LEFT_PAREN, LEFT_BRACKET:
+			depth += 1
+		case RIGHT_PAREN, RIGHT_BRACKET:
+			depth -= 1
+		}
+		p.next()
+	}
+
+	text := strings.TrimSpace(p.lexer.s[begin:p.start])
+	if text == "" {
+		p.lexer.Error("formula expected")
+	}
+	return text, line
+}
+
+// Parses a single record with the TPTP grammar, from the given line of the
+// SC-TPTP file.
+func parseSCTPTPText(record string, line int) PStatement {
+	oldLine, oldCounter, oldEquality := yylineno, quantifiersCounter, containsEquality
+	yylineno = line
+	statements, _, _ := ParseTPTPString(record)
+	yylineno, quantifiersCounter, containsEquality = oldLine, oldCounter, oldEquality
+
+	if len(statements) != 1 {
+		Glob.Fatal(parse_label, fmt.Sprintf("Syntax error, line %d: formula expected", line))
+	}
+	if _, isForm := statements[0].form.(Lib.Some[PForm]); !isForm {
+		Glob.Fatal(parse_label, fmt.Sprintf("Syntax error, line %d: formula expected", line))
+	}
+	return statements[0]
+}
+
+func (p *sctptpParser) generalTerm() PGeneralTerm {
+	switch p.token {
+	case LEFT_BRACKET:
+		p.next()
+		list := PGeneralList{p.generalTerms(RIGHT_BRACKET)}
+		p.next()
+		return list
+	case DOLLAR_FOT, DOLLAR_FOF, DOLLAR_TFF:
+		token := p.token
+		p.next()
+		p.expect(LEFT_PAREN, "(")
+		var data PGeneralTerm
+		if token == DOLLAR_FOT {
+			data = PGeneralTermData{p.term()}
+		} else {
+			data = PGeneralFormData{p.form()}
+		}
+		p.expect(RIGHT_PAREN, ")")
+		return data
+	case LOWER_WORD, UPPER_WORD, SINGLE_QUOTED, DOLLAR_WORD, DISTINCT_OBJECT, INTEGER, RATIONAL, REAL:
+		word := p.val.str
+		p.next()
+		if p.token == LEFT_PAREN {
+			p.next()
+			fun := PGeneralFun{word, p.generalTerms(RIGHT_PAREN)}
+			p.next()
+			return fun
+		}
+		return PGeneralWord{word}
+	}
+
+	p.lexer.Error("general term expected")
+	return nil
+}
+
+// Parses general terms separated by commas until the closing token, which is
+// not consumed.
+func (p *sctptpParser) generalTerms(closing int) []PGeneralTerm {
+	terms := []PGeneralTerm{}
+	for p.token != closing {
+		terms = append(terms, p.generalTerm())
+		if p.token != closing {
+			p.expect(COMMA, ",")
+		}
+	}
+	return terms
+}
+
+// inference(rule, [params], [premises])
+func makeInference(inference PGeneralFun, lexer *TPTPLex) PInference {
+	if len(inference.arguments) != 3 {
+		lexer.Error("inference(rule, [params], [premises]) expected")
+	}
+
+	rule, isRule := inference.arguments[0].(PGeneralWord)
+	params, isParams := inference.arguments[1].(PGeneralList)
+	premisesList, isPremises := inference.arguments[2].(PGeneralList)
+	if !isRule || !isParams || !isPremises {
+		lexer.Error("inference(rule, [params], [premises]) expected")
+	}
+
+	premises := []string{}
+	for _, premise := range premisesList.elements {
+		name, isName := premise.(PGeneralWord)
+		if !isName {
+			lexer.Error(fmt.Sprintf("premise name expected, got %s", premise.ToString()))
+		}
+		premises = append(premises, name.word)
+	}
+
+	return PInference{rule.word, params.elements, premises}
+}
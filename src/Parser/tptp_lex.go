@@ -34,6 +34,7 @@ package Parser
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"unicode"
 
@@ -207,7 +208,7 @@ func (lexer TPTPLex) isPunctuation() (int, bool) {
 	return manageReturn(lexMap, lexer.c)
 }
 
-// %----Operators:   ! ? ~ & | <=> => <= <~> ~| ~& * +
+// %----Operators:   ! ? ~ & | <=> => <= <~> ~| ~& * + @ ^
 func (lexer *TPTPLex) isOperator() (int, bool) {
 	lexMap := map[string]int{
 		"!":   FORALL,
@@ -225,6 +226,8 @@ func (lexer *TPTPLex) isOperator() (int, bool) {
 		"*":   STAR,
 		"+":   PLUS,
 		"<<":  SUBTYPE,
+		"@":   APPLY,
+		"^":   LAMBDA,
 	}
 
 	word := string(lexer.c)
@@ -247,6 +250,8 @@ func (lexer *TPTPLex) isOperator() (int, bool) {
 		} else {
 			return FAILURE_TOKEN, false
 		}
+	case '^':
+		quantifiersCounter += 1
 	case '<':
 		if lexer.checkAdvance('=') { // <=> or <=
 			word += "="
@@ -832,24 +837,52 @@ func (lexer *TPTPLex) Lex(yylval *TPTPSymType) int {
 // Main parsing function
 // ----------------------------------------------------------------------------
 
+// The name standing for the standard input in place of a file name.
+const StdinName = "-"
+
+// Parses the TPTP problem in the given file, or on the standard input if the
+// name is StdinName.
 func ParseTPTPFile(filename string) ([]PStatement, int, bool) {
-	defer func() {
-		if r := recover(); r != nil {
-			Glob.Fatal(parse_label, fmt.Sprintf("Lexing error: %v", r))
-		}
-	}()
+	var data []byte
+	var err error
 
-	data, err := os.ReadFile(filename)
+	if filename == StdinName {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
 
 	if err != nil {
 		Glob.Fatal(parse_label, err.Error())
 	}
 
-	TPTPParse(&TPTPLex{s: string(data)})
+	return ParseTPTPString(string(data))
+}
+
+// Parses a TPTP problem given as a string.
+func ParseTPTPString(input string) ([]PStatement, int, bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, isFatal := r.(Glob.FatalError); isFatal {
+				panic(err)
+			}
+			Glob.Fatal(parse_label, fmt.Sprintf("Lexing error: %v", r))
+		}
+	}()
+
+	TPTPParse(&TPTPLex{s: input})
 
 	return statement, quantifiersCounter, containsEquality
 }
 
+// Resets the counters accumulated over the parsings of a problem and of its
+// includes, before parsing another problem.
+func ResetParsingState() {
+	quantifiersCounter = 0
+	containsEquality = false
+	yylineno = 1
+}
+
 // ----------------------------------------------------------------------------
 // Utility functions
 // ----------------------------------------------------------------------------
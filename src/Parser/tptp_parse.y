@@ -68,6 +68,7 @@ type TypedVar = Lib.Pair[string, PAtomicType]
     ttl []PTerm
     form PForm
     tps Lib.Pair[string, PType]
+    tpl []Lib.Pair[string, PType]
     tff TFFFormula
     fr PFormulaRole
     stm PStatement
@@ -82,7 +83,7 @@ type TypedVar = Lib.Pair[string, PAtomicType]
 // Punctuation
 %token LEFT_PAREN RIGHT_PAREN COMMA DOT LEFT_BRACKET RIGHT_BRACKET COLON
 // Operators
-%token FORALL EXISTS NOT VLINE AND EQUIV IMPLY LEFT_IMPLY XOR NOTVLINE NOTAND STAR PLUS FORALL_TYPE SUBTYPE
+%token FORALL EXISTS NOT VLINE AND EQUIV IMPLY LEFT_IMPLY XOR NOTVLINE NOTAND STAR PLUS FORALL_TYPE SUBTYPE APPLY LAMBDA
 // Predicates
 %token EQUAL NOT_EQUAL TRUE FALSE
 // Quoted words
@@ -97,12 +98,18 @@ type TypedVar = Lib.Pair[string, PAtomicType]
 // Semantic type of non-terminal type
 %type <lstm> tptp_file tptp_input_list
 %type <stm> tff_annotated
-%type <stm>  tptp_input annotated_formula include fof_annotated tpi_annotated
+%type <stm>  tptp_input annotated_formula include fof_annotated tpi_annotated cnf_annotated thf_annotated
 %type <str>  name atomic_word atomic_defined_word file_name type_constant type_functor defined_type variable constant defined_constant functor defined_functor untyped_atom
 %type <strty> number
 %type <fr>   formula_role 
 %type <form>  tpi_formula
 %type <form>  tff_logic_formula tff_and_formula tff_binary_assoc tff_binary_formula tff_binary_nonassoc tff_or_formula tff_unitary_formula tff_unit_formula tff_preunit_formula tff_quantified_formula tff_unary_formula tff_prefix_unary tff_infix_unary tff_atomic_formula tff_plain_atomic_formula tff_defined_atomic tff_defined_plain
+%type <form> cnf_formula cnf_disjunction cnf_literal
+%type <form> thf_logic_formula thf_binary_formula thf_binary_nonassoc thf_binary_assoc thf_or_formula thf_and_formula thf_apply_formula thf_unit_formula thf_preunit_formula thf_unitary_formula thf_quantified_formula thf_unary_formula thf_prefix_unary thf_infix_unary thf_defined_infix thf_unitary_term thf_atomic_formula
+%type <tps> thf_variable thf_atom_typing
+%type <tpl> thf_variable_list
+%type <typ> thf_top_level_type thf_mapping_type thf_unitary_type
+%type <tff> thf_formula
 %type <form> fof_formula fof_infix_unary fof_logic_formula fof_binary_formula fof_binary_nonassoc fof_binary_assoc fof_or_formula fof_and_formula fof_unary_formula fof_unit_formula fof_unitary_formula fof_quantified_formula fof_atomic_formula fof_plain_atomic_formula fof_defined_atomic_formula fof_defined_plain_formula fof_defined_infix_formula tff_defined_infix
 %type <tfv> tff_variable tff_typed_variable
 %type <tfl> tff_variable_list fof_variable_list
@@ -136,18 +143,35 @@ tptp_input: annotated_formula   { $$ = $1 }
 annotated_formula: fof_annotated    { $$ = $1 }
   | tpi_annotated                   { $$ = $1 }
   | tff_annotated                   { $$ = $1 }
+  | cnf_annotated                   { $$ = $1 }
+  | thf_annotated                   { $$ = $1 }
   ;
 
 tff_annotated: TFF LEFT_PAREN name COMMA formula_role COMMA tff_formula annotations RIGHT_PAREN DOT
-  { $$ = PStatement{$3, $5, $7.form, $7.typ} }
+  { $$ = PStatement{$3, $5, $7.form, $7.typ, PTFF} }
   ;
 
 fof_annotated: FOF LEFT_PAREN name COMMA formula_role COMMA fof_formula annotations RIGHT_PAREN DOT
-  { $$ = PStatement{$3, $5, Lib.MkSome($7), Lib.MkNone[Lib.Pair[string, PType]]()} }
+  { $$ = PStatement{$3, $5, Lib.MkSome($7), Lib.MkNone[Lib.Pair[string, PType]](), PFOF} }
   ;
 
 tpi_annotated: TPI LEFT_PAREN name COMMA formula_role COMMA tpi_formula annotations RIGHT_PAREN DOT
-  { $$ = PStatement{$3, $5, Lib.MkSome($7), Lib.MkNone[Lib.Pair[string, PType]]()} }
+  { $$ = PStatement{$3, $5, Lib.MkSome($7), Lib.MkNone[Lib.Pair[string, PType]](), PTPI} }
+  ;
+
+cnf_annotated: CNF LEFT_PAREN name COMMA formula_role COMMA cnf_formula annotations RIGHT_PAREN DOT
+  {
+    // The variables of a clause are implicitly quantified, hence a clause with
+    // variables counts for one quantifier in the bound of the search.
+    if len(FreeVariables($7)) > 0 {
+        quantifiersCounter += 1
+    }
+    $$ = PStatement{$3, $5, Lib.MkSome($7), Lib.MkNone[Lib.Pair[string, PType]](), PCNF}
+  }
+  ;
+
+thf_annotated: THF LEFT_PAREN name COMMA formula_role COMMA thf_formula annotations RIGHT_PAREN DOT
+  { $$ = PStatement{$3, $5, $7.form, $7.typ, PTHF} }
   ;
 
 tpi_formula: fof_formula { $$ = $1 }
@@ -161,6 +185,126 @@ formula_role: LOWER_WORD            { $$ = PFormulaRoleFromStr($1) }
   | LOWER_WORD DASH general_term    { $$ = PFormulaRoleFromStr($1) }
   ;
 
+// ----------------------------------------------------------------------------
+// THF
+// ----------------------------------------------------------------------------
+
+// Only the TH0 fragment is supported. Higher-order formulas are encoded into
+// first-order ones when elaborating the statement (see Engine.ToInternalSyntax).
+
+thf_formula: thf_logic_formula
+  { $$ = TFFFormula{Lib.MkSome($1), Lib.MkNone[Lib.Pair[string, PType]]()} }
+  | thf_atom_typing
+  { $$ = TFFFormula{Lib.MkNone[PForm](), Lib.MkSome($1)} }
+  ;
+
+thf_logic_formula: thf_unitary_formula  { $$ = $1 }
+  | thf_unary_formula                   { $$ = $1 }
+  | thf_binary_formula                  { $$ = $1 }
+  | thf_defined_infix                   { $$ = $1 }
+  ;
+
+thf_binary_formula: thf_binary_nonassoc { $$ = $1 }
+  | thf_binary_assoc                    { $$ = $1 }
+  ;
+
+thf_binary_nonassoc: thf_unit_formula EQUIV thf_unit_formula    { $$ = MkPEqu($1, $3) }
+  | thf_unit_formula IMPLY thf_unit_formula                     { $$ = MkPImp($1, $3) }
+  | thf_unit_formula LEFT_IMPLY thf_unit_formula                { $$ = MkPRevImp($1, $3) }
+  | thf_unit_formula XOR thf_unit_formula                       { $$ = MkPXor($1, $3) }
+  | thf_unit_formula NOTVLINE thf_unit_formula                  { $$ = MkPNotOr($1, $3) }
+  | thf_unit_formula NOTAND thf_unit_formula                    { $$ = MkPNotAnd($1, $3) }
+  ;
+
+thf_binary_assoc: thf_or_formula { $$ = $1 }
+  | thf_and_formula              { $$ = $1 }
+  | thf_apply_formula            { $$ = $1 }
+  ;
+
+thf_or_formula: thf_unit_formula VLINE thf_unit_formula { $$ = MkPOr($1, $3) }
+  | thf_or_formula VLINE thf_unit_formula               { $$ = MkPOr($1, $3) }
+  ;
+
+thf_and_formula: thf_unit_formula AND thf_unit_formula { $$ = MkPAnd($1, $3) }
+  | thf_and_formula AND thf_unit_formula                { $$ = MkPAnd($1, $3) }
+  ;
+
+thf_apply_formula: thf_unit_formula APPLY thf_unit_formula { $$ = MkPApp($1, $3) }
+  | thf_apply_formula APPLY thf_unit_formula                { $$ = MkPApp($1, $3) }
+  ;
+
+thf_unit_formula: thf_unitary_formula { $$ = $1 }
+  | thf_unary_formula                 { $$ = $1 }
+  | thf_defined_infix                 { $$ = $1 }
+  ;
+
+thf_preunit_formula: thf_unitary_formula { $$ = $1 }
+  | thf_prefix_unary                     { $$ = $1 }
+  ;
+
+thf_unitary_formula: thf_quantified_formula  { $$ = $1 }
+  | thf_atomic_formula                       { $$ = $1 }
+  | variable                                 { $$ = PVar{$1} }
+  | LEFT_PAREN thf_logic_formula RIGHT_PAREN { $$ = $2 }
+  ;
+
+thf_quantified_formula: FORALL LEFT_BRACKET thf_variable_list RIGHT_BRACKET COLON thf_unit_formula
+  { $$ = MkPHOAll($3, $6) }
+  | EXISTS LEFT_BRACKET thf_variable_list RIGHT_BRACKET COLON thf_unit_formula
+  { $$ = MkPHOEx($3, $6) }
+  | LAMBDA LEFT_BRACKET thf_variable_list RIGHT_BRACKET COLON thf_unit_formula
+  { $$ = MkPLambda($3, $6) }
+  ;
+
+thf_variable_list: thf_variable { $$ = []Lib.Pair[string, PType]{$1} }
+  | thf_variable COMMA thf_variable_list
+  { $$ = append([]Lib.Pair[string, PType]{$1}, $3...) }
+  ;
+
+thf_variable: variable COLON thf_top_level_type { $$ = Lib.MkPair($1, $3) }
+  | variable                                    { $$ = Lib.MkPair($1, MkDefPAtomicType().(PType)) }
+  ;
+
+thf_unary_formula: thf_prefix_unary { $$ = $1 }
+  | thf_infix_unary                 { $$ = $1 }
+  ;
+
+thf_prefix_unary: NOT thf_preunit_formula { $$ = MkPNeg($2) }
+  ;
+
+thf_infix_unary: thf_unitary_term NOT_EQUAL thf_unitary_term { $$ = MkPNeg(MkPHOEq($1, $3)) }
+  ;
+
+thf_defined_infix: thf_unitary_term EQUAL thf_unitary_term { $$ = MkPHOEq($1, $3) }
+  ;
+
+thf_unitary_term: thf_atomic_formula         { $$ = $1 }
+  | variable                                 { $$ = PVar{$1} }
+  | LEFT_PAREN thf_logic_formula RIGHT_PAREN { $$ = $2 }
+  ;
+
+thf_atomic_formula: constant { $$ = PPred{$1, []PTerm{}} }
+  | defined_constant         { $$ = PPred{$1, []PTerm{}} }
+  | TRUE                     { $$ = MkPTop() }
+  | FALSE                    { $$ = MkPBot() }
+  ;
+
+thf_atom_typing: untyped_atom COLON thf_top_level_type { $$ = Lib.MkPair($1, $3) }
+  | LEFT_PAREN thf_atom_typing RIGHT_PAREN             { $$ = $2 }
+  ;
+
+thf_top_level_type: thf_unitary_type { $$ = $1 }
+  | thf_mapping_type                 { $$ = $1 }
+  ;
+
+thf_mapping_type: thf_unitary_type ARROW thf_unitary_type { $$ = MkTypeMap($1, $3) }
+  | thf_unitary_type ARROW thf_mapping_type               { $$ = MkTypeMap($1, $3) }
+  ;
+
+thf_unitary_type: tff_atomic_type             { $$ = $1.(PType) }
+  | LEFT_PAREN thf_mapping_type RIGHT_PAREN   { $$ = $2 }
+  ;
+
 // ----------------------------------------------------------------------------
 // TFF
 // ----------------------------------------------------------------------------
@@ -438,6 +582,26 @@ fof_defined_plain_formula: defined_constant { $$ = PPred{$1, []PTerm{}} }
 fof_defined_infix_formula: fof_term EQUAL fof_term { $$ = PPred{PEqSymbol, []PTerm{$1, $3}} }
   ;
 
+// ----------------------------------------------------------------------------
+// CNF
+// ----------------------------------------------------------------------------
+
+// The variables of a clause are implicitly universally quantified: the closure
+// is done when elaborating the statement (see Engine.ToInternalSyntax).
+
+cnf_formula: cnf_disjunction                    { $$ = $1 }
+  | LEFT_PAREN cnf_disjunction RIGHT_PAREN      { $$ = $2 }
+  ;
+
+cnf_disjunction: cnf_literal                    { $$ = $1 }
+  | cnf_disjunction VLINE cnf_literal           { $$ = MkPOr($1, $3) }
+  ;
+
+cnf_literal: fof_atomic_formula                 { $$ = $1 }
+  | NOT fof_atomic_formula                      { $$ = MkPNeg($2) }
+  | fof_infix_unary                             { $$ = $1 }
+  ;
+
 // <fof_system_atomic_formula> ::= <fof_system_term>
 // %----<fof_system_atomic_formula>s are used for evaluable predicates that are
 // %----available in particular tools. The predicate names are not controlled by
@@ -507,7 +671,7 @@ variable: UPPER_WORD { $$ = $1 }
   ;
 
 include: INCLUDE LEFT_PAREN file_name formula_selection RIGHT_PAREN DOT
-{ $$ = PStatement{$3, Include, Lib.MkNone[PForm](), Lib.MkNone[Lib.Pair[string, PType]]()} }
+{ $$ = PStatement{$3, Include, Lib.MkNone[PForm](), Lib.MkNone[Lib.Pair[string, PType]](), PFOF} }
   ;
 
 formula_selection:
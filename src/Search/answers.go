@@ -0,0 +1,136 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file extracts the answers of an existential conjecture, i.e., the terms
+* chosen for its variables, from the substitution that closes the tableau.
+**/
+
+package Search
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+// The names of the metavariables that the gamma rules introduce for the
+// variables of the conjecture, in the order of the variables.
+var answerVariables []string
+
+// The answers found by the last search: one tuple of terms per instance of the
+// conjecture used in the proof.
+var answers [][]string
+
+// Registers the existential variables of the conjecture whose instances are
+// to be reported. A nil list disables the answers.
+func SetAnswerVariables(vars []AST.Var) {
+	answerVariables = nil
+	for _, v := range vars {
+		answerVariables = append(answerVariables, strings.ToUpper(v.GetName()))
+	}
+}
+
+// Returns the answers found by the last search, nil if there are none.
+func GetAnswers() [][]string {
+	return answers
+}
+
+// Retrieves the answers from the substitution of the proof. The conjecture may
+// have been instantiated several times: the instances of its variables are
+// grouped by occurrence of their metavariables, and the tuples that are not
+// fully instantiated by ground terms are left out, as any term would do.
+func recordAnswers(subst Unif.Substitutions) {
+	answers = nil
+	if len(answerVariables) == 0 {
+		return
+	}
+
+	tuples := map[int][]AST.Term{}
+	for _, s := range subst {
+		position := slices.Index(answerVariables, s.Key().GetName())
+		if position == -1 {
+			continue
+		}
+
+		occurrence := s.Key().GetOccurence()
+		if _, found := tuples[occurrence]; !found {
+			tuples[occurrence] = make([]AST.Term, len(answerVariables))
+		}
+		tuples[occurrence][position] = Core.ApplySubstitutionsOnTerm(subst, s.Value())
+	}
+
+	occurrences := []int{}
+	for occurrence := range tuples {
+		occurrences = append(occurrences, occurrence)
+	}
+	sort.Ints(occurrences)
+
+	for _, occurrence := range occurrences {
+		if tuple, isGround := answerTuple(tuples[occurrence]); isGround && !slices.ContainsFunc(answers, func(other []string) bool {
+			return slices.Equal(other, tuple)
+		}) {
+			answers = append(answers, tuple)
+		}
+	}
+}
+
+func answerTuple(terms []AST.Term) ([]string, bool) {
+	tuple := []string{}
+	for _, term := range terms {
+		if term == nil || !term.GetMetas().IsEmpty() {
+			return nil, false
+		}
+		tuple = append(tuple, modelTermToString(term))
+	}
+	return tuple, true
+}
+
+// Prints the answers in the SZS format: a disjunction of tuples, e.g.,
+// [[a,b],[c,d]|_] when either (a, b) or (c, d) is an answer.
+func printAnswers() {
+	if len(answers) == 0 {
+		return
+	}
+
+	tuples := []string{}
+	for _, tuple := range answers {
+		tuples = append(tuples, "["+strings.Join(tuple, ",")+"]")
+	}
+	fmt.Printf("%% SZS answers Tuple [%s|_] for %v\n", strings.Join(tuples, ","), Glob.GetProblemName())
+}
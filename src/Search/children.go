@@ -130,6 +130,8 @@ func closeChildren(children *[]Communication, kill bool) {
 	delayed_removal := Lib.EmptySet[Lib.Int]()
 	for i, v := range *children {
 		select {
+		case <-stopped:
+			return
 		case v.quit <- kill:
 			Glob.PrintDebug("CC", Lib.MkLazy(func() string { return "Send close order" }))
 		case res := <-v.result:
@@ -142,7 +144,11 @@ func closeChildren(children *[]Communication, kill bool) {
 				}),
 			)
 			if res.need_answer {
-				v.quit <- kill
+				select {
+				case v.quit <- kill:
+				case <-stopped:
+					return
+				}
 			} else {
 				Glob.PrintDebug(
 					"CC",
@@ -169,7 +175,11 @@ func sendSubToChildren(children []Communication, s Core.SubstAndForm) {
 			"SSTC",
 			Lib.MkLazy(func() string { return fmt.Sprintf("children : %v/%v", i+1, len(children)) }),
 		)
-		v.result <- Result{Glob.GetGID(), true, true, s.Copy(), []Core.SubstAndForm{}, Unif.MakeEmptySubstitutionList(), nil, -1, -1, Core.MakeUnifier()}
+		select {
+		case v.result <- Result{Glob.GetGID(), true, true, s.Copy(), []Core.SubstAndForm{}, Unif.MakeEmptySubstitutionList(), nil, -1, -1, Core.MakeUnifier()}:
+		case <-stopped:
+			return
+		}
 	}
 }
 
@@ -184,7 +194,11 @@ func sendForbiddenToChildren(children []Communication, s []Unif.Substitutions) {
 			"SFTC",
 			Lib.MkLazy(func() string { return fmt.Sprintf("children : %v/%v", i+1, len(children)) }),
 		)
-		v.result <- Result{Glob.GetGID(), true, true, Core.MakeEmptySubstAndForm(), []Core.SubstAndForm{}, s, nil, -1, -1, Core.MakeUnifier()}
+		select {
+		case v.result <- Result{Glob.GetGID(), true, true, Core.MakeEmptySubstAndForm(), []Core.SubstAndForm{}, s, nil, -1, -1, Core.MakeUnifier()}:
+		case <-stopped:
+			return
+		}
 	}
 }
 
@@ -239,5 +253,6 @@ func (ds *destructiveSearch) sendSubToFather(c Communication, closed, need_answe
 		}
 	case quit := <-c.quit:
 		ds.manageQuitOrder(quit, c, father_id, st, []Communication{}, given_substs, node_id, original_node_id, []int{}, meta_to_reintroduce)
+	case <-stopped:
 	}
 }
@@ -0,0 +1,344 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file builds the matrix of the connection search: the clauses of the
+* problem, as given by the rules of the tableau. Every clause keeps the way its
+* literals are derived from the formula of the problem, so that a connection
+* proof can be replayed as a tableau.
+**/
+
+package connection
+
+import (
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Core/Sko"
+	"github.com/GoelandProver/Goeland/Glob"
+)
+
+// Above this number of clauses for a single formula, the problem is deemed too
+// large to be put in clausal form (see -definitional).
+const maxClauses = 100000
+
+type matrix struct {
+	clauses []*clause
+	// The clauses having a literal of the given sign and predicate.
+	index map[literalKey][]candidate
+	// The Skolem symbols introduced by the clausal form.
+	skolems map[int]bool
+	// Equality is only handled by the reflexivity of its literals, so the
+	// search is not complete on the problems that have some.
+	hasEquality bool
+	// Set when the clausal form cannot be computed.
+	unsupported string
+}
+
+type clause struct {
+	literals   []AST.Form
+	derivation *step
+	// The metavariables of the gamma rules of the derivation, renamed in
+	// every copy of the clause.
+	metas []AST.Meta
+	// Derived from the negated conjecture.
+	goal bool
+}
+
+type literalKey struct {
+	predicate string
+	arity     int
+	positive  bool
+}
+
+type candidate struct {
+	clause  *clause
+	literal int
+}
+
+type stepKind int
+
+const (
+	literalStep stepKind = iota
+	closedStep
+	alphaStep
+	betaStep
+	gammaStep
+	deltaStep
+)
+
+// A node of the derivation of a clause, from a formula of the branch. The
+// literals of the clause are the leaves of the derivation, in order.
+type step struct {
+	kind stepKind
+	// The result of an alpha rule that the derivation goes on with, and for a
+	// beta rule, the result chosen in each branch.
+	choice   int
+	choices  []int
+	next     *step
+	branches []*step
+	// The metavariable of a gamma rule, and the Skolem term of a delta rule.
+	meta   AST.Meta
+	skolem AST.Fun
+}
+
+// A clause of a formula, while the clausal form is computed.
+type derived struct {
+	literals []AST.Form
+	step     *step
+	metas    []AST.Meta
+}
+
+func makeMatrix(root AST.Form) *matrix {
+	m := &matrix{index: map[literalKey][]candidate{}, skolems: map[int]bool{}}
+
+	// The negated conjecture is the last formula of the problem.
+	and, isAnd := root.(AST.And)
+	hasAxioms := isAnd && and.FormList.Len() > 1
+
+	for _, d := range m.clausify(root) {
+		c := &clause{literals: d.literals, derivation: d.step, metas: d.metas}
+		if Glob.IsConjectureFound() {
+			c.goal = !hasAxioms || isDerivedFrom(and, d.step)
+		}
+		m.addClause(c)
+	}
+
+	return m
+}
+
+// Whether the derivation of a clause from the root starts by choosing its last
+// formula.
+func isDerivedFrom(root AST.And, derivation *step) bool {
+	return derivation.kind == alphaStep && derivation.choice == root.FormList.Len()-1
+}
+
+func (m *matrix) addClause(c *clause) {
+	m.clauses = append(m.clauses, c)
+	for i, lit := range c.literals {
+		key := keyOf(lit)
+		m.index[key] = append(m.index[key], candidate{c, i})
+		m.hasEquality = m.hasEquality || key.predicate == AST.Id_eq.GetName()
+	}
+}
+
+// The clauses of a formula: the result of a rule on the formula holds if all
+// the clauses of its results hold.
+func (m *matrix) clausify(form AST.Form) []derived {
+	if m.unsupported != "" {
+		return nil
+	}
+
+	switch {
+	case isValid(form):
+		return nil
+	case isContradictory(form):
+		return []derived{{step: &step{kind: closedStep}}}
+	}
+
+	r := ruleOf(form)
+	switch r.kind {
+	case Core.Atomic:
+		if !isLiteral(form) {
+			m.unsupported = "the formula " + form.ToString()
+			return nil
+		}
+		return []derived{{literals: []AST.Form{form}, step: &step{kind: literalStep}}}
+
+	case Core.Alpha:
+		clauses := []derived{}
+		for i, result := range r.results[0] {
+			for _, d := range m.clausify(result) {
+				clauses = append(clauses, derived{d.literals, &step{kind: alphaStep, choice: i, next: d.step}, d.metas})
+			}
+		}
+		return clauses
+
+	case Core.Beta:
+		return m.clausifyBranches(r.results)
+
+	case Core.Gamma:
+		v, isQuantified := firstVariable(form)
+		if !isQuantified {
+			m.unsupported = "the quantification over types"
+			return nil
+		}
+		meta := AST.MakerMeta(strings.ToUpper(v.GetName()), -1, v.GetTypeApp())
+		clauses := []derived{}
+		for _, d := range m.clausify(instantiate(form, meta)) {
+			metas := append([]AST.Meta{meta}, d.metas...)
+			clauses = append(clauses, derived{d.literals, &step{kind: gammaStep, meta: meta, next: d.step}, metas})
+		}
+		return clauses
+
+	case Core.Delta:
+		v, _ := firstVariable(form)
+		skolem := Sko.MkSkolemTerm(v, form.GetMetas().Elements())
+		m.skolems[skolem.GetIndex()] = true
+		clauses := []derived{}
+		for _, d := range m.clausify(instantiate(form, skolem)) {
+			clauses = append(clauses, derived{d.literals, &step{kind: deltaStep, skolem: skolem, next: d.step}, d.metas})
+		}
+		return clauses
+	}
+
+	return nil
+}
+
+// The clauses of a disjunction of branches, each of them being the conjunction
+// of its formulas: one clause for each way to choose a clause in every branch.
+func (m *matrix) clausifyBranches(branches [][]AST.Form) []derived {
+	type partial struct {
+		literals []AST.Form
+		choices  []int
+		steps    []*step
+		metas    []AST.Meta
+	}
+	partials := []partial{{}}
+
+	for _, branch := range branches {
+		type option struct {
+			choice int
+			d      derived
+		}
+		options := []option{}
+		for i, f := range branch {
+			for _, d := range m.clausify(f) {
+				options = append(options, option{i, d})
+			}
+		}
+
+		if len(partials)*len(options) > maxClauses {
+			m.unsupported = "the size of the clausal form"
+			return nil
+		}
+
+		next := []partial{}
+		for _, p := range partials {
+			for _, o := range options {
+				next = append(next, partial{
+					appendCopy(p.literals, o.d.literals...),
+					appendCopy(p.choices, o.choice),
+					appendCopy(p.steps, o.d.step),
+					appendCopy(p.metas, o.d.metas...),
+				})
+			}
+		}
+		partials = next
+	}
+
+	clauses := []derived{}
+	for _, p := range partials {
+		clauses = append(clauses, derived{p.literals, &step{kind: betaStep, choices: p.choices, branches: p.steps}, p.metas})
+	}
+	return clauses
+}
+
+func appendCopy[T any](slice []T, values ...T) []T {
+	return append(slice[:len(slice):len(slice)], values...)
+}
+
+// The formulas that need no clause, and the ones that close a branch by
+// themselves.
+func isValid(form AST.Form) bool {
+	switch nf := form.(type) {
+	case AST.Top:
+		return true
+	case AST.Not:
+		_, isBot := nf.GetForm().(AST.Bot)
+		return isBot
+	}
+	return false
+}
+
+func isContradictory(form AST.Form) bool {
+	switch nf := form.(type) {
+	case AST.Bot:
+		return true
+	case AST.Not:
+		_, isTop := nf.GetForm().(AST.Top)
+		return isTop
+	}
+	return false
+}
+
+// Literals are indexed by their sign and their predicate.
+func keyOf(lit AST.Form) literalKey {
+	atom, positive := atomOf(lit)
+	return literalKey{atom.GetID().GetName(), atom.GetArgs().Len(), positive}
+}
+
+func complementKey(lit AST.Form) literalKey {
+	key := keyOf(lit)
+	key.positive = !key.positive
+	return key
+}
+
+func atomOf(lit AST.Form) (AST.Pred, bool) {
+	if not, isNot := lit.(AST.Not); isNot {
+		return not.GetForm().(AST.Pred), false
+	}
+	return lit.(AST.Pred), true
+}
+
+// The clauses a connection proof starts with: the ones of the negated
+// conjecture, and then the positive ones.
+func (m *matrix) startClauses() []*clause {
+	start := []*clause{}
+	for _, c := range m.clauses {
+		if c.goal {
+			start = append(start, c)
+		}
+	}
+	for _, c := range m.clauses {
+		if !c.goal && isPositive(c) {
+			start = append(start, c)
+		}
+	}
+	return start
+}
+
+func isPositive(c *clause) bool {
+	for _, lit := range c.literals {
+		if _, positive := atomOf(lit); !positive {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether a term is made of a Skolem symbol of the clausal form.
+func (m *matrix) isSkolem(t AST.Term) bool {
+	fun, isFun := t.(AST.Fun)
+	return isFun && m.skolems[fun.GetIndex()]
+}
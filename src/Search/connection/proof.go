@@ -0,0 +1,430 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file gives a connection proof as a tableau. The clause copies of the
+* proof tell which rules the tableau applies: the alpha, beta and delta rules
+* on their formulas, and the gamma rules with their terms. The rules are then
+* applied on every branch as the destructive search would do, until the branch
+* is closed, which the connections of the proof ensure.
+*
+* A term brought by a connection may contain the witness of a delta rule
+* that is applied further down the branch. The gamma rules using such a term
+* wait for the delta rule, so that its witness is fresh, unless the formula of
+* the delta rule cannot appear on the branch anymore.
+**/
+
+package connection
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+type proofBuilder struct {
+	matrix *matrix
+	subst  Unif.Substitutions
+	// The witnesses of the delta rules: one fresh symbol for each instance of
+	// a Skolem symbol of the clausal form.
+	witnesses map[string]AST.Fun
+	// The formulas the tableau applies a rule on, by their string.
+	expansions map[string][]*expansion
+	// The rules whose results include a formula, by the string of the formula.
+	producers map[string][]producer
+	// The formula of the delta rule of each witness.
+	witnessDeltas map[int]*expansion
+}
+
+// The formula of a rule of the tableau, with the terms of its gamma rules or
+// the witness of its delta rule.
+type expansion struct {
+	form    AST.Form
+	terms   []AST.Term
+	witness AST.Term
+}
+
+// A rule giving a formula: the rule of an expansion, with the index of the
+// term for a gamma rule.
+type producer struct {
+	expansion *expansion
+	term      int
+	result    AST.Form
+}
+
+type branch struct {
+	forms *AST.FormList
+	// The rules applied on the branch, with the indexes of the terms of the
+	// gamma rules.
+	applied map[*expansion]map[int]bool
+	// The literal closing the branch, nil while it is open.
+	closedBy AST.Form
+}
+
+func buildProof(m *matrix, root AST.Form, res outcome) []Search.ProofStruct {
+	b := &proofBuilder{m, res.subst, map[string]AST.Fun{}, map[string][]*expansion{}, map[string][]producer{}, map[int]*expansion{}}
+	b.walkCopy(root, res.start, res.proofs)
+	b.indexProducers()
+
+	br := &branch{forms: AST.NewFormList(), applied: map[*expansion]map[int]bool{}}
+	br.add(root)
+	return b.expand(br, Glob.IncrCptNode())
+}
+
+func (b *proofBuilder) walkCopy(root AST.Form, c *clauseCopy, proofs []*literalProof) {
+	b.walk(root, c.clause.derivation, c)
+	for _, proof := range proofs {
+		if proof != nil && proof.kind == extension {
+			b.walkCopy(root, proof.copy, proof.literals)
+		}
+	}
+}
+
+// Records the rules of the derivation of a clause copy.
+func (b *proofBuilder) walk(form AST.Form, st *step, c *clauseCopy) {
+	e := b.expansionOf(form, true)
+
+	switch st.kind {
+	case alphaStep:
+		b.walk(ruleOf(form).results[0][st.choice], st.next, c)
+
+	case gammaStep:
+		term := b.tableauTerm(c, st.meta)
+		if !slices.ContainsFunc(e.terms, func(t AST.Term) bool { return t.Equals(term) }) {
+			e.terms = append(e.terms, term)
+		}
+		b.walk(instantiate(form, term), st.next, c)
+
+	case deltaStep:
+		e.witness = b.tableauTerm(c, st.skolem)
+		if witness, isFun := e.witness.(AST.Fun); isFun {
+			b.witnessDeltas[witness.GetIndex()] = e
+		}
+		b.walk(instantiate(form, e.witness), st.next, c)
+
+	case betaStep:
+		for i, results := range ruleOf(form).results {
+			b.walk(results[st.choices[i]], st.branches[i], c)
+		}
+	}
+}
+
+func (b *proofBuilder) indexProducers() {
+	for _, expansions := range b.expansions {
+		for _, e := range expansions {
+			r := ruleOf(e.form)
+			switch r.kind {
+			case Core.Alpha, Core.Beta:
+				for _, results := range r.results {
+					for _, result := range results {
+						b.addProducer(producer{e, 0, result})
+					}
+				}
+			case Core.Gamma:
+				for i, term := range e.terms {
+					b.addProducer(producer{e, i, instantiate(e.form, term)})
+				}
+			}
+		}
+	}
+}
+
+func (b *proofBuilder) addProducer(p producer) {
+	key := p.result.ToString()
+	b.producers[key] = append(b.producers[key], p)
+}
+
+func (b *proofBuilder) expansionOf(form AST.Form, create bool) *expansion {
+	key := form.ToString()
+	for _, e := range b.expansions[key] {
+		if e.form.Equals(form) {
+			return e
+		}
+	}
+	if !create {
+		return nil
+	}
+	e := &expansion{form: form}
+	b.expansions[key] = append(b.expansions[key], e)
+	return e
+}
+
+// Applies one rule on the branch, the alpha and delta rules first, then the
+// beta rules and the gamma rules last, and goes on until it is closed.
+func (b *proofBuilder) expand(br *branch, nodeId int) []Search.ProofStruct {
+	if br.closedBy != nil {
+		return []Search.ProofStruct{closure(br.closedBy, nodeId)}
+	}
+
+	var beta *expansion
+	gammas := []*expansion{}
+	for _, f := range br.forms.Slice() {
+		e := b.expansionOf(f, false)
+		r := ruleOf(f)
+		if e == nil || r.kind != Core.Gamma && br.isApplied(e, 0) {
+			continue
+		}
+
+		switch r.kind {
+		case Core.Alpha:
+			return b.applyLinearRule(br, e, r, r.results[0], 0, nodeId)
+		case Core.Delta:
+			return b.applyLinearRule(br, e, r, []AST.Form{instantiate(f, e.witness)}, 0, nodeId)
+		case Core.Beta:
+			if beta == nil {
+				beta = e
+			}
+		case Core.Gamma:
+			gammas = append(gammas, e)
+		}
+	}
+
+	if beta != nil {
+		return b.applyBetaRule(br, beta, nodeId)
+	}
+
+	// Without a gamma rule whose term is ready, the first one is applied.
+	var pending *expansion
+	pendingTerm := -1
+	for _, e := range gammas {
+		for i, term := range e.terms {
+			if br.isApplied(e, i) {
+				continue
+			}
+			if b.isReady(term, br) {
+				return b.applyLinearRule(br, e, ruleOf(e.form), []AST.Form{instantiate(e.form, term)}, i, nodeId)
+			}
+			if pending == nil {
+				pending, pendingTerm = e, i
+			}
+		}
+	}
+	if pending != nil {
+		return b.applyLinearRule(br, pending, ruleOf(pending.form), []AST.Form{instantiate(pending.form, pending.terms[pendingTerm])}, pendingTerm, nodeId)
+	}
+
+	Glob.Anomaly("Connection", "A branch of the tableau cannot be closed")
+	return nil
+}
+
+func (b *proofBuilder) applyLinearRule(br *branch, e *expansion, r rule, results []AST.Form, term, nodeId int) []Search.ProofStruct {
+	br.markApplied(e, term)
+	if br.containsAll(results) {
+		return b.expand(br, nodeId)
+	}
+
+	proof := makeStep(e.form, r, nodeId)
+	childId := Glob.IncrCptNode()
+	proof.Result_formulas = []Search.IntFormAndTermsList{Search.MakeIntFormAndTermsList(childId, formAndTermsList(results))}
+
+	br.add(results...)
+	return append([]Search.ProofStruct{proof}, b.expand(br, childId)...)
+}
+
+func (b *proofBuilder) applyBetaRule(br *branch, e *expansion, nodeId int) []Search.ProofStruct {
+	r := ruleOf(e.form)
+	br.markApplied(e, 0)
+	for _, results := range r.results {
+		if br.containsAll(results) {
+			return b.expand(br, nodeId)
+		}
+	}
+
+	proof := makeStep(e.form, r, nodeId)
+	for _, results := range r.results {
+		childId := Glob.IncrCptNode()
+		proof.Result_formulas = append(proof.Result_formulas, Search.MakeIntFormAndTermsList(childId, formAndTermsList(results)))
+
+		child := br.copy()
+		child.add(results...)
+		proof.Children = append(proof.Children, b.expand(child, childId))
+	}
+	return []Search.ProofStruct{proof}
+}
+
+// A term is ready to be used on the branch when the delta rules of its
+// witnesses are applied, or when their formulas cannot appear on the branch
+// anymore, but through rules using the witnesses.
+func (b *proofBuilder) isReady(term AST.Term, br *branch) bool {
+	fun, isFun := term.(AST.Fun)
+	if !isFun {
+		return true
+	}
+	for _, arg := range fun.GetArgs().GetSlice() {
+		if !b.isReady(arg, br) {
+			return false
+		}
+	}
+
+	delta, isWitness := b.witnessDeltas[fun.GetIndex()]
+	return !isWitness || br.isApplied(delta, 0) || !b.mayAppear(delta.form, br, fun, map[*expansion]bool{})
+}
+
+// Whether a formula is on the branch, or may be given by a rule applied
+// later on the branch without using the witness.
+func (b *proofBuilder) mayAppear(form AST.Form, br *branch, witness AST.Fun, visited map[*expansion]bool) bool {
+	if br.forms.Contains(form) {
+		return true
+	}
+
+	for _, p := range b.producers[form.ToString()] {
+		if !p.result.Equals(form) || visited[p.expansion] || br.isApplied(p.expansion, p.term) {
+			continue
+		}
+		if len(p.expansion.terms) > 0 && p.expansion.terms[p.term].GetSubTerms().Contains(witness, AST.TermEquals) {
+			continue
+		}
+		visited[p.expansion] = true
+		if b.mayAppear(p.expansion.form, br, witness, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// The term of the tableau standing for a term of a clause copy: the Skolem
+// symbols of the clausal form are replaced by the witnesses of the delta rules.
+func (b *proofBuilder) tableauTerm(c *clauseCopy, t AST.Term) AST.Term {
+	return b.witnessesOf(substituteTerm(b.subst, Core.ApplySubstitutionsOnTerm(c.renaming, t)))
+}
+
+func (b *proofBuilder) witnessesOf(t AST.Term) AST.Term {
+	fun, isFun := t.(AST.Fun)
+	if !isFun {
+		return t
+	}
+
+	args := Lib.ListMap(fun.GetArgs(), b.witnessesOf)
+	if !b.matrix.isSkolem(fun) {
+		return AST.MakerFun(fun.GetID(), args, fun.GetTypeVars(), fun.GetTypeHint())
+	}
+
+	argStrings := []string{}
+	for _, arg := range args.GetSlice() {
+		argStrings = append(argStrings, arg.ToString())
+	}
+	key := fmt.Sprintf("%d(%s)", fun.GetIndex(), strings.Join(argStrings, ","))
+
+	witness, found := b.witnesses[key]
+	if !found {
+		witness = AST.MakerFun(AST.MakerNewId(fun.GetName()), args, fun.GetTypeVars(), fun.GetTypeHint())
+		b.witnesses[key] = witness
+	}
+	return witness
+}
+
+func (br *branch) add(forms ...AST.Form) {
+	for _, f := range forms {
+		if br.closedBy == nil && br.isClosedBy(f) {
+			br.closedBy = f
+		}
+		br.forms.Append(f)
+	}
+}
+
+// A branch is closed by a contradictory formula, a negated reflexive equality
+// or complementary literals.
+func (br *branch) isClosedBy(f AST.Form) bool {
+	if isContradictory(f) {
+		return true
+	}
+	if !isLiteral(f) {
+		return false
+	}
+
+	atom, positive := atomOf(f)
+	if positive {
+		return br.forms.Contains(AST.MakerNot(atom))
+	}
+	args := atom.GetArgs()
+	isReflexive := atom.GetID().Equals(AST.Id_eq) && args.Len() == 2 && args.At(0).Equals(args.At(1))
+	return isReflexive || br.forms.Contains(atom)
+}
+
+func (br *branch) containsAll(forms []AST.Form) bool {
+	for _, f := range forms {
+		if !br.forms.Contains(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (br *branch) isApplied(e *expansion, term int) bool {
+	return br.applied[e][term]
+}
+
+func (br *branch) markApplied(e *expansion, term int) {
+	if br.applied[e] == nil {
+		br.applied[e] = map[int]bool{}
+	}
+	br.applied[e][term] = true
+}
+
+func (br *branch) copy() *branch {
+	applied := map[*expansion]map[int]bool{}
+	for e, terms := range br.applied {
+		applied[e] = map[int]bool{}
+		for term := range terms {
+			applied[e][term] = true
+		}
+	}
+	return &branch{br.forms.Copy(), applied, br.closedBy}
+}
+
+func makeStep(form AST.Form, r rule, nodeId int) Search.ProofStruct {
+	proof := Search.MakeEmptyProofStruct()
+	proof.Formula = Core.MakeFormAndTerm(form, Lib.NewList[AST.Term]())
+	proof.Node_id = nodeId
+	proof.Rule = r.symbol
+	proof.Rule_name = r.name
+	return proof
+}
+
+func closure(lit AST.Form, nodeId int) Search.ProofStruct {
+	return makeStep(lit, rule{name: "CLOSURE", symbol: "⊙"}, nodeId)
+}
+
+func formAndTermsList(forms []AST.Form) Core.FormAndTermsList {
+	list := Core.MakeEmptyFormAndTermsList()
+	for _, f := range forms {
+		list = list.Append(Core.MakeFormAndTerm(f, Lib.NewList[AST.Term]()))
+	}
+	return list
+}
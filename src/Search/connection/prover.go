@@ -0,0 +1,338 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the connection calculus, in the style of leanCoP: the
+* literals of a clause are refuted one after the other, either by a reduction
+* with a literal of the path leading to them or by an extension with a copy of
+* a clause having a complementary literal. The search is bounded by the length
+* of the paths, which is increased step by step.
+**/
+
+package connection
+
+import (
+	"fmt"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+// How many inferences are made between two checks of the stop order.
+const stopCheckInterval = 256
+
+type prover struct {
+	matrix *matrix
+	// The limit on the length of the paths, beyond which only the ground
+	// clauses may extend a path.
+	limit        int
+	limitReached bool
+	// With restricted backtracking, a literal is refuted in a single way.
+	restricted bool
+	stop       <-chan struct{}
+	stopped    bool
+	inferences int
+}
+
+// A copy of a clause, with fresh metavariables.
+type clauseCopy struct {
+	clause   *clause
+	literals []AST.Form
+	renaming Unif.Substitutions
+}
+
+type proofKind int
+
+const (
+	reduction proofKind = iota
+	reflexivity
+	extension
+)
+
+// How a literal of a clause copy is refuted. An extension gives the copy that
+// is connected to the literal by its entry literal, and how the other literals
+// of the copy are refuted.
+type literalProof struct {
+	kind     proofKind
+	copy     *clauseCopy
+	entry    int
+	literals []*literalProof
+}
+
+// The outcome of the search, with the start clause of the proof and the
+// unifier of its connections when one is found.
+type outcome struct {
+	proved bool
+	// The search space has been exhausted without finding a proof.
+	exhausted bool
+	start     *clauseCopy
+	proofs    []*literalProof
+	subst     Unif.Substitutions
+}
+
+func prove(m *matrix, stop <-chan struct{}) outcome {
+	p := &prover{matrix: m, restricted: !Glob.GetCompleteness(), stop: stop}
+
+	for limit := 1; Glob.GetLimit() < 0 || limit <= Glob.GetLimit(); limit++ {
+		Glob.PrintInfo("Connection", fmt.Sprintf("Path limit: %d, restricted backtracking: %v", limit, p.restricted))
+		p.limit = limit
+		p.limitReached = false
+
+		if res, found := p.proveFromStartClauses(); found || p.stopped {
+			return res
+		}
+
+		if !p.limitReached {
+			if !p.restricted {
+				return outcome{exhausted: true}
+			}
+			// The refutations left out by the restricted backtracking may be
+			// needed: the search goes on with all of them.
+			p.restricted = false
+			limit--
+		}
+	}
+
+	return outcome{}
+}
+
+func (p *prover) proveFromStartClauses() (outcome, bool) {
+	for _, c := range p.matrix.startClauses() {
+		start := p.copyClause(c)
+		res := outcome{}
+		found := p.proveLiterals(start, 0, -1, nil, Unif.MakeEmptySubstitution(), make([]*literalProof, len(start.literals)),
+			func(subst Unif.Substitutions, proofs []*literalProof) bool {
+				res = outcome{proved: true, start: start, proofs: proofs, subst: subst}
+				return true
+			})
+		if found || p.stopped {
+			return res, found
+		}
+	}
+	return outcome{}, false
+}
+
+// Refutes the literals of a clause copy from the i-th one, except its entry
+// literal, and then calls the continuation. Returns true as soon as a
+// continuation does.
+func (p *prover) proveLiterals(
+	c *clauseCopy,
+	i, entry int,
+	path []AST.Form,
+	subst Unif.Substitutions,
+	proofs []*literalProof,
+	k func(Unif.Substitutions, []*literalProof) bool,
+) bool {
+	if i == entry {
+		i++
+	}
+	if i >= len(c.literals) {
+		return k(subst, proofs)
+	}
+
+	withProof := func(proof *literalProof) []*literalProof {
+		updated := append([]*literalProof{}, proofs...)
+		updated[i] = proof
+		return updated
+	}
+
+	if !p.restricted {
+		return p.proveLiteral(c.literals[i], path, subst, func(s Unif.Substitutions, proof *literalProof) bool {
+			return p.proveLiterals(c, i+1, entry, path, s, withProof(proof), k)
+		})
+	}
+
+	// The first refutation of the literal is kept, and no other one is tried
+	// if the next literals cannot be refuted.
+	var refuted Unif.Substitutions
+	var proof *literalProof
+	if !p.proveLiteral(c.literals[i], path, subst, func(s Unif.Substitutions, lp *literalProof) bool {
+		refuted, proof = s, lp
+		return true
+	}) {
+		return false
+	}
+	return p.proveLiterals(c, i+1, entry, path, refuted, withProof(proof), k)
+}
+
+func (p *prover) proveLiteral(
+	lit AST.Form,
+	path []AST.Form,
+	subst Unif.Substitutions,
+	k func(Unif.Substitutions, *literalProof) bool,
+) bool {
+	if p.isStopped() {
+		return false
+	}
+
+	// Regularity: no literal occurs twice on a path.
+	instance := substituteForm(subst, lit)
+	for _, pathLit := range path {
+		if substituteForm(subst, pathLit).Equals(instance) {
+			return false
+		}
+	}
+
+	if s, ok := unifyReflexivity(lit, subst); ok && k(s, &literalProof{kind: reflexivity}) {
+		return true
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if s, ok := unifyComplementary(lit, path[i], subst); ok && k(s, &literalProof{kind: reduction}) {
+			return true
+		}
+	}
+
+	return p.extend(lit, path, subst, k)
+}
+
+func (p *prover) extend(
+	lit AST.Form,
+	path []AST.Form,
+	subst Unif.Substitutions,
+	k func(Unif.Substitutions, *literalProof) bool,
+) bool {
+	extended := appendCopy(path, lit)
+
+	for _, cand := range p.matrix.index[complementKey(lit)] {
+		if len(cand.clause.metas) > 0 && len(path) >= p.limit {
+			p.limitReached = true
+			continue
+		}
+
+		c := p.copyClause(cand.clause)
+		s, ok := unifyComplementary(lit, c.literals[cand.literal], subst)
+		if !ok {
+			continue
+		}
+
+		found := p.proveLiterals(c, 0, cand.literal, extended, s, make([]*literalProof, len(c.literals)),
+			func(s Unif.Substitutions, proofs []*literalProof) bool {
+				return k(s, &literalProof{kind: extension, copy: c, entry: cand.literal, literals: proofs})
+			})
+		if found || p.stopped {
+			return found
+		}
+	}
+
+	return false
+}
+
+func (p *prover) isStopped() bool {
+	p.inferences++
+	if p.inferences%stopCheckInterval == 0 {
+		select {
+		case <-p.stop:
+			p.stopped = true
+		default:
+		}
+	}
+	return p.stopped
+}
+
+func (p *prover) copyClause(c *clause) *clauseCopy {
+	renaming := Unif.MakeEmptySubstitution()
+	for _, meta := range c.metas {
+		renaming.Set(meta, AST.MakerMeta(meta.GetName(), -1, meta.GetTypeApp()))
+	}
+
+	literals := []AST.Form{}
+	for _, lit := range c.literals {
+		literals = append(literals, Core.ApplySubstitutionsOnFormula(renaming, lit))
+	}
+	return &clauseCopy{c, literals, renaming}
+}
+
+// Unifies the atoms of two literals of opposite signs.
+func unifyComplementary(lit1, lit2 AST.Form, subst Unif.Substitutions) (Unif.Substitutions, bool) {
+	atom1, positive1 := atomOf(lit1)
+	atom2, positive2 := atomOf(lit2)
+	if positive1 == positive2 || !atom1.GetID().Equals(atom2.GetID()) || atom1.GetArgs().Len() != atom2.GetArgs().Len() {
+		return nil, false
+	}
+
+	args1, args2 := atom1.GetArgs().GetSlice(), atom2.GetArgs().GetSlice()
+	for i := range args1 {
+		var ok bool
+		if subst, ok = unifyTerms(args1[i], args2[i], subst); !ok {
+			return nil, false
+		}
+	}
+	return subst, true
+}
+
+// A negated equality is refuted when its terms are unifiable.
+func unifyReflexivity(lit AST.Form, subst Unif.Substitutions) (Unif.Substitutions, bool) {
+	atom, positive := atomOf(lit)
+	if positive || !atom.GetID().Equals(AST.Id_eq) || atom.GetArgs().Len() != 2 {
+		return nil, false
+	}
+	return unifyTerms(atom.GetArgs().At(0), atom.GetArgs().At(1), subst)
+}
+
+func unifyTerms(t1, t2 AST.Term, subst Unif.Substitutions) (Unif.Substitutions, bool) {
+	t1 = substituteTerm(subst, t1)
+	t2 = substituteTerm(subst, t2)
+	if t1.Equals(t2) {
+		return subst, true
+	}
+
+	unified := Unif.AddUnification(t1, t2, subst.Copy())
+	if unified.Equals(Unif.Failure()) {
+		return nil, false
+	}
+	return unified, substituteTerm(unified, t1).Equals(substituteTerm(unified, t2))
+}
+
+// Applies a substitution until its metavariables are all replaced, as the
+// substitution may bind a metavariable to a term containing another one.
+func substituteTerm(subst Unif.Substitutions, t AST.Term) AST.Term {
+	for {
+		next := Core.ApplySubstitutionsOnTerm(subst, t)
+		if next.Equals(t) {
+			return next
+		}
+		t = next
+	}
+}
+
+func substituteForm(subst Unif.Substitutions, f AST.Form) AST.Form {
+	for {
+		next := Core.ApplySubstitutionsOnFormula(subst, f)
+		if next.Equals(f) {
+			return next
+		}
+		f = next
+	}
+}
@@ -0,0 +1,169 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file decomposes the formulas as the rules of the tableau do, for the
+* clausal form and for the proofs.
+**/
+
+package connection
+
+import (
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+)
+
+// The rule of the tableau that applies on a formula, with its names in the
+// proofs and its results: a single list for an alpha rule, and one list per
+// branch for a beta rule.
+type rule struct {
+	kind    Core.KindOfRule
+	name    string
+	symbol  string
+	results [][]AST.Form
+}
+
+func ruleOf(form AST.Form) rule {
+	switch nf := form.(type) {
+	case AST.And:
+		return rule{Core.Alpha, "ALPHA_AND", "α∧", [][]AST.Form{nf.FormList.Slice()}}
+	case AST.Or:
+		return rule{Core.Beta, "BETA_OR", "β∨", oneFormPerBranch(nf.FormList.Slice())}
+	case AST.Imp:
+		return rule{Core.Beta, "BETA_IMPLY", "β⇒", [][]AST.Form{{AST.MakerNot(nf.GetF1())}, {nf.GetF2()}}}
+	case AST.Equ:
+		return rule{Core.Beta, "BETA_EQUIV", "β⇔", [][]AST.Form{
+			{AST.MakerNot(nf.GetF1()), AST.MakerNot(nf.GetF2())},
+			{nf.GetF1(), nf.GetF2()},
+		}}
+	case AST.All, AST.AllType:
+		return rule{kind: Core.Gamma, name: "GAMMA_FORALL", symbol: "γ∀"}
+	case AST.Ex:
+		return rule{kind: Core.Delta, name: "DELTA_EXISTS", symbol: "δ∃"}
+	case AST.Not:
+		return ruleOfNegation(nf.GetForm())
+	}
+	return rule{kind: Core.Atomic}
+}
+
+func ruleOfNegation(form AST.Form) rule {
+	switch nf := form.(type) {
+	case AST.Not:
+		return rule{Core.Alpha, "ALPHA_NOT_NOT", "α¬¬", [][]AST.Form{{nf.GetForm()}}}
+	case AST.Or:
+		return rule{Core.Alpha, "ALPHA_NOT_OR", "α¬∨", [][]AST.Form{negateAll(nf.FormList.Slice())}}
+	case AST.Imp:
+		return rule{Core.Alpha, "ALPHA_NOT_IMPLY", "α¬⇒", [][]AST.Form{{nf.GetF1(), AST.MakerNot(nf.GetF2())}}}
+	case AST.And:
+		return rule{Core.Beta, "BETA_NOT_AND", "β¬∧", oneFormPerBranch(negateAll(nf.FormList.Slice()))}
+	case AST.Equ:
+		return rule{Core.Beta, "BETA_NOT_EQUIV", "β¬⇔", [][]AST.Form{
+			{AST.MakerNot(nf.GetF1()), nf.GetF2()},
+			{nf.GetF1(), AST.MakerNot(nf.GetF2())},
+		}}
+	case AST.Ex:
+		return rule{kind: Core.Gamma, name: "GAMMA_NOT_EXISTS", symbol: "γ¬∃"}
+	case AST.All:
+		return rule{kind: Core.Delta, name: "DELTA_NOT_FORALL", symbol: "δ¬∀"}
+	}
+	return rule{kind: Core.Atomic}
+}
+
+func oneFormPerBranch(forms []AST.Form) [][]AST.Form {
+	branches := [][]AST.Form{}
+	for _, f := range forms {
+		branches = append(branches, []AST.Form{f})
+	}
+	return branches
+}
+
+func negateAll(forms []AST.Form) []AST.Form {
+	negated := []AST.Form{}
+	for _, f := range forms {
+		negated = append(negated, AST.MakerNot(f))
+	}
+	return negated
+}
+
+// The literals are the atoms and their negations.
+func isLiteral(form AST.Form) bool {
+	if not, isNot := form.(AST.Not); isNot {
+		form = not.GetForm()
+	}
+	_, isPred := form.(AST.Pred)
+	return isPred
+}
+
+// Returns the first variable of a quantified formula, possibly negated.
+func firstVariable(form AST.Form) (AST.Var, bool) {
+	if not, isNot := form.(AST.Not); isNot {
+		form = not.GetForm()
+	}
+
+	switch nf := form.(type) {
+	case AST.All:
+		return nf.GetVarList()[0], true
+	case AST.Ex:
+		return nf.GetVarList()[0], true
+	}
+	return AST.Var{}, false
+}
+
+// Replaces the first variable of a quantified formula, possibly negated, by a
+// term, as the gamma and the delta rules do.
+func instantiate(form AST.Form, term AST.Term) AST.Form {
+	not, isNot := form.(AST.Not)
+	if isNot {
+		form = not.GetForm()
+	}
+
+	var vars []AST.Var
+	var body AST.Form
+	switch nf := form.(type) {
+	case AST.All:
+		vars, body = nf.GetVarList(), nf.GetForm()
+		if len(vars) > 1 {
+			body = AST.MakerAll(vars[1:], body)
+		}
+	case AST.Ex:
+		vars, body = nf.GetVarList(), nf.GetForm()
+		if len(vars) > 1 {
+			body = AST.MakerEx(vars[1:], body)
+		}
+	}
+
+	result, _ := body.ReplaceTermByTerm(vars[0], term)
+	if isNot {
+		result = AST.MakerNot(result)
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the connection search, an alternative to the tableau
+* search that refutes the clausal form of the problem with the connection
+* calculus, and gives its proof as a tableau.
+**/
+
+package connection
+
+import (
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+type connectionSearch struct{}
+
+func NewConnectionSearch() Search.SearchAlgorithm {
+	return &connectionSearch{}
+}
+
+func (cs *connectionSearch) Search(formula AST.Form, bound int) bool {
+	m := makeMatrix(formula)
+	if m.unsupported != "" {
+		Glob.PrintInfo("Connection", "The clausal form cannot handle "+m.unsupported)
+		Search.PrintNoResult(Search.GaveUpStatus)
+		return false
+	}
+	Glob.PrintInfo("Connection", "Clausal form computed")
+
+	outcomes := make(chan outcome)
+	stop := make(chan struct{})
+	go func() { outcomes <- prove(m, stop) }()
+	Glob.IncrGoRoutine(1)
+
+	res, done := Search.AwaitSearch(outcomes, stop)
+	switch {
+	case Search.IsTimedOut():
+		Search.PrintNoResult(Search.TimeoutStatus)
+	case Search.IsResourceOut():
+		Search.PrintNoResult(Search.ResourceOutStatus)
+	case done && res.proved:
+		Search.PrintSearchResult(true)
+		proof := buildProof(m, formula, res)
+		Search.PrintProof(proof, Search.RetrieveUninstantiatedMetaFromProof(proof))
+	case !res.exhausted || m.hasEquality:
+		Search.PrintNoResult(Search.GaveUpStatus)
+	default:
+		Search.PrintSearchResult(false)
+	}
+
+	return done && res.proved
+}
+
+func (cs *connectionSearch) SetApplyRules(func(uint64, Search.State, Search.Communication, Core.FormAndTermsList, int, int, []int)) {
+	Glob.PrintError("Connection", "Connection search not compatible with the assisted plugin for now.")
+}
+
+// ManageClosureRule implements Search.SearchAlgorithm.
+func (cs *connectionSearch) ManageClosureRule(uint64, *Search.State, Search.Communication, []Unif.Substitutions, Core.FormAndTerms, int, int) (bool, []Core.SubstAndForm) {
+	Glob.PrintError("Connection", "Connection search not compatible with the equality plugin for now.")
+	return false, []Core.SubstAndForm{}
+}
@@ -84,11 +84,21 @@ func (ds *destructiveSearch) Search(formula AST.Form, bound int) bool {
 	Glob.SetNbStep(1)
 	limit := bound
 
-	for ok := true; ok; ok = (!res && bound > 0 && !Glob.IsOneStep()) {
+	for ok := true; ok; ok = (!res && bound > 0 && !Glob.IsOneStep() && !IsTimedOut() && !IsResourceOut() && !hasSaturatedBranch()) {
 		res, limit = ds.doOneStep(limit, formula)
 	}
 
-	if !res || !Glob.GetProof() {
+	switch {
+	case IsTimedOut():
+		PrintNoResult(TimeoutStatus)
+	case IsResourceOut():
+		PrintNoResult(ResourceOutStatus)
+	case !res && bound > 0 && !hasSaturatedBranch():
+		// Only -one_step stops the search before a proof or, with -completeness,
+		// an open saturated branch is found. A bound of 0 means that there is no
+		// quantifier, so that the first step is exhaustive.
+		PrintNoResult(GaveUpStatus)
+	case !res || !Glob.GetProof():
 		PrintSearchResult(res)
 	}
 
@@ -99,6 +109,7 @@ func (ds *destructiveSearch) doOneStep(limit int, formula AST.Form) (bool, int)
 	AST.ResetMeta()
 	// proof.ResetProofFile()
 	ResetExchangesFile()
+	resetSaturatedBranch()
 
 	Glob.PrintInfo("MAIN", fmt.Sprintf("nb_step : %v - limit : %v", Glob.GetNbStep(), limit))
 
@@ -122,7 +133,7 @@ func (ds *destructiveSearch) doOneStep(limit int, formula AST.Form) (bool, int)
 	}
 
 	nodeId := Glob.IncrCptNode()
-	go ds.ProofSearch(Glob.GetGID(), state, c, Core.MakeEmptySubstAndForm(), nodeId, nodeId, []int{}, false)
+	ds.goProofSearch(Glob.GetGID(), state, c, Core.MakeEmptySubstAndForm(), nodeId, nodeId, []int{}, false)
 	Glob.IncrGoRoutine(1)
 
 	Glob.PrintDebug("MAIN", Lib.MkLazy(func() string { return "GO" }))
@@ -137,8 +148,11 @@ func (ds *destructiveSearch) doOneStep(limit int, formula AST.Form) (bool, int)
 	)
 
 	unifier, finalProof, result := ds.manageResult(c)
+	stopSearchGoroutines()
 
 	if result {
+		recordAnswers(unifier.GetUnifier())
+
 		if Glob.GetProof() {
 			PrintSearchResult(result)
 		}
@@ -243,6 +257,17 @@ func (ds *destructiveSearch) searchContradiction(atomic AST.Form, father_id uint
 	return false
 }
 
+// Runs ProofSearch in a goroutine that the search waits for. A panic in the
+// goroutine cannot be recovered by the caller of the search: it stops the
+// prover, and is kept for the errors of the prover itself.
+func (ds *destructiveSearch) goProofSearch(father_id uint64, st State, cha Communication, s Core.SubstAndForm, node_id int, original_node_id int, meta_to_reintroduce []int, post_dmt_step bool) {
+	searchGoroutines.Add(1)
+	go func() {
+		defer searchGoroutines.Done()
+		ds.ProofSearch(father_id, st, cha, s, node_id, original_node_id, meta_to_reintroduce, post_dmt_step)
+	}()
+}
+
 /**
 * ProofSearch
 * Search algorithm (Tableaux method)
@@ -284,7 +309,11 @@ func (ds *destructiveSearch) ProofSearch(father_id uint64, st State, cha Communi
 	select {
 	case quit := <-cha.quit:
 		ds.manageQuitOrder(quit, cha, father_id, st, nil, st.GetSubstsFound(), node_id, original_node_id, nil, meta_to_reintroduce)
+	case <-stopped:
+		return
 	default:
+		st.ownBranchForms()
+
 		// Apply subst if needed
 		if !s.IsEmpty() {
 			Glob.PrintDebug(
@@ -322,6 +351,10 @@ func (ds *destructiveSearch) ProofSearch(father_id uint64, st State, cha Communi
 		Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Dispatch" }))
 		step_atomics := Core.MakeEmptyFormAndTermsList()
 		for _, f := range st.GetLF() {
+			if st.isOnBranch(f) {
+				Glob.PrintDebug("PS", Lib.MkLazy(func() string { return fmt.Sprintf("Already on the branch: %v", f.ToString()) }))
+				continue
+			}
 			if Core.ShowKindOfRule(f.GetForm()) == Core.Atomic {
 				if searchObviousClosureRule(f.GetForm()) {
 					ds.ManageClosureRule(father_id, &st, cha, []Unif.Substitutions{}, f, node_id, original_node_id)
@@ -396,7 +429,11 @@ func (ds *destructiveSearch) ProofSearch(father_id uint64, st State, cha Communi
 		)
 
 		// DoCorrectApplyRules is defined by default as ApplyRules, or to ApplyRulesAssisted if assisted flag is given.
-		go ds.doCorrectApplyRules(father_id, st, cha, atomics_dmt, node_id, original_node_id, meta_to_reintroduce)
+		searchGoroutines.Add(1)
+		go func() {
+			defer searchGoroutines.Done()
+			ds.doCorrectApplyRules(father_id, st, cha, atomics_dmt, node_id, original_node_id, meta_to_reintroduce)
+		}()
 	}
 }
 
@@ -438,6 +475,8 @@ func (ds *destructiveSearch) waitChildren(args wcdArgs) {
 		WriteExchanges(args.fatherId, args.st, args.givenSubsts, args.currentSubst, "WaitChildren - Die")
 		ds.manageQuitOrder(quit, args.c, args.fatherId, args.st, args.children, args.givenSubsts, args.nodeId, args.originalNodeId, args.childOrdering, args.toReintroduce)
 		return
+	case <-stopped:
+		return
 	default:
 		Glob.PrintDebug(
 			"WC",
@@ -510,6 +549,9 @@ func (ds *destructiveSearch) waitFather(father_id uint64, st State, c Communicat
 		ds.manageQuitOrder(quit, c, father_id, st, []Communication{}, given_substs, node_id, original_node_id, child_order, meta_to_reintroduce)
 		return
 
+	case <-stopped:
+		return
+
 	case answer_father := <-c.result:
 		subst := answer_father.getSubstForChildren()
 
@@ -631,7 +673,7 @@ func (ds *destructiveSearch) waitFather(father_id uint64, st State, c Communicat
 					return fmt.Sprintf("Forbidden : %v", Unif.SubstListToString(st_copy.GetForbiddenSubsts()))
 				}),
 			)
-			go ds.ProofSearch(Glob.GetGID(), st_copy, c2, answer_father.getSubstForChildren(), node_id, original_node_id, new_meta_to_reintroduce, false)
+			ds.goProofSearch(Glob.GetGID(), st_copy, c2, answer_father.getSubstForChildren(), node_id, original_node_id, new_meta_to_reintroduce, false)
 			Glob.IncrGoRoutine(1)
 
 			Glob.PrintDebug("WF", Lib.MkLazy(func() string { return "GO !" }))
@@ -682,7 +724,7 @@ func (ds *destructiveSearch) selectChildren(father Communication, children *[]Co
 	proof_tab := make([][]ProofStruct, len(child_order))
 
 	// Select structure
-	cases := make([]reflect.SelectCase, len(*children)+1)
+	cases := make([]reflect.SelectCase, len(*children)+2)
 	for i, ch := range *children {
 		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch.result)}
 	}
@@ -691,6 +733,10 @@ func (ds *destructiveSearch) selectChildren(father Communication, children *[]Co
 	index_quit := len(*children)
 	cases[index_quit] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(father.quit)}
 
+	// The search has returned: no more answers are expected
+	index_stopped := len(*children) + 1
+	cases[index_stopped] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stopped)}
+
 	// Result struct
 	result_int := ERROR
 	result_subst := []Core.SubstAndForm{}
@@ -719,7 +765,10 @@ func (ds *destructiveSearch) selectChildren(father Communication, children *[]Co
 		Glob.PrintDebug("SLC", Lib.MkLazy(func() string { return "Answer received" }))
 
 		// Manage quit order
-		if index == index_quit {
+		if index == index_stopped {
+			Glob.PrintDebug("SLC", Lib.MkLazy(func() string { return "Search stopped" }))
+			result_int = QUIT
+		} else if index == index_quit {
 			Glob.PrintDebug("SLC", Lib.MkLazy(func() string { return "Quit order" }))
 			if !value.Interface().(bool) {
 				Glob.PrintDebug("SLC", Lib.MkLazy(func() string { return "Quit order says to wait father" }))
@@ -1041,7 +1090,7 @@ func (ds *destructiveSearch) tryRewrite(rewritten []Core.IntSubstAndForm, f Core
 		otherState.SetBTOnFormulas(false)
 
 		channelChild := Communication{make(chan bool), make(chan Result)}
-		go ds.ProofSearch(Glob.GetGID(), otherState, channelChild, choosenRewritten.GetSaf().ToSubstAndForm(), childNode, childNode, []int{}, false)
+		ds.goProofSearch(Glob.GetGID(), otherState, channelChild, choosenRewritten.GetSaf().ToSubstAndForm(), childNode, childNode, []int{}, false)
 		Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "GO !" }))
 		Glob.IncrGoRoutine(1)
 		ds.waitChildren(MakeWcdArgs(fatherId, *state, c, []Communication{channelChild}, []Core.SubstAndForm{}, choosenRewritten.GetSaf().ToSubstAndForm(), []Core.SubstAndForm{}, newRewritten, currentNodeId, originalNodeId, false, []int{childNode}, metaToReintroduce))
@@ -1067,6 +1116,10 @@ func (ds *destructiveSearch) tryRewrite(rewritten []Core.IntSubstAndForm, f Core
 **/
 func (ds *destructiveSearch) ManageClosureRule(father_id uint64, st *State, c Communication, substs []Unif.Substitutions, f Core.FormAndTerms, node_id int, original_node_id int) (bool, []Core.SubstAndForm) {
 
+	for _, s := range substs {
+		reintroductionPolicy.RecordClosure(st.meta_generator, s)
+	}
+
 	mm := st.GetMM().Copy()
 	subst := st.GetAppliedSubst().GetSubst()
 	mm = mm.Union(Core.GetMetaFromSubst(subst))
@@ -1091,6 +1144,9 @@ func (ds *destructiveSearch) ManageClosureRule(father_id uint64, st *State, c Co
 		// Proof
 		st.SetCurrentProofRule("⊙")
 		st.SetCurrentProofRuleName("CLOSURE")
+		if isArithmeticClosure(f.GetForm(), st) {
+			st.SetCurrentProofRuleName("ARITHMETIC_CLOSURE")
+		}
 		st.SetCurrentProofFormula(f.Copy())
 		st.SetCurrentProofNodeId(node_id)
 		st.SetCurrentProofResultFormulas([]IntFormAndTermsList{})
@@ -1265,6 +1321,7 @@ func (ds *destructiveSearch) applyRules(fatherId uint64, state State, c Communic
 		WriteExchanges(fatherId, state, nil, Core.MakeEmptySubstAndForm(), "ApplyRules - SAT")
 		state.SetCurrentProofRule("Sat")
 		state.SetProof(append(state.GetProof(), state.GetCurrentProof()))
+		recordSaturatedBranch(state)
 		Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Nothing found, return sat" }))
 		ds.sendSubToFather(c, false, false, fatherId, state, []Core.SubstAndForm{}, currentNodeId, originalNodeId, []int{})
 	}
@@ -1272,6 +1329,7 @@ func (ds *destructiveSearch) applyRules(fatherId uint64, state State, c Communic
 
 func (ds *destructiveSearch) manageAlphaRules(fatherId uint64, state State, c Communication, originalNodeId int) {
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Alpha rule" }))
+	state.SetAlpha(selectFormula(AlphaKind, state.GetAlpha(), &state))
 	hdf := state.GetAlpha()[0]
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return fmt.Sprintf("Rule applied on : %s", hdf.ToString()) }))
 	state.SetAlpha(state.GetAlpha()[1:])
@@ -1289,6 +1347,7 @@ func (ds *destructiveSearch) manageAlphaRules(fatherId uint64, state State, c Co
 
 func (ds *destructiveSearch) manageDeltaRules(fatherId uint64, state State, c Communication, originalNodeId int) {
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Delta rule" }))
+	state.SetDelta(selectFormula(DeltaKind, state.GetDelta(), &state))
 	hdf := state.GetDelta()[0]
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return fmt.Sprintf("Rule applied on : %s", hdf.ToString()) }))
 	state.SetDelta(state.GetDelta()[1:])
@@ -1306,11 +1365,19 @@ func (ds *destructiveSearch) manageDeltaRules(fatherId uint64, state State, c Co
 
 func (ds *destructiveSearch) manageBetaRules(fatherId uint64, state State, c Communication, currentNodeId int, originalNodeId int, metaToReintroduce []int) {
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Beta rule" }))
+	state.SetBeta(selectFormula(BetaKind, state.GetBeta(), &state))
 	hdf := state.GetBeta()[0]
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return fmt.Sprintf("Rule applied on : %s", hdf.ToString()) }))
 	reslf := ApplyBetaRules(hdf, &state)
 	childIds := []int{}
 
+	if state.isBetaRedundant(reslf) {
+		Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "A branch of the rule is already on the branch, skip it" }))
+		state.SetBeta(state.GetBeta()[1:])
+		ds.applyRules(fatherId, state, c, Core.MakeEmptyFormAndTermsList(), currentNodeId, originalNodeId, metaToReintroduce)
+		return
+	}
+
 	// Proof
 	state.SetCurrentProofFormula(hdf)
 
@@ -1331,9 +1398,9 @@ func (ds *destructiveSearch) manageBetaRules(fatherId uint64, state State, c Com
 		if Glob.IsDestructive() {
 			channelChild := Communication{make(chan bool), make(chan Result)}
 			channels = append(channels, channelChild)
-			go ds.ProofSearch(Glob.GetGID(), otherState, channelChild, Core.MakeEmptySubstAndForm(), fl.GetI(), fl.GetI(), []int{}, false)
+			ds.goProofSearch(Glob.GetGID(), otherState, channelChild, Core.MakeEmptySubstAndForm(), fl.GetI(), fl.GetI(), []int{}, false)
 		} else {
-			go ds.ProofSearch(Glob.GetGID(), otherState, c, Core.MakeEmptySubstAndForm(), fl.GetI(), fl.GetI(), []int{}, false)
+			ds.goProofSearch(Glob.GetGID(), otherState, c, Core.MakeEmptySubstAndForm(), fl.GetI(), fl.GetI(), []int{}, false)
 		}
 
 		Glob.IncrGoRoutine(1)
@@ -1346,6 +1413,7 @@ func (ds *destructiveSearch) manageBetaRules(fatherId uint64, state State, c Com
 
 func (ds *destructiveSearch) manageGammaRules(fatherId uint64, state State, c Communication, originalNodeId int) {
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Gamma rule" }))
+	state.SetGamma(selectFormula(GammaKind, state.GetGamma(), &state))
 	hdf := state.GetGamma()[0]
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return fmt.Sprintf("Rule applied on : %s", hdf.ToString()) }))
 	state.SetGamma(state.GetGamma()[1:])
@@ -1376,7 +1444,7 @@ func (ds *destructiveSearch) manageGammaRules(fatherId uint64, state State, c Co
 
 func (ds *destructiveSearch) manageReintroductionRules(fatherId uint64, state State, c Communication, originalNodeId int, metaToReintroduce []int, newAtomics Core.FormAndTermsList, currentNodeId int, reintroduceAnyway bool) {
 
-	currentMTR := -1
+	currentMTR := reintroductionPolicy.Choose(state.meta_generator)
 
 	Glob.PrintDebug("PS", Lib.MkLazy(func() string { return "Reintroduction" }))
 	Glob.PrintDebug(
@@ -1410,7 +1478,22 @@ func (ds *destructiveSearch) manageReintroductionRules(fatherId uint64, state St
 }
 
 func (ds *destructiveSearch) manageResult(c Communication) (Core.Unifier, []ProofStruct, bool) {
-	result := <-c.getResult()
+	var result Result
+	done := make(chan struct{})
+	defer close(done)
+
+	select {
+	case result = <-c.getResult():
+	case <-timeLimit():
+		cancelSearch(c)
+		return Core.MakeUnifier(), []ProofStruct{}, false
+	case resource := <-resourceLimit(done):
+		cancelSearchForResources(c, resource)
+		return Core.MakeUnifier(), []ProofStruct{}, false
+	case <-interruption:
+		cancelSearch(c)
+		return Core.MakeUnifier(), []ProofStruct{}, false
+	}
 
 	Glob.PrintDebug(
 		"MAIN",
@@ -11,22 +11,67 @@ import (
 
 type incrementalSearch struct{}
 
+// The proof found by the last search.
+var finalProof []Search.ProofStruct
+
+// Closed when the search is cancelled, so that the nodes stop searching.
+var stopped <-chan struct{}
+
+func isStopped() bool {
+	select {
+	case <-stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasEquality(formula AST.Form) bool {
+	for _, f := range formula.GetSubFormulasRecur().Slice() {
+		if pred, isPred := f.(AST.Pred); isPred && pred.GetID().Equals(AST.Id_eq) {
+			return true
+		}
+	}
+	return false
+}
+
 func NewIncrementalSearch() Search.SearchAlgorithm {
 	return &incrementalSearch{}
 }
 
 func (is *incrementalSearch) Search(formula AST.Form, bound int) bool {
 	resetSearchTree(formula)
+	stop := make(chan struct{})
+	stopped = stop
 	go allDoParallelAlgo(doSearch, rootSearchNode)
-	resultFound := is.handleSearchResults()
 
-	return resultFound
+	results := make(chan bool)
+	go func() { results <- is.handleSearchResults() }()
+	Glob.IncrGoRoutine(1)
+
+	res, done := Search.AwaitSearch(results, stop)
+	switch {
+	case Search.IsTimedOut():
+		Search.PrintNoResult(Search.TimeoutStatus)
+	case Search.IsResourceOut():
+		Search.PrintNoResult(Search.ResourceOutStatus)
+	case done && res:
+		Search.PrintSearchResult(true)
+		Search.PrintProof(finalProof, Lib.EmptySet[AST.Meta]())
+	case hasEquality(formula) || Glob.IsLoaded("dmt") || Glob.GetArithModule():
+		// The open branch is only a model without equality, rewrite rules and
+		// arithmetic, that the incremental search does not reason with.
+		Search.PrintNoResult(Search.GaveUpStatus)
+	default:
+		Search.PrintSearchResult(false)
+	}
+
+	return done && res
 }
 
 func (is *incrementalSearch) handleSearchResults() bool {
-	resultFound, finalProof := rootSearchNode.getResult()
-
-	Search.PrintProof(finalProof, Lib.EmptySet[AST.Meta]())
+	resultFound, proof := rootSearchNode.getResult()
+	finalProof = proof
 
 	return resultFound
 }
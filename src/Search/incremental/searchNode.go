@@ -37,6 +37,7 @@ func getNewSearchNodeId() int {
 
 func resetSearchTree(formula AST.Form) {
 	searchNodeIdCounter = 0
+	treeImpossible = false
 	newRootSearchNode(formula)
 }
 
@@ -95,6 +96,10 @@ func allDoParallelAlgo(algo func(*SearchNode), nodes ...*SearchNode) {
 func doSearch(node *SearchNode) {
 	defer searchNodeCounter.Decrement()
 
+	if isStopped() {
+		return
+	}
+
 	if node.closureManager.shouldDoSearch() {
 		node.search()
 	} else {
@@ -105,6 +110,10 @@ func doSearch(node *SearchNode) {
 func doKeepGoing(node *SearchNode) {
 	defer searchNodeCounter.Decrement()
 
+	if isStopped() {
+		return
+	}
+
 	if node.closureManager.shouldKeepGoing() {
 		node.nonClosureSearch()
 	} else {
@@ -0,0 +1,337 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file removes from a proof the steps that no closure depends on: the
+* expansions whose results are never used, the branchings of which one branch
+* closes without the formulas it introduces, and the axioms that are not used.
+**/
+
+package Search
+
+import (
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+)
+
+// An axiom of the problem, with the name of its TPTP statement.
+type Axiom struct {
+	Name string
+	Form AST.Form
+}
+
+// The axioms of the problem being proven, in the order of the problem.
+var axioms []Axiom
+
+// Forgets the axioms registered for the previous problem.
+func ClearAxioms() {
+	axioms = nil
+}
+
+// Registers an axiom of the problem, as it appears in the formula to refute
+// (or in the rewrite rules with DMT).
+func AddAxiom(name string, form AST.Form) {
+	axioms = append(axioms, Axiom{name, form})
+}
+
+type minimizer struct {
+	// Every formula that a remaining step depends on.
+	used *AST.FormList
+}
+
+// Returns the proof without the steps and the axioms that no closure depends
+// on, along with the axioms of the problem that it uses. The proof is returned
+// unchanged, and all the axioms are deemed used, when the formulas it depends
+// on cannot be traced back to its root.
+func MinimizeProof(proof []ProofStruct) ([]ProofStruct, []Axiom) {
+	if len(proof) == 0 {
+		return proof, []Axiom{}
+	}
+
+	m := minimizer{used: AST.NewFormList()}
+	root := proof[0].GetFormula().GetForm()
+	if !isRootConjunction(proof) {
+		steps, needed := m.minimizeBranch(proof, AST.NewFormList(root))
+		if !isSubsetOf(needed, AST.NewFormList(root)) {
+			return proof, axioms
+		}
+		return steps, m.usedAxioms()
+	}
+
+	conjuncts := proof[0].Result_formulas[0].GetForms()
+	steps, needed := m.minimizeBranch(proof[1:], conjuncts)
+	if !isSubsetOf(needed, conjuncts) {
+		return proof, axioms
+	}
+
+	// The last conjunct is kept as the outputs expect the negation of the
+	// conjecture there.
+	kept := Core.FormAndTermsList{}
+	for i, fat := range proof[0].Result_formulas[0].GetFL() {
+		if needed.Contains(fat.GetForm()) || i == conjuncts.Len()-1 {
+			kept = append(kept, fat)
+		}
+	}
+
+	switch {
+	case len(kept) == conjuncts.Len():
+		return append([]ProofStruct{proof[0]}, steps...), m.usedAxioms()
+	case len(kept) == 1 && len(steps) > 0 && steps[0].GetFormula().GetForm().Equals(kept[0].GetForm()):
+		return steps, m.usedAxioms()
+	}
+
+	first := proof[0].Copy()
+	first.SetFormulaProof(Core.MakeFormAndTerm(AST.MakerAnd(kept.ExtractForms()), first.GetFormula().GetTerms()))
+	first.SetResultFormulasProof([]IntFormAndTermsList{MakeIntFormAndTermsList(first.Result_formulas[0].GetI(), kept)})
+	return append([]ProofStruct{first}, steps...), m.usedAxioms()
+}
+
+// Whether the proof starts by splitting the conjunction of the axioms and of
+// the negated conjecture.
+func isRootConjunction(proof []ProofStruct) bool {
+	_, isAnd := proof[0].GetFormula().GetForm().(AST.And)
+	return isAnd && len(proof) > 1 &&
+		len(proof[0].GetChildren()) == 0 &&
+		len(proof[0].GetResultFormulas()) == 1
+}
+
+// Minimizes a branch whose first step applies on the given formulas. Returns
+// the remaining steps and the formulas of the branch that they depend on.
+func (m *minimizer) minimizeBranch(proof []ProofStruct, branch *AST.FormList) ([]ProofStruct, *AST.FormList) {
+	if len(proof) == 0 {
+		return proof, m.use(branch.Copy())
+	}
+
+	available := branch.Copy()
+	for _, step := range proof[:len(proof)-1] {
+		available.Append(GetFormulasFromIntFormAndTermList(step.GetResultFormulas()).Slice()...)
+	}
+
+	last := proof[len(proof)-1]
+	var steps []ProofStruct
+	var needed *AST.FormList
+
+	switch {
+	case len(last.GetChildren()) > 0:
+		steps, needed = m.minimizeBranching(last, available)
+	case last.GetRuleName() == "CLOSURE", last.GetRuleName() == "ARITHMETIC_CLOSURE":
+		steps, needed = []ProofStruct{last}, m.use(closureNeeds(last.GetFormula().GetForm(), available))
+	default:
+		// The branch is not closed, so nothing is known to be unused.
+		m.use(available)
+		return proof, branch.Copy()
+	}
+
+	for i := len(proof) - 2; i >= 0; i-- {
+		step := proof[i]
+		results := GetFormulasFromIntFormAndTermList(step.GetResultFormulas())
+		if !isStepNeeded(step, results, needed) {
+			continue
+		}
+
+		needed = difference(needed, results)
+		needed.AppendIfNotContains(step.GetFormula().GetForm())
+		m.used.AppendIfNotContains(step.GetFormula().GetForm())
+		steps = append([]ProofStruct{step}, steps...)
+	}
+
+	return steps, needed
+}
+
+// A branching step is replaced by the proof of one of its children when this
+// child does not depend on the formulas introduced by the step.
+func (m *minimizer) minimizeBranching(step ProofStruct, available *AST.FormList) ([]ProofStruct, *AST.FormList) {
+	children := [][]ProofStruct{}
+	needed := AST.NewFormList()
+	used := AST.NewFormList(step.GetFormula().GetForm())
+
+	for i, child := range step.GetChildren() {
+		results := step.Result_formulas[i].GetForms()
+		childAvailable := available.Copy()
+		childAvailable.Append(results.Slice()...)
+
+		// The formulas used by the other children are not used anymore when
+		// this child replaces the step.
+		cm := minimizer{used: AST.NewFormList()}
+		childSteps, childNeeded := cm.minimizeBranch(child, childAvailable)
+		if !intersects(childNeeded, results) {
+			m.use(cm.used)
+			return childSteps, childNeeded
+		}
+
+		children = append(children, childSteps)
+		needed.AppendIfNotContains(difference(childNeeded, results).Slice()...)
+		used.AppendIfNotContains(cm.used.Slice()...)
+	}
+
+	minimized := step.Copy()
+	minimized.SetChildrenProof(children)
+	needed.AppendIfNotContains(step.GetFormula().GetForm())
+	m.use(used)
+	return []ProofStruct{minimized}, needed
+}
+
+func (m *minimizer) use(forms *AST.FormList) *AST.FormList {
+	m.used.AppendIfNotContains(forms.Slice()...)
+	return forms
+}
+
+// The axioms that the proof depends on. An axiom split at the root is used
+// when one of its parts is. With DMT, the rewrite rules are always used.
+func (m *minimizer) usedAxioms() []Axiom {
+	used := []Axiom{}
+	for _, axiom := range axioms {
+		if m.isUsed(axiom.Form) || (Glob.IsLoaded("dmt") && dmt.GetRegisteredAxioms().Contains(axiom.Form)) {
+			used = append(used, axiom)
+		}
+	}
+	return used
+}
+
+func (m *minimizer) isUsed(form AST.Form) bool {
+	if m.used.Contains(form) {
+		return true
+	}
+
+	if and, isAnd := form.(AST.And); isAnd {
+		for _, f := range and.FormList.Slice() {
+			if m.isUsed(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// A step is needed when one of its results is, or when it is a delta-rule
+// whose new symbols appear in the formulas that are needed.
+func isStepNeeded(step ProofStruct, results, needed *AST.FormList) bool {
+	if intersects(needed, results) {
+		return true
+	}
+
+	if !strings.HasPrefix(step.GetRuleName(), "DELTA") {
+		return false
+	}
+
+	symbols := functionSymbols(AST.NewFormList(step.GetFormula().GetForm()))
+	neededSymbols := functionSymbols(needed)
+	for symbol := range functionSymbols(results) {
+		if !symbols[symbol] && neededSymbols[symbol] {
+			return true
+		}
+	}
+	return false
+}
+
+// The formulas of the branch that a closure depends on. When the closure is
+// not syntactic, it may come from equality or arithmetic reasoning, so all the
+// literals of the branch are kept.
+func closureNeeds(form AST.Form, available *AST.FormList) *AST.FormList {
+	needed := AST.NewFormList(form)
+
+	var complement AST.Form = AST.MakerNot(form)
+	switch nf := form.(type) {
+	case AST.Bot:
+		return needed
+	case AST.Not:
+		switch inner := nf.GetForm().(type) {
+		case AST.Top:
+			return needed
+		case AST.Pred:
+			args := inner.GetArgs().GetSlice()
+			if inner.GetID().Equals(AST.Id_eq) && len(args) == 2 && args[0].Equals(args[1]) {
+				return needed
+			}
+		}
+		complement = nf.GetForm()
+	}
+
+	if available.Contains(complement) {
+		needed.AppendIfNotContains(complement)
+		return needed
+	}
+
+	for _, f := range available.Slice() {
+		if isLiteral(f) {
+			needed.AppendIfNotContains(f)
+		}
+	}
+	return needed
+}
+
+func isLiteral(f AST.Form) bool {
+	if not, isNot := f.(AST.Not); isNot {
+		f = not.GetForm()
+	}
+	_, isPred := f.(AST.Pred)
+	return isPred
+}
+
+func functionSymbols(forms *AST.FormList) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, f := range forms.Slice() {
+		for _, term := range f.GetSubTerms().GetSlice() {
+			if fun, isFun := term.(AST.Fun); isFun {
+				symbols[fun.GetName()] = true
+			}
+		}
+	}
+	return symbols
+}
+
+func intersects(fl1, fl2 *AST.FormList) bool {
+	for _, f := range fl2.Slice() {
+		if fl1.Contains(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSubsetOf(fl1, fl2 *AST.FormList) bool {
+	return fl2.ContainsAll(fl1.Slice()...)
+}
+
+func difference(fl1, fl2 *AST.FormList) *AST.FormList {
+	res := AST.NewFormList()
+	for _, f := range fl1.Slice() {
+		if !fl2.Contains(f) {
+			res.Append(f)
+		}
+	}
+	return res
+}
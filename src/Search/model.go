@@ -0,0 +1,283 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file builds a Herbrand model from an open saturated branch, printed when
+* the search ends without proof in completeness mode.
+**/
+
+package Search
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+var saturatedBranch struct {
+	atomics Lib.Option[Core.FormAndTermsList]
+	lock    sync.Mutex
+}
+
+// The formula given to the search, used to retrieve the symbols of the problem.
+var searchedFormula AST.Form
+
+func resetSaturatedBranch() {
+	saturatedBranch.lock.Lock()
+	saturatedBranch.atomics = Lib.MkNone[Core.FormAndTermsList]()
+	saturatedBranch.lock.Unlock()
+}
+
+// Keeps the atomic formulas of the first open branch found that is saturated,
+// i.e., that has no formula left to reintroduce. Such a branch is ground, and
+// its atoms give a model of the problem, unless it needs equality reasoning or
+// rewrite rules have been taken out of the problem. A branch that still has
+// universal formulas (metagen) is not saturated, even when they cannot be
+// reintroduced anymore: no model is built from it, and the search goes on.
+func recordSaturatedBranch(state State) {
+	if !Glob.GetCompleteness() || len(state.GetMetaGen()) > 0 || Glob.IsLoaded("dmt") {
+		return
+	}
+
+	for _, f := range state.GetAtomic() {
+		// Terms are interpreted by themselves: a positive equality between
+		// two different terms does not hold in a Herbrand model.
+		if pred, isPred := f.GetForm().(AST.Pred); isPred && pred.GetID().Equals(AST.Id_eq) &&
+			!pred.GetArgs().At(0).Equals(pred.GetArgs().At(1)) {
+			return
+		}
+	}
+
+	saturatedBranch.lock.Lock()
+	defer saturatedBranch.lock.Unlock()
+	if _, isNone := saturatedBranch.atomics.(Lib.None[Core.FormAndTermsList]); isNone {
+		saturatedBranch.atomics = Lib.MkSome(state.GetAtomic().Copy())
+	}
+}
+
+// Tells whether a saturated open branch has been found: the problem then has
+// a model, and the search can stop.
+func hasSaturatedBranch() bool {
+	saturatedBranch.lock.Lock()
+	defer saturatedBranch.lock.Unlock()
+	_, found := saturatedBranch.atomics.(Lib.Some[Core.FormAndTermsList])
+	return found
+}
+
+func printModel() {
+	model := GetModel()
+	if model == "" {
+		Glob.PrintInfo("MODEL", "No saturated branch has been found")
+		return
+	}
+	fmt.Print(model)
+}
+
+// Returns the Herbrand model of the saturated branch in the TPTP interpretation
+// format, between the SZS output markers, or an empty string if no saturated
+// branch has been found: every term denotes itself, so that the constants are
+// distinct, and the atoms of the branch are the only true ones. The model is
+// finite if there are no function symbols.
+func GetModel() string {
+	saturatedBranch.lock.Lock()
+	atomics, found := saturatedBranch.atomics.(Lib.Some[Core.FormAndTermsList])
+	saturatedBranch.lock.Unlock()
+
+	if !found {
+		return ""
+	}
+
+	positive := map[string][][]AST.Term{}
+	predicates := map[string]int{}
+	terms := Lib.NewList[AST.Term]()
+
+	for _, f := range atomics.Val {
+		form, isPositive := f.GetForm(), true
+		if not, isNot := form.(AST.Not); isNot {
+			form, isPositive = not.GetForm(), false
+		}
+
+		pred, isPred := form.(AST.Pred)
+		if !isPred {
+			continue
+		}
+		terms.Add(AST.TermEquals, pred.GetSubTerms().GetSlice()...)
+
+		if pred.GetID().Equals(AST.Id_eq) {
+			continue
+		}
+
+		name := pred.GetID().GetName()
+		predicates[name] = pred.GetArgs().Len()
+		if isPositive {
+			positive[name] = append(positive[name], pred.GetArgs().GetSlice())
+		}
+	}
+
+	if searchedFormula != nil {
+		terms.Add(AST.TermEquals, searchedFormula.GetSubTerms().GetSlice()...)
+	}
+
+	constants, isFinite := []string{}, true
+	for _, term := range terms.GetSlice() {
+		if fun, isFun := term.(AST.Fun); isFun {
+			if fun.GetArgs().Len() > 0 {
+				isFinite = false
+			} else if !slices.Contains(constants, modelSymbol(fun.GetName())) {
+				constants = append(constants, modelSymbol(fun.GetName()))
+			}
+		}
+	}
+	sort.Strings(constants)
+
+	kind := "Model"
+	if isFinite {
+		kind = "FiniteModel"
+	}
+
+	var model strings.Builder
+	fmt.Fprintf(&model, "%v SZS output start %s for %v\n", "%", kind, Glob.GetProblemName())
+	if isFinite && len(constants) > 0 {
+		equalities := []string{}
+		for _, constant := range constants {
+			equalities = append(equalities, "X = "+constant)
+		}
+		fmt.Fprintf(&model, "fof(interpretation_domain, fi_domain, ! [X] : (%s)).\n", strings.Join(equalities, " | "))
+		// The constants are pairwise distinct, as each of them denotes itself.
+		if len(constants) > 1 {
+			disequalities := []string{}
+			for i := range constants {
+				for _, other := range constants[i+1:] {
+					disequalities = append(disequalities, constants[i]+" != "+other)
+				}
+			}
+			fmt.Fprintf(&model, "fof(interpretation_distinct, fi_domain, (%s)).\n", strings.Join(disequalities, " & "))
+		}
+	} else if !isFinite {
+		fmt.Fprintf(&model, "%% The domain is the Herbrand universe: every term denotes itself.\n")
+	}
+	fmt.Fprintf(&model, "fof(interpretation_atoms, fi_predicates, %s).\n", predicatesInterpretation(predicates, positive))
+	fmt.Fprintf(&model, "%v SZS output end %s for %v\n", "%", kind, Glob.GetProblemName())
+	return model.String()
+}
+
+// Each predicate holds exactly on the arguments of its positive atoms.
+func predicatesInterpretation(predicates map[string]int, positive map[string][][]AST.Term) string {
+	names := []string{}
+	for name := range predicates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	definitions := []string{}
+	for _, name := range names {
+		arity := predicates[name]
+		if arity == 0 {
+			if len(positive[name]) > 0 {
+				definitions = append(definitions, modelSymbol(name))
+			} else {
+				definitions = append(definitions, "~ "+modelSymbol(name))
+			}
+			continue
+		}
+
+		vars := []string{}
+		for i := 1; i <= arity; i++ {
+			vars = append(vars, fmt.Sprintf("X%d", i))
+		}
+		atom := fmt.Sprintf("%s(%s)", modelSymbol(name), strings.Join(vars, ", "))
+
+		cases := []string{}
+		for _, args := range positive[name] {
+			equalities := []string{}
+			for i, arg := range args {
+				equalities = append(equalities, fmt.Sprintf("%s = %s", vars[i], modelTermToString(arg)))
+			}
+			if c := "(" + strings.Join(equalities, " & ") + ")"; !slices.Contains(cases, c) {
+				cases = append(cases, c)
+			}
+		}
+		sort.Strings(cases)
+
+		if len(cases) == 0 {
+			definitions = append(definitions, fmt.Sprintf("(! [%s] : ~ %s)", strings.Join(vars, ", "), atom))
+		} else {
+			interpretation := strings.Join(cases, " | ")
+			if len(cases) > 1 {
+				interpretation = "(" + interpretation + ")"
+			}
+			definitions = append(definitions, fmt.Sprintf("(! [%s] : (%s <=> %s))", strings.Join(vars, ", "), atom, interpretation))
+		}
+	}
+
+	if len(definitions) == 0 {
+		return "$true"
+	}
+	return "(" + strings.Join(definitions, " & ") + ")"
+}
+
+func modelTermToString(term AST.Term) string {
+	if fun, isFun := term.(AST.Fun); isFun && fun.GetArgs().Len() > 0 {
+		args := []string{}
+		for _, arg := range fun.GetArgs().GetSlice() {
+			args = append(args, modelTermToString(arg))
+		}
+		return fmt.Sprintf("%s(%s)", modelSymbol(fun.GetName()), strings.Join(args, ", "))
+	}
+	return modelSymbol(term.GetName())
+}
+
+// Symbols introduced by the prover, such as Skolem symbols, are not TPTP words
+// and are quoted.
+func modelSymbol(name string) string {
+	isWord := name != "" && name[0] >= 'a' && name[0] <= 'z'
+	for _, c := range name {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			isWord = false
+		}
+	}
+	if isWord || strings.HasPrefix(name, "$") || strings.HasPrefix(name, "'") || strings.HasPrefix(name, "\"") {
+		return name
+	}
+	if _, err := strconv.ParseFloat(strings.Replace(name, "/", "", 1), 64); err == nil {
+		return name
+	}
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(name, "\\", "\\\\"), "'", "\\'") + "'"
+}
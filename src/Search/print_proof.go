@@ -32,21 +32,26 @@
 package Search
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/GoelandProver/Goeland/AST"
 	"github.com/GoelandProver/Goeland/Glob"
 	"github.com/GoelandProver/Goeland/Lib"
 )
 
-var BasicOutputProofStruct = &OutputProofStruct{ProofOutput: ProofStructListToText, Name: "Basic", Extension: ".proof"}
+var BasicOutputProofStruct = &OutputProofStruct{ProofOutput: ProofStructListToText, Name: "Basic", Extension: ".proof", Arithmetic: true}
 
 type OutputProofStruct struct {
 	ProofOutput func(finalProof []ProofStruct, metaList Lib.List[AST.Meta]) string
 	Name        string
 	Extension   string
+	// Whether the output can justify the closures of the arithmetic module.
+	Arithmetic bool
 }
 
 var outputProofStructs []*OutputProofStruct
@@ -55,20 +60,125 @@ func AddPrintProofAlgorithm(ps *OutputProofStruct) {
 	outputProofStructs = append(outputProofStructs, ps)
 }
 
+var proofCheckers []func(problem AST.Form, finalProof []ProofStruct) error
+
+// Registers a checker of the proofs that are found, given the problem of the
+// search. A proof that the checker rejects is a fatal error.
+func AddProofChecker(checker func(problem AST.Form, finalProof []ProofStruct) error) {
+	proofCheckers = append(proofCheckers, checker)
+}
+
 func PrintProof(final_proof []ProofStruct, metaList Lib.Set[AST.Meta]) {
-	if !Glob.GetProof() {
+	finalProof = final_proof
+	if Glob.GetMinimizeProof() || Glob.GetCoreAxioms() {
+		final_proof = minimizeAndPrintUsedAxioms(final_proof)
+	}
+	checkProof(final_proof)
+
+	// The proof may only be computed for the answers, without being output.
+	if !Glob.GetProof() || !Glob.GetPrintResults() || len(outputProofStructs) == 0 {
 		return
 	}
 
 	fmt.Printf("%v SZS output start Proof for %v\n", "%", Glob.GetProblemName())
 
+	arithmetic := hasArithmeticClosure(final_proof)
 	for _, ps := range outputProofStructs {
+		if arithmetic && !ps.Arithmetic {
+			Glob.PrintError("PRF", fmt.Sprintf("The %s output cannot justify the arithmetic closures, no certificate is output", ps.Name))
+			fmt.Printf("%v The %s certificate is not output, as the proof uses arithmetic reasoning\n", "%", ps.Name)
+			continue
+		}
 		ps.printProofWithProofStruct(final_proof, metaList)
 	}
 
 	fmt.Printf("%v SZS output end Proof for %v\n", "%", Glob.GetProblemName())
 }
 
+// Whether a branch of the proof is closed by the arithmetic module.
+func hasArithmeticClosure(proof []ProofStruct) bool {
+	for _, step := range proof {
+		if step.GetRuleName() == "ARITHMETIC_CLOSURE" {
+			return true
+		}
+		for _, child := range step.GetChildren() {
+			if hasArithmeticClosure(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Prints the axioms that the proof uses, as a comment with -minimize and as
+// a list of names with -core_axioms. Returns the minimized proof with
+// -minimize, and the proof itself otherwise.
+func minimizeAndPrintUsedAxioms(finalProof []ProofStruct) []ProofStruct {
+	minimized, axioms := MinimizeProof(finalProof)
+	if !Glob.GetMinimizeProof() {
+		minimized = finalProof
+	}
+
+	if !Glob.GetPrintResults() {
+		return minimized
+	}
+
+	// The definitions introduced at parsing bear the name of their statement.
+	names := []string{}
+	for _, axiom := range axioms {
+		if !slices.Contains(names, axiom.Name) {
+			names = append(names, axiom.Name)
+		}
+	}
+
+	if Glob.GetMinimizeProof() {
+		fmt.Printf("%v Used axioms: %v\n", "%", strings.Join(names, ", "))
+	}
+
+	if Glob.GetCoreAxioms() {
+		fmt.Printf("%v SZS output start CoreAxioms for %v\n", "%", Glob.GetProblemName())
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		fmt.Printf("%v SZS output end CoreAxioms for %v\n", "%", Glob.GetProblemName())
+	}
+
+	return minimized
+}
+
+// Implemented by the errors of the proof checkers that cannot tell whether a
+// step is valid. Such a step does not reject the proof, but is reported.
+type uncheckedError interface {
+	Unchecked() bool
+}
+
+func checkProof(finalProof []ProofStruct) {
+	if len(proofCheckers) == 0 {
+		return
+	}
+
+	uncheckedSteps := []string{}
+	for _, check := range proofCheckers {
+		err := check(searchedFormula, finalProof)
+		var unchecked uncheckedError
+		switch {
+		case errors.As(err, &unchecked) && unchecked.Unchecked():
+			Glob.PrintWarn("Check", fmt.Sprintf("The proof cannot be fully checked, %v", err))
+			uncheckedSteps = append(uncheckedSteps, err.Error())
+		case err != nil:
+			Glob.Fatal("Check", fmt.Sprintf("The proof is not valid, %v", err))
+		}
+	}
+
+	if Glob.GetPrintResults() {
+		if len(uncheckedSteps) > 0 {
+			fmt.Printf("%v The proof has been checked, except for %s\n", "%", strings.Join(uncheckedSteps, ", "))
+		} else {
+			fmt.Printf("%v The proof has been checked\n", "%")
+		}
+	}
+}
+
 func (ps *OutputProofStruct) printProofWithProofStruct(finalProof []ProofStruct, metaList Lib.Set[AST.Meta]) {
 	output := ps.ProofOutput(finalProof, metaList.Elements())
 
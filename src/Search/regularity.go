@@ -0,0 +1,138 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the regularity check of the destructive search
+* (-regularity): a formula is never added twice to a branch, up to the
+* substitutions applied on the branch. The formulas of a branch, including the
+* ones already expanded, are kept in a hash table.
+**/
+
+package Search
+
+import (
+	"slices"
+
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+// The formulas added to a branch, by hash. The gamma formulas are left out, as
+// they are added again when they are reintroduced. The hash only depends on the
+// formula (see Core.FormAndTerms.Hash): the formulas that only differ by their
+// terms are in the same list.
+type branchForms map[uint64]Core.FormAndTermsList
+
+func (bf branchForms) contains(f Core.FormAndTerms) bool {
+	return bf[f.Hash()].Contains(f)
+}
+
+func (bf branchForms) add(f Core.FormAndTerms) {
+	if Core.ShowKindOfRule(f.GetForm()) == Core.Gamma || bf.contains(f) {
+		return
+	}
+	bf[f.Hash()] = append(bf[f.Hash()], f)
+}
+
+func (bf branchForms) containsAll(fl Core.FormAndTermsList) bool {
+	for _, f := range fl {
+		if !bf.contains(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// The lists are copied as well: add appends to them, and the branches using the
+// copies are expanded concurrently.
+func (bf branchForms) copy() branchForms {
+	res := branchForms{}
+	for h, fl := range bf {
+		res[h] = slices.Clone(fl)
+	}
+	return res
+}
+
+// Returns the formulas of the branch once the substitution is applied: the ones
+// that become equal are only kept once.
+func (bf branchForms) substitute(s Unif.Substitutions) branchForms {
+	res := branchForms{}
+	for _, fl := range bf {
+		for _, f := range fl {
+			res.add(Core.ApplySubstitutionsOnFormAndTerms(s, f))
+		}
+	}
+	return res
+}
+
+// Removes the formulas that occur twice in a list, after a substitution has
+// been applied on it.
+func removeDuplicates(fl Core.FormAndTermsList) Core.FormAndTermsList {
+	seen := branchForms{}
+	res := Core.MakeEmptyFormAndTermsList()
+	for _, f := range fl {
+		if !seen.contains(f) {
+			seen[f.Hash()] = append(seen[f.Hash()], f)
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Whether a formula is already on the branch.
+func (st State) isOnBranch(f Core.FormAndTerms) bool {
+	return Glob.GetRegularity() && st.branch_forms.contains(f)
+}
+
+// Gives a search step its own formulas of the branch, as the state it is given
+// may be used again by its father.
+func (st *State) ownBranchForms() {
+	if Glob.GetRegularity() {
+		st.branch_forms = st.branch_forms.copy()
+	}
+}
+
+// Whether a beta rule can be skipped because the formulas of one of its
+// branches are already on the branch: that branch is the current one, and the
+// other ones are not needed to close it.
+func (st State) isBetaRedundant(branches []Core.FormAndTermsList) bool {
+	if !Glob.GetRegularity() {
+		return false
+	}
+	for _, fl := range branches {
+		if st.branch_forms.containsAll(fl) {
+			return true
+		}
+	}
+	return false
+}
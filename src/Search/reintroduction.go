@@ -0,0 +1,167 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides the policies choosing which formula the reintroduction rule
+* instantiates again once the gamma rules are exhausted. Each formula gets a
+* share of the bound of the branch (see -l) proportional to its weight: the
+* formula reintroduced is the one that has been instantiated the least times
+* relatively to its weight.
+**/
+
+package Search
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+type ReintroductionPolicy interface {
+	// Forgets what has been learnt during the previous search.
+	Reset()
+	// Learns from a substitution closing a branch whose formulas generating
+	// metavariables are the given ones.
+	RecordClosure(generators []Core.MetaGen, subst Unif.Substitutions)
+	// Returns the index of the formula to reintroduce among the generators,
+	// or -1 for the least reintroduced one.
+	Choose(generators []Core.MetaGen) int
+}
+
+var reintroductionPolicy ReintroductionPolicy = uniformPolicy{}
+
+func SetReintroductionPolicy(policy ReintroductionPolicy) {
+	reintroductionPolicy = policy
+}
+
+// Returns the policy of the given name, an error if there is none:
+//   - uniform: every formula has the same weight (the default),
+//   - depth: the formulas with deeper terms have less weight, as their instances
+//     are less likely to close a branch,
+//   - closures: the formulas have more weight as their metavariables take part
+//     in more closures.
+func MakeReintroductionPolicy(name string) (ReintroductionPolicy, error) {
+	switch name {
+	case "uniform", "":
+		return uniformPolicy{}, nil
+	case "depth":
+		return weightedPolicy{depthWeight}, nil
+	case "closures":
+		return &closuresPolicy{counts: make(map[int]int)}, nil
+	}
+	return nil, fmt.Errorf("unknown reintroduction policy %s (expected uniform, depth or closures)", name)
+}
+
+type uniformPolicy struct{}
+
+func (uniformPolicy) Reset()                                           {}
+func (uniformPolicy) RecordClosure([]Core.MetaGen, Unif.Substitutions) {}
+func (uniformPolicy) Choose([]Core.MetaGen) int                        { return -1 }
+
+type weightedPolicy struct {
+	weight func(Core.MetaGen) float64
+}
+
+func (weightedPolicy) Reset()                                           {}
+func (weightedPolicy) RecordClosure([]Core.MetaGen, Unif.Substitutions) {}
+
+func (p weightedPolicy) Choose(generators []Core.MetaGen) int {
+	return chooseWeighted(generators, p.weight)
+}
+
+// The formula instantiated the least times relatively to its weight, the first
+// one in case of a tie.
+func chooseWeighted(generators []Core.MetaGen, weight func(Core.MetaGen) float64) int {
+	chosen, least := -1, 0.
+	for i, generator := range generators {
+		share := float64(generator.GetCounter()) / weight(generator)
+		if chosen == -1 || share < least {
+			chosen, least = i, share
+		}
+	}
+	return chosen
+}
+
+func depthWeight(generator Core.MetaGen) float64 {
+	depth := 0
+	for _, term := range generator.GetForm().GetForm().GetSubTerms().GetSlice() {
+		depth = max(depth, termDepth(term))
+	}
+	return 1 / float64(1+depth)
+}
+
+func termDepth(term AST.Term) int {
+	fun, isFun := term.(AST.Fun)
+	if !isFun {
+		return 0
+	}
+
+	depth := 0
+	for _, arg := range fun.GetArgs().GetSlice() {
+		depth = max(depth, termDepth(arg))
+	}
+	return depth + 1
+}
+
+// The number of closures in which the metavariables of each formula (given by
+// its index) took part, shared by all the branches.
+type closuresPolicy struct {
+	mutex  sync.Mutex
+	counts map[int]int
+}
+
+func (p *closuresPolicy) Reset() {
+	p.mutex.Lock()
+	p.counts = make(map[int]int)
+	p.mutex.Unlock()
+}
+
+func (p *closuresPolicy) RecordClosure(generators []Core.MetaGen, subst Unif.Substitutions) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, i := range retrieveMetaFromSubst(subst) {
+		if i >= 0 && i < len(generators) {
+			p.counts[generators[i].GetForm().GetForm().GetIndex()]++
+		}
+	}
+}
+
+func (p *closuresPolicy) Choose(generators []Core.MetaGen) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return chooseWeighted(generators, func(generator Core.MetaGen) float64 {
+		return float64(1 + p.counts[generator.GetForm().GetForm().GetIndex()])
+	})
+}
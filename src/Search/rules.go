@@ -73,7 +73,7 @@ var strToPrintMap map[string]string = map[string]string{
 func ApplyClosureRules(form AST.Form, state *State) (result bool, substitutions []Unif.Substitutions) {
 	Glob.PrintDebug("ACR", Lib.MkLazy(func() string { return "Start ACR" }))
 
-	if searchObviousClosureRule(form) {
+	if searchObviousClosureRule(form) || TryArithmeticClosure(form, state) {
 		return true, substitutions
 	}
 
@@ -134,6 +134,19 @@ func searchForbidden(state *State, s Unif.MatchingSubstitutions) bool {
 	return foundForbidden
 }
 
+// Closure rule of the arithmetic module (see Mods/arith). It closes the branch
+// without any substitution.
+var TryArithmeticClosure = func(form AST.Form, state *State) bool {
+	return false
+}
+
+// Whether a branch closed without substitution has been closed by the
+// arithmetic module: the proof records it with its own rule, as the literals
+// of the branch are not complementary.
+func isArithmeticClosure(form AST.Form, state *State) bool {
+	return !searchObviousClosureRule(form) && TryArithmeticClosure(form, state)
+}
+
 /* Search obvious closure rule like ⊥ and ¬⊤ */
 func searchObviousClosureRule(f AST.Form) bool {
 	switch nf := f.(type) {
@@ -38,6 +38,10 @@ package Search
 
 import (
 	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
 
 	"github.com/GoelandProver/Goeland/AST"
 	"github.com/GoelandProver/Goeland/Core"
@@ -56,6 +60,48 @@ var UsedSearch SearchAlgorithm
 
 var EagerEq = false
 
+// SZS statuses of a search that did not reach a result.
+const (
+	TimeoutStatus     = "Timeout"
+	GaveUpStatus      = "GaveUp"
+	ResourceOutStatus = "ResourceOut"
+)
+
+// Exit codes of the prover. Errors exit with code 1 (see Glob.Fatal).
+const (
+	ExitSolved      = 0
+	ExitGaveUp      = 2
+	ExitResourceOut = 3
+	ExitTimeout     = 124
+)
+
+// How often the resources used by the search are compared to their limits.
+const resourceCheckInterval = 10 * time.Millisecond
+
+// The memory compared to -max_memory: the bytes taken by the objects of the
+// heap, live or not yet collected.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+var exitCode = ExitSolved
+var timedOut = false
+var resourceOut = false
+
+// Outcome of the last search: its SZS status and, if it found one, its proof.
+var status = ""
+var finalProof []ProofStruct
+
+// Closed by the caller of the search to stop it, see SetInterruption.
+var interruption <-chan struct{}
+
+// The goroutines of the destructive search. Search waits for them before
+// returning: once the search is cancelled, they are still closing themselves
+// and use the global state that the next search resets.
+var searchGoroutines sync.WaitGroup
+
+// Closed when a step of the search returns, so that its goroutines stop instead
+// of waiting for the orders and the answers of the other ones.
+var stopped = make(chan struct{})
+
 func init() {
 	SetSearchAlgorithm(NewDestructiveSearch())
 }
@@ -76,40 +122,245 @@ func Search(formula AST.Form, bound int) {
 		Lib.MkLazy(func() string { return fmt.Sprintf("Initial formula: %v", formula.ToString()) }),
 	)
 
-	UsedSearch.Search(formula, bound)
+	searchedFormula = formula
+	resetResult()
+	reintroductionPolicy.Reset()
+	res := UsedSearch.Search(formula, bound)
+	stopSearchGoroutines()
+
+	// Some search algorithms do not print their result.
+	if status == "" {
+		status = szsStatus(res)
+	}
+}
+
+// Stops the goroutines of the destructive search that are still running and
+// waits for them, so that the next step of the search, which resets the global
+// state, starts once they are done.
+func stopSearchGoroutines() {
+	close(stopped)
+	searchGoroutines.Wait()
+	stopped = make(chan struct{})
+}
+
+func resetResult() {
+	exitCode = ExitSolved
+	timedOut = false
+	resourceOut = false
+	status = ""
+	finalProof = nil
+	answers = nil
+	resetSaturatedBranch()
+}
+
+// Returns the SZS status of the last search.
+func GetStatus() string {
+	return status
+}
+
+// Returns the proof found by the last search, nil if there is none.
+func GetFinalProof() []ProofStruct {
+	return finalProof
+}
+
+// Stops the search when the channel is closed, as if the time limit had been
+// reached. A nil channel never stops it.
+func SetInterruption(ch <-chan struct{}) {
+	interruption = ch
+}
+
+func szsStatus(res bool) string {
+	switch {
+	case !res && Glob.ArePremisesDropped():
+		// The axioms dropped by the premise selection have not been tried.
+		return GaveUpStatus
+	case res && Glob.IsConjectureFound():
+		return "Theorem"
+	case res:
+		return "Unsatisfiable"
+	case Glob.IsConjectureFound():
+		return "CounterSatisfiable"
+	default:
+		return "Satisfiable"
+	}
 }
 
 func PrintSearchResult(res bool) {
+	if szsStatus(res) == GaveUpStatus {
+		PrintNoResult(GaveUpStatus)
+		return
+	}
+
 	Glob.PrintInfo("Res", fmt.Sprintf("%v goroutines created", Glob.GetNbGoroutines()))
 	Glob.PrintInfo("Res", "==== Result ====")
 
-	validity := ""
-	status := ""
-
+	validity := "NOT VALID"
 	if res {
 		validity = "VALID"
+	}
+	status = szsStatus(res)
+
+	Glob.PrintInfo("MAIN", fmt.Sprintf("%v RES : %v", "%", validity))
+	printStandardSolution(status)
 
-		if Glob.IsConjectureFound() {
-			status = "Theorem"
-		} else {
-			status = "Unsatisfiable"
+	if res && status == "Theorem" && Glob.GetPrintResults() {
+		printAnswers()
+	}
+
+	if !res && Glob.GetCompleteness() && Glob.GetPrintResults() {
+		printModel()
+	}
+}
+
+// Prints the status of a search that did not reach a result: either the time
+// limit has been reached or the search stopped without being complete.
+func PrintNoResult(noResultStatus string) {
+	Glob.PrintInfo("Res", fmt.Sprintf("%v goroutines created", Glob.GetNbGoroutines()))
+	Glob.PrintInfo("Res", "==== No result ====")
+
+	status = noResultStatus
+	switch status {
+	case TimeoutStatus:
+		exitCode = ExitTimeout
+	case GaveUpStatus:
+		exitCode = ExitGaveUp
+	case ResourceOutStatus:
+		exitCode = ExitResourceOut
+	}
+
+	printStandardSolution(status)
+}
+
+func GetExitCode() int {
+	return exitCode
+}
+
+func IsTimedOut() bool {
+	return timedOut
+}
+
+// Whether the last search has been stopped by -max_goroutines or -max_memory.
+func IsResourceOut() bool {
+	return resourceOut
+}
+
+// Returns a channel receiving a value when the time limit given by -timeout is
+// reached, or nil (which blocks forever) when there is no time limit.
+func timeLimit() <-chan time.Time {
+	if Glob.GetTimeout() < 0 {
+		return nil
+	}
+	deadline := Glob.GetStart().Add(time.Duration(Glob.GetTimeout()) * time.Second)
+	return time.After(time.Until(deadline))
+}
+
+// Returns a channel receiving the resource whose limit, given by -max_goroutines
+// or -max_memory, is exceeded, or nil (which blocks forever) when there is no
+// limit. The resources are checked until done is closed.
+func resourceLimit(done <-chan struct{}) <-chan string {
+	if Glob.GetMaxGoroutines() < 0 && Glob.GetMaxMemory() < 0 {
+		return nil
+	}
+
+	exceeded := make(chan string, 1)
+	searchGoroutines.Add(1)
+	go func() {
+		defer searchGoroutines.Done()
+		ticker := time.NewTicker(resourceCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if resource := exceededResource(); resource != "" {
+					exceeded <- resource
+					return
+				}
+			}
 		}
-	} else {
-		validity = "NOT VALID"
+	}()
+	return exceeded
+}
 
-		if Glob.IsConjectureFound() {
-			status = "CounterSatisfiable"
-		} else {
-			status = "Satisfiable"
+func exceededResource() string {
+	if limit := Glob.GetMaxGoroutines(); limit >= 0 && runtime.NumGoroutine() > limit {
+		return fmt.Sprintf("%d goroutines running", runtime.NumGoroutine())
+	}
+
+	// Unlike runtime.ReadMemStats, reading a metric does not stop the world.
+	if limit := Glob.GetMaxMemory(); limit >= 0 {
+		sample := []metrics.Sample{{Name: heapMetric}}
+		metrics.Read(sample)
+		if sample[0].Value.Kind() == metrics.KindUint64 && sample[0].Value.Uint64() > uint64(limit)<<20 {
+			return fmt.Sprintf("%d MiB in use", sample[0].Value.Uint64()>>20)
 		}
 	}
+	return ""
+}
 
-	Glob.PrintInfo("MAIN", fmt.Sprintf("%v RES : %v", "%", validity))
-	printStandardSolution(status)
+// Orders the root of the proof search to close itself, as cancelSearch does,
+// when one of its resource limits is exceeded.
+func cancelSearchForResources(c Communication, resource string) {
+	Glob.PrintInfo("MAIN", fmt.Sprintf("Resource limit reached (%s), closing the proof search", resource))
+	resourceOut = true
+	sendQuitOrder(c)
+}
+
+// Orders the root of the proof search to close itself (and its children) once
+// the time limit is reached.
+func cancelSearch(c Communication) {
+	Glob.PrintInfo("MAIN", "Time limit reached, closing the proof search")
+	timedOut = true
+	sendQuitOrder(c)
+}
+
+// Sends the close order to the root of the proof search asynchronously, as the
+// root may be busy applying rules. The order is dropped if the root stops first.
+func sendQuitOrder(c Communication) {
+	searchGoroutines.Add(1)
+	go func() {
+		defer searchGoroutines.Done()
+		select {
+		case c.getQuit() <- true:
+		case <-stopped:
+		}
+	}()
+}
+
+// Waits for the outcome of a search run in the background by another search
+// algorithm. The search is told to stop, by closing stop, when the time limit
+// or a resource limit is reached or when it is interrupted: the outcome it
+// then sends is discarded and false is returned.
+func AwaitSearch[T any](outcome <-chan T, stop chan<- struct{}) (T, bool) {
+	done := make(chan struct{})
+	defer close(done)
+
+	select {
+	case res := <-outcome:
+		return res, true
+	case <-timeLimit():
+		Glob.PrintInfo("MAIN", "Time limit reached, closing the proof search")
+		timedOut = true
+	case resource := <-resourceLimit(done):
+		Glob.PrintInfo("MAIN", fmt.Sprintf("Resource limit reached (%s), closing the proof search", resource))
+		resourceOut = true
+	case <-interruption:
+		Glob.PrintInfo("MAIN", "Time limit reached, closing the proof search")
+		timedOut = true
+	}
+
+	close(stop)
+	var res T
+	<-outcome
+	return res, false
 }
 
 // Do not change this function, it is the standard output for TPTP files
 func printStandardSolution(status string) {
+	if !Glob.GetPrintResults() {
+		return
+	}
 	fmt.Printf("%s SZS status %v for %v\n", "%", status, Glob.GetProblemName())
 }
 
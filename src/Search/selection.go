@@ -0,0 +1,189 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides the heuristics choosing, among the formulas of a branch to
+* which the same kind of rule applies, the one that is expanded first.
+**/
+
+package Search
+
+import (
+	"fmt"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+)
+
+// The kinds of formulas of a branch, by the rule that applies on them.
+type RuleKind int
+
+const (
+	AlphaKind RuleKind = iota
+	DeltaKind
+	BetaKind
+	GammaKind
+)
+
+type FormulaSelector interface {
+	// Returns the index of the formula to expand among the candidates, which
+	// are the formulas of the branch of the given kind.
+	Select(kind RuleKind, candidates Core.FormAndTermsList, state *State) int
+}
+
+var formulaSelector FormulaSelector = firstSelector{}
+
+func SetFormulaSelector(selector FormulaSelector) {
+	formulaSelector = selector
+}
+
+// Returns the selector of the given name, an error if there is none:
+//   - first: the formulas are expanded in the order of the branch (the default),
+//   - branches: the beta formulas with the fewest branches first,
+//   - connection: the beta formulas with a literal complementary to a literal of
+//     the branch first, as one of their branches closes at once,
+//   - size: the smallest formulas first.
+func MakeFormulaSelector(name string) (FormulaSelector, error) {
+	switch name {
+	case "first", "":
+		return firstSelector{}, nil
+	case "branches":
+		return betaSelector{fewestBranches}, nil
+	case "connection":
+		return betaSelector{firstConnected}, nil
+	case "size":
+		return sizeSelector{}, nil
+	}
+	return nil, fmt.Errorf("unknown formula selection %s (expected first, branches, connection or size)", name)
+}
+
+// Moves the formula chosen by the selector in front of the formulas of the given
+// kind.
+func selectFormula(kind RuleKind, forms Core.FormAndTermsList, state *State) Core.FormAndTermsList {
+	i := formulaSelector.Select(kind, forms, state)
+	if i <= 0 || i >= len(forms) {
+		return forms
+	}
+
+	selected := Core.FormAndTermsList{forms[i]}
+	selected = append(selected, forms[:i]...)
+	return append(selected, forms[i+1:]...)
+}
+
+type firstSelector struct{}
+
+func (firstSelector) Select(RuleKind, Core.FormAndTermsList, *State) int {
+	return 0
+}
+
+// Chooses among the beta formulas, the other ones being expanded in order.
+type betaSelector struct {
+	choose func(Core.FormAndTermsList, *State) int
+}
+
+func (s betaSelector) Select(kind RuleKind, candidates Core.FormAndTermsList, state *State) int {
+	if kind != BetaKind {
+		return 0
+	}
+	return s.choose(candidates, state)
+}
+
+func fewestBranches(candidates Core.FormAndTermsList, _ *State) int {
+	chosen, fewest := 0, -1
+	for i, fnt := range candidates {
+		if branches := len(betaBranches(fnt.GetForm())); fewest == -1 || branches < fewest {
+			chosen, fewest = i, branches
+		}
+	}
+	return chosen
+}
+
+func firstConnected(candidates Core.FormAndTermsList, state *State) int {
+	literals := state.atomic.ExtractForms()
+	for i, fnt := range candidates {
+		for _, branch := range betaBranches(fnt.GetForm()) {
+			if isLiteral(branch) && literals.Contains(complement(branch)) {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// The formulas that a beta rule puts on each branch, when there is only one on
+// each branch.
+func betaBranches(form AST.Form) []AST.Form {
+	switch nf := form.(type) {
+	case AST.Or:
+		return nf.FormList.Slice()
+	case AST.Imp:
+		return []AST.Form{AST.MakerNot(nf.GetF1()), nf.GetF2()}
+	case AST.Not:
+		if and, isAnd := nf.GetForm().(AST.And); isAnd {
+			branches := []AST.Form{}
+			for _, f := range and.FormList.Slice() {
+				branches = append(branches, AST.MakerNot(f))
+			}
+			return branches
+		}
+	}
+	// The equivalences have two branches of two formulas.
+	return []AST.Form{AST.MakerTop(), AST.MakerTop()}
+}
+
+func complement(literal AST.Form) AST.Form {
+	if not, isNot := literal.(AST.Not); isNot {
+		return not.GetForm()
+	}
+	return AST.MakerNot(literal)
+}
+
+type sizeSelector struct{}
+
+func (sizeSelector) Select(_ RuleKind, candidates Core.FormAndTermsList, _ *State) int {
+	chosen, smallest := 0, -1
+	for i, fnt := range candidates {
+		if size := formSize(fnt.GetForm()); smallest == -1 || size < smallest {
+			chosen, smallest = i, size
+		}
+	}
+	return chosen
+}
+
+// The number of connectives, quantifiers and atoms of the formula.
+func formSize(form AST.Form) int {
+	size := 1
+	for _, f := range form.GetChildFormulas().Slice() {
+		size += formSize(f)
+	}
+	return size
+}
@@ -67,6 +67,7 @@ type State struct {
 	forbidden                             []Unif.Substitutions
 	unifier                               Core.Unifier
 	eqStruct                              eqStruct.EqualityStruct
+	branch_forms                          branchForms // With -regularity only
 }
 
 /***********/
@@ -287,7 +288,8 @@ func MakeState(limit int, tp, tn Unif.DataStructure, f AST.Form) State {
 		false,
 		[]Unif.Substitutions{},
 		Core.MakeUnifier(),
-		eqStruct.NewEqStruct()}
+		eqStruct.NewEqStruct(),
+		branchForms{}}
 }
 
 /* Print a state */
@@ -435,6 +437,7 @@ func (st State) Copy() State {
 	new_state.SetCurrentProof(MakeEmptyProofStruct())
 	new_state.SetBTOnFormulas(st.GetBTOnFormulas())
 	new_state.SetForbiddenSubsts(st.GetForbiddenSubsts())
+	new_state.branch_forms = st.branch_forms.copy()
 	return new_state
 }
 
@@ -484,6 +487,10 @@ func (st *State) DispatchForm(f Core.FormAndTerms) {
 	default:
 		Glob.Anomaly("State", "Formula not recognized")
 	}
+
+	if Glob.GetRegularity() && Glob.IsDestructive() {
+		st.branch_forms.add(f)
+	}
 }
 
 /** Apply a sbstitution on a state
@@ -506,6 +513,15 @@ func ApplySubstitution(st *State, saf Core.SubstAndForm) error {
 	st.SetGamma(Core.ApplySubstitutionsOnFormAndTermsList(s, st.GetGamma()))
 	st.SetMetaGen(Core.ApplySubstitutionOnMetaGenList(s, st.GetMetaGen()))
 
+	// The formulas that become equal are pruned from the branch.
+	if Glob.GetRegularity() {
+		st.branch_forms = st.branch_forms.substitute(s)
+		st.SetAtomic(removeDuplicates(st.GetAtomic()))
+		st.SetAlpha(removeDuplicates(st.GetAlpha()))
+		st.SetBeta(removeDuplicates(st.GetBeta()))
+		st.SetDelta(removeDuplicates(st.GetDelta()))
+	}
+
 	st.SetTreePos(st.GetTreePos().MakeDataStruct(st.GetAtomic().ExtractForms(), true))
 	st.SetTreeNeg(st.GetTreeNeg().MakeDataStruct(st.GetAtomic().ExtractForms(), false))
 
@@ -0,0 +1,135 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the definitional transformation (-definitional).
+ **/
+
+package engine_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Engine"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+func TestMain(m *testing.M) {
+	AST.Init()
+	os.Exit(m.Run())
+}
+
+// Returns the definitions introduced for a problem made of a single statement,
+// with subformulas of size at most 3 left unnamed. The definitions precede the
+// statement where the subformulas are named.
+func definitions(problem string) []AST.Form {
+	Glob.SetDefinitionalSize(3)
+	defer Glob.SetDefinitionalSize(0)
+	Engine.ResetDefinitions()
+
+	statements, _, _ := Parser.ParseTPTPString(problem)
+	forms := []AST.Form{}
+	elaborated := Engine.ToInternalSyntax(statements)
+	for _, statement := range elaborated[:len(elaborated)-1] {
+		form, isForm := statement.GetForm().(Lib.Some[AST.Form])
+		if isForm && statement.GetRole() == Core.Axiom {
+			forms = append(forms, form.Val)
+		}
+	}
+	return forms
+}
+
+func isFreshPredicate(f AST.Form) bool {
+	pred, isPred := f.(AST.Pred)
+	return isPred && strings.HasPrefix(pred.GetID().GetName(), "@tseitin_")
+}
+
+func TestDefinitionalPolarities(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem string
+		// The side of the definition where the fresh predicate is: the left
+		// one of an implication, the right one, or the left one of an
+		// equivalence.
+		expected string
+	}{
+		{"positive", "fof(d, axiom, p | (q & (r | s))).\n", "imp_left"},
+		{"negative", "fof(d, axiom, (q & (r | s)) => p).\n", "imp_right"},
+		{"conjecture", "fof(d, conjecture, p | (q & (r | s))).\n", "imp_right"},
+		{"negated conjecture", "fof(d, conjecture, ~ (p | (q & (r | s)))).\n", "imp_left"},
+		{"equivalence", "fof(d, axiom, p <=> (q & (r | s))).\n", "equ_left"},
+	}
+
+	for _, test := range tests {
+		forms := definitions(test.problem)
+		if len(forms) != 1 {
+			t.Errorf("%s: expected 1 definition, got %d.", test.name, len(forms))
+			continue
+		}
+
+		var side string
+		switch def := forms[0].(type) {
+		case AST.Imp:
+			if isFreshPredicate(def.GetF1()) {
+				side = "imp_left"
+			} else if isFreshPredicate(def.GetF2()) {
+				side = "imp_right"
+			}
+		case AST.Equ:
+			if isFreshPredicate(def.GetF1()) {
+				side = "equ_left"
+			}
+		}
+		if side != test.expected {
+			t.Errorf("%s: expected the fresh predicate on the %s side, got the definition %s.", test.name, test.expected, forms[0].ToString())
+		}
+	}
+}
+
+func TestDefinitionalFreeVariables(t *testing.T) {
+	// The fresh predicate is applied to the variables of the subformula only.
+	forms := definitions("fof(d, axiom, ! [X, Y] : (p(Y) | (q(X) & (r(X) | s)))).\n")
+	if len(forms) != 1 {
+		t.Fatalf("Error: expected 1 definition, got %d.", len(forms))
+	}
+
+	all, isAll := forms[0].(AST.All)
+	if !isAll || len(all.GetVarList()) != 1 || all.GetVarList()[0].GetName() != "X" {
+		t.Fatalf("Error: expected a definition quantified over X, got %s.", forms[0].ToString())
+	}
+}
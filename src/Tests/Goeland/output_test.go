@@ -0,0 +1,190 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the certificates printed for the proofs.
+ **/
+
+package goeland_test
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/isabelle"
+	"github.com/GoelandProver/Goeland/Mods/lean"
+	"github.com/GoelandProver/Goeland/goeland"
+)
+
+// A typed problem whose proof uses beta, gamma and delta rules.
+const typedProblem = "tff(u_type, type, u: $tType).\ntff(p_type, type, p: u > $o).\n" +
+	"tff(f_type, type, f: u > u).\ntff(a_type, type, a: u).\n" +
+	"tff(a1, axiom, ! [X: u] : (p(X) => p(f(X)))).\ntff(a2, axiom, p(a) | ? [Y: u] : p(Y)).\n" +
+	"tff(c, conjecture, ? [Z: u] : p(f(Z))).\n"
+
+func proveTypedProblem(t *testing.T) goeland.Proof {
+	res, proof, err := goeland.ProveString(context.Background(), typedProblem, goeland.Options{})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	return proof
+}
+
+// Checks that the output matches every pattern.
+func expectPatterns(t *testing.T, output string, patterns ...string) {
+	t.Helper()
+	for _, pattern := range patterns {
+		if !regexp.MustCompile(pattern).MatchString(output) {
+			t.Fatalf("Error: the output does not match %q:\n%s", pattern, output)
+		}
+	}
+}
+
+func TestIsabelleOutput(t *testing.T) {
+	proof := proveTypedProblem(t)
+
+	isabelle.SetContextEnabled(true)
+	output := isabelle.MakeIsabelleProof(proof.GS3(), Lib.NewList[AST.Meta]())
+	isabelle.SetContextEnabled(false)
+
+	expectPatterns(t, output,
+		`(?m)^theory goeland_proof_of_\w+\n  imports Main\nbegin$`,
+		`(?m)^typedecl u$`,
+		`(?m)^consts p_\d+ :: "\(u => bool\)"$`,
+		`(?m)^consts f_\d+ :: "\(u => u\)"$`,
+		`(?m)^consts a_\d+ :: "u"$`,
+		`(?m)^  assumes H0: "\(ALL X\d+::u\. \(\(p_\d+ X\d+\) --> \(p_\d+ \(f_\d+ X\d+\)\)\)\)"$`,
+		`(?m)^  shows "\(EX Z\d+::u\. \(p_\d+ \(f_\d+ Z\d+\)\)\)"$`,
+		`(?m)^proof \(rule ccontr\)$`,
+		`proof \(rule goeland_or\[OF _ _ H1\]\)`,
+		`proof \(rule goeland_all\[where t = "a_\d+", OF _ H0\]\)`,
+		`proof \(rule goeland_ex\[OF _ H\d+\]\)\n *fix skolem_Y\d+_\d+ :: "u"`,
+		`\nend\n$`,
+	)
+
+	// Every rule is a lemma of the prelude, and every step is closed.
+	for _, rule := range regexp.MustCompile(`rule (goeland_\w+)\[`).FindAllStringSubmatch(output, -1) {
+		if !strings.Contains(output, "lemma "+rule[1]+":") {
+			t.Fatalf("Error: the rule %s is not in the prelude:\n%s", rule[1], output)
+		}
+	}
+	if strings.Count(output, "proof (") != strings.Count(output, "qed") {
+		t.Fatalf("Error: the steps are not all closed:\n%s", output)
+	}
+
+	// Without the context, only the theorem is printed.
+	output = isabelle.MakeIsabelleProof(proof.GS3(), Lib.NewList[AST.Meta]())
+	if strings.Contains(output, "lemma ") || strings.Contains(output, "typedecl") ||
+		!strings.HasPrefix(strings.TrimSpace(output), "theorem goeland_proof_of_") {
+		t.Fatalf("Error: unexpected output without the context:\n%s", output)
+	}
+}
+
+func TestLeanOutput(t *testing.T) {
+	proof := proveTypedProblem(t)
+
+	lean.SetContextEnabled(true)
+	output := lean.MakeLeanProof(proof.GS3(), Lib.NewList[AST.Meta]())
+	lean.SetContextEnabled(false)
+
+	expectPatterns(t, output,
+		`(?m)^axiom u : Type$`,
+		`(?m)^axiom goeland_inhabited_u : Inhabited u$`,
+		`(?m)^axiom p_\d+ : \(u → Prop\)$`,
+		`(?m)^axiom f_\d+ : \(u → u\)$`,
+		`(?m)^axiom a_\d+ : u$`,
+		`(?m)^theorem goeland_proof_of_\w*$`,
+		`(?m)^    \(H0 : \(∀ \(X\d+ : u\), \(\(p_\d+ X\d+\) → \(p_\d+ \(f_\d+ X\d+\)\)\)\)\)$`,
+		`(?m)^    \(∃ \(Z\d+ : u\), \(p_\d+ \(f_\d+ Z\d+\)\)\) := by\n  apply Classical.byContradiction\n  intro H\d+$`,
+		`(?m)^  goeland_beta goeland_or H1$`,
+		`(?m)^ +goeland_gamma goeland_all H0 a_\d+$`,
+		`(?m)^ +goeland_delta goeland_ex H\d+\n +intro «skolem@Y\d+_\d+» H\d+$`,
+	)
+
+	// Every rule is a theorem of the prelude, and every tactic is a macro.
+	steps := regexp.MustCompile(`(?m)^ +(goeland_\w+) (goeland_\w+) `).FindAllStringSubmatch(output, -1)
+	closures := regexp.MustCompile(`(?m)^ +exact (goeland_\w+) `).FindAllStringSubmatch(output, -1)
+	if len(steps) == 0 || len(closures) == 0 {
+		t.Fatalf("Error: the proof has no steps:\n%s", output)
+	}
+	for _, step := range steps {
+		if !strings.Contains(output, "macro \""+step[1]+" \"") {
+			t.Fatalf("Error: the tactic %s is not defined:\n%s", step[1], output)
+		}
+		if !strings.Contains(output, "theorem "+step[2]+" ") {
+			t.Fatalf("Error: the rule %s is not in the prelude:\n%s", step[2], output)
+		}
+	}
+	for _, closure := range closures {
+		if !strings.Contains(output, "theorem "+closure[1]+" ") {
+			t.Fatalf("Error: the rule %s is not in the prelude:\n%s", closure[1], output)
+		}
+	}
+
+	// Without the context, only the theorem is printed.
+	output = lean.MakeLeanProof(proof.GS3(), Lib.NewList[AST.Meta]())
+	if strings.Contains(output, "axiom ") || strings.Contains(output, "macro ") ||
+		!strings.HasPrefix(strings.TrimSpace(output), "theorem goeland_proof_of_") {
+		t.Fatalf("Error: unexpected output without the context:\n%s", output)
+	}
+}
+
+func TestLeanOutputReals(t *testing.T) {
+	problem := "tff(p_type, type, p: $real > $o).\ntff(a1, axiom, ! [X: $real] : p(X)).\ntff(c, conjecture, ? [Y: $real] : p(Y)).\n"
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+
+	lean.SetContextEnabled(true)
+	output := lean.MakeLeanProof(proof.GS3(), Lib.NewList[AST.Meta]())
+	lean.SetContextEnabled(false)
+
+	// The reals are defined in Mathlib, which is imported first.
+	expectPatterns(t, output,
+		`^import Mathlib\.Data\.Real\.Basic\n`,
+		`(?m)^axiom p_\d+ : \(Real → Prop\)$`,
+		`(?m)^    \(H0 : \(∀ \(X\d+ : Real\), \(p_\d+ X\d+\)\)\) :$`,
+	)
+
+	// The typed problem only relies on the core library.
+	lean.SetContextEnabled(true)
+	output = lean.MakeLeanProof(proveTypedProblem(t).GS3(), Lib.NewList[AST.Meta]())
+	lean.SetContextEnabled(false)
+	if strings.Contains(output, "import") {
+		t.Fatalf("Error: unexpected import:\n%s", output)
+	}
+}
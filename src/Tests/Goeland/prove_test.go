@@ -0,0 +1,546 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the use of Goéland as a library.
+ **/
+
+package goeland_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/coq"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/goeland"
+)
+
+func writeProblem(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "problem.p")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestProveTwice(t *testing.T) {
+	theorem := writeProblem(t, "fof(ax, axiom, ! [X] : (p(X) => q(X))).\nfof(c, conjecture, p(a) => q(a)).\n")
+	counterSat := writeProblem(t, "fof(ax, axiom, p(a) | q(a)).\nfof(c, conjecture, p(a)).\n")
+
+	for i := 0; i < 2; i++ {
+		res, proof, err := goeland.Prove(context.Background(), theorem, goeland.Options{})
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if res.Status != "Theorem" || proof.IsEmpty() {
+			t.Fatalf("Error: expected a proof, got the status %s.", res.Status)
+		}
+		if proof.GS3() == nil {
+			t.Fatal("Error: the GS3 proof is empty.")
+		}
+		if err := proof.Check(); err != nil {
+			t.Fatalf("Error: the proof is not valid, %v", err)
+		}
+		if proof.Size() < len(proof.Steps) || res.Goroutines == 0 {
+			t.Fatalf("Error: unexpected statistics (proof size %d, %d goroutines).", proof.Size(), res.Goroutines)
+		}
+
+		res, proof, err = goeland.Prove(context.Background(), counterSat, goeland.Options{Completeness: true})
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if res.Status != "CounterSatisfiable" || !proof.IsEmpty() {
+			t.Fatalf("Error: expected no proof, got the status %s.", res.Status)
+		}
+	}
+}
+
+func TestProveTimeout(t *testing.T) {
+	// The search never ends on this satisfiable problem.
+	problem := writeProblem(t, "fof(ax, axiom, ! [X] : ? [Y] : p(X, Y)).\nfof(c, conjecture, q).\n")
+
+	goroutines := runtime.NumGoroutine()
+	res, _, err := goeland.Prove(context.Background(), problem, goeland.Options{Timeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if res.Status != "Timeout" || Search.GetExitCode() != Search.ExitTimeout {
+		t.Fatalf("Error: expected a timeout, got the status %s (exit code %d).", res.Status, Search.GetExitCode())
+	}
+
+	// The goroutines of the search have stopped before Prove returns.
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Fatalf("Error: %d goroutines are still running after the search.", n-goroutines)
+	}
+}
+
+func TestProveGaveUp(t *testing.T) {
+	// The first step is not conclusive, as the axiom may have to be instantiated
+	// again.
+	problem := "fof(ax, axiom, ! [X] : (p(X) | q(f(X)))).\nfof(c, conjecture, r).\n"
+	for _, opts := range []goeland.Options{{OneStep: true}, {OneStep: true, Completeness: true}} {
+		res, _, err := goeland.ProveString(context.Background(), problem, opts)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if res.Status != "GaveUp" || Search.GetExitCode() != Search.ExitGaveUp {
+			t.Fatalf("Error: expected to give up, got the status %s (exit code %d).", res.Status, Search.GetExitCode())
+		}
+	}
+
+	// An open branch is saturated after the first step.
+	problem = "fof(ax, axiom, ? [X] : p(X)).\nfof(c, conjecture, q).\n"
+	res, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{OneStep: true, Completeness: true})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if res.Status != "CounterSatisfiable" || Search.GetExitCode() != Search.ExitSolved {
+		t.Fatalf("Error: expected a counter-model, got the status %s (exit code %d).", res.Status, Search.GetExitCode())
+	}
+}
+
+func TestProveErrors(t *testing.T) {
+	if _, _, err := goeland.Prove(context.Background(), writeProblem(t, "fof(ax, axiom, p(."), goeland.Options{}); err == nil {
+		t.Fatal("Error: a syntax error has not been reported.")
+	}
+	if _, _, err := goeland.Prove(context.Background(), filepath.Join(t.TempDir(), "none.p"), goeland.Options{}); err == nil {
+		t.Fatal("Error: a missing file has not been reported.")
+	}
+}
+
+func TestProveString(t *testing.T) {
+	res, proof, err := goeland.ProveString(
+		context.Background(),
+		"fof(ax, axiom, ! [X] : (p(X) => q(X))).\nfof(c, conjecture, p(a) => q(a)).\n",
+		goeland.Options{},
+	)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if res.Status != "Theorem" || proof.IsEmpty() {
+		t.Fatalf("Error: expected a proof, got the status %s.", res.Status)
+	}
+}
+
+func TestProveIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.p"), filepath.Join(dir, "second.p")
+	if err := os.WriteFile(first, []byte("include('second.p').\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("include('first.p').\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problem := "include('" + first + "').\nfof(c, conjecture, p).\n"
+	if _, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{}); err == nil {
+		t.Fatal("Error: an include cycle has not been reported.")
+	}
+}
+
+func TestProveAnswers(t *testing.T) {
+	question := "fof(a1, axiom, p(a) | p(b)).\nfof(q, question, ? [X] : p(X)).\n"
+
+	res, _, err := goeland.ProveString(context.Background(), question, goeland.Options{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if res.Status != "Theorem" || len(res.Answers) != 2 {
+		t.Fatalf("Error: expected two answers, got %v (status %s).", res.Answers, res.Status)
+	}
+
+	// The answers of a conjecture are only given on demand.
+	conjecture := strings.Replace(question, "question", "conjecture", 1)
+	res, _, _ = goeland.ProveString(context.Background(), conjecture, goeland.Options{})
+	if res.Answers != nil {
+		t.Fatalf("Error: unexpected answers %v.", res.Answers)
+	}
+	res, _, _ = goeland.ProveString(context.Background(), conjecture, goeland.Options{Answers: true})
+	if len(res.Answers) != 2 {
+		t.Fatalf("Error: expected two answers, got %v.", res.Answers)
+	}
+}
+
+func TestProveSine(t *testing.T) {
+	problem := "fof(a1, axiom, p).\nfof(a2, axiom, p => q).\nfof(c, conjecture, q).\n"
+
+	res, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{Completeness: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+
+	// The axiom a1 is dropped, so the search cannot conclude.
+	opts := goeland.Options{Completeness: true, SineTolerance: 1.0, SineDepth: 1}
+	res, _, err = goeland.ProveString(context.Background(), problem, opts)
+	if err != nil || res.Status != "GaveUp" {
+		t.Fatalf("Error: expected the status GaveUp, got %s (%v).", res.Status, err)
+	}
+
+	// The axioms without symbols are never triggered, but they are kept.
+	for _, axiom := range []string{"$false", "! [X, Y] : X = Y"} {
+		problem = "fof(a1, axiom, " + axiom + ").\nfof(a2, axiom, q(a) => r(a)).\nfof(c, conjecture, p(a) => p(b)).\n"
+		res, _, err = goeland.ProveString(context.Background(), problem, opts)
+		if err != nil || res.Status != "Theorem" {
+			t.Fatalf("Error: expected a proof with the axiom %s, got the status %s (%v).", axiom, res.Status, err)
+		}
+	}
+}
+
+func TestProofCheck(t *testing.T) {
+	res, proof, err := goeland.ProveString(context.Background(), "fof(c, conjecture, (p & q) => (q & p)).\n", goeland.Options{})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+
+	// The proof of another problem is rejected.
+	_, other, err := goeland.ProveString(context.Background(), "fof(c, conjecture, (q & p) => (p & q)).\n", goeland.Options{})
+	if err != nil || other.IsEmpty() {
+		t.Fatalf("Error: expected a proof (%v).", err)
+	}
+	other.Problem = proof.Problem
+	var rootError gs3.CheckError
+	if !errors.As(other.Check(), &rootError) || rootError.NodeId != other.Steps[0].Node_id {
+		t.Fatalf("Error: expected the root to be rejected, got %v.", rootError)
+	}
+
+	// A rule applied on a formula that is not on the branch is reported.
+	seq := proof.GS3()
+	roots := AST.NewFormList(seq.GetTargetForm())
+	seq.Child(0).SetTargetForm(AST.MakerPred(AST.MakerId("r"), Lib.NewList[AST.Term](), []AST.TypeApp{}))
+
+	var checkError gs3.CheckError
+	if !errors.As(gs3.CheckProof(seq, roots), &checkError) || checkError.NodeId != seq.Child(0).GetId() {
+		t.Fatalf("Error: expected an invalid step at node %d, got %v.", seq.Child(0).GetId(), checkError)
+	}
+}
+
+func TestProofCheckArithmetic(t *testing.T) {
+	// The branch is closed by the arithmetic module, not by complementary literals.
+	problem := "tff(x_type, type, x: $int).\ntff(a, axiom, $less(x, 3)).\ntff(c, conjecture, $less(x, 5)).\n"
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Arithmetic: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+
+	// The Skolem symbol of X is an integer: X < 3 and 2 < X have no integer
+	// solution, but have rational ones.
+	problem = "tff(c, conjecture, ! [X: $int] : ($less(X, 3) => $lesseq(X, 2))).\n"
+	res, proof, err = goeland.ProveString(context.Background(), problem, goeland.Options{Arithmetic: true, Timeout: 10 * time.Second})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+}
+
+func TestProveMiniscope(t *testing.T) {
+	problem := "fof(a, axiom, ! [X] : (p(X) & q)).\nfof(c, conjecture, q).\n"
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Miniscope: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+
+	// The proof starts from the original problem, and cuts the miniscoped one.
+	if output := coq.MakeCoqProof(proof.GS3(), Lib.NewList[AST.Meta]()); !strings.Contains(output, "apply (goeland_cut (") {
+		t.Fatalf("Error: the Coq output does not cut the miniscoped problem:\n%s", output)
+	}
+}
+
+func TestProofMinimize(t *testing.T) {
+	problem := "fof(a1, axiom, p(a)).\nfof(a2, axiom, q(b)).\nfof(a3, axiom, ! [X] : (r(X) => s(X))).\n" +
+		"fof(a4, axiom, ! [X] : (p(X) => t(X))).\nfof(c, conjecture, t(a) | r(b)).\n"
+
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+
+	minimized, axioms := proof.Minimize()
+	if len(axioms) != 2 || axioms[0].Name != "a1" || axioms[1].Name != "a4" {
+		t.Fatalf("Error: expected the axioms a1 and a4, got %v.", axioms)
+	}
+	if minimized.Size() > proof.Size() {
+		t.Fatalf("Error: the minimized proof has %d steps, against %d.", minimized.Size(), proof.Size())
+	}
+	if err := minimized.Check(); err != nil {
+		t.Fatalf("Error: the minimized proof is not valid, %v", err)
+	}
+
+	// The axioms left in the root are the ones that are used.
+	root, isAnd := minimized.Steps[0].GetFormula().GetForm().(AST.And)
+	if !isAnd || root.FormList.Len() != 3 {
+		t.Fatalf("Error: unexpected root %s.", minimized.Steps[0].GetFormula().GetForm().ToString())
+	}
+}
+
+func TestProveSelection(t *testing.T) {
+	problem := "fof(a1, axiom, p(a) | q(a)).\nfof(a2, axiom, ! [X] : (p(X) => r(X))).\n" +
+		"fof(a3, axiom, ! [X] : (q(X) => r(X))).\nfof(c, conjecture, r(a)).\n"
+
+	for _, selection := range []string{"first", "branches", "connection", "size"} {
+		res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Selection: selection})
+		if err != nil || res.Status != "Theorem" {
+			t.Fatalf("Error: expected a proof with %s, got the status %s (%v).", selection, res.Status, err)
+		}
+		if err := proof.Check(); err != nil {
+			t.Fatalf("Error: the proof found with %s is not valid, %v", selection, err)
+		}
+	}
+
+	if _, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{Selection: "none"}); err == nil {
+		t.Fatal("Error: an unknown heuristic has not been reported.")
+	}
+}
+
+func TestProveConnection(t *testing.T) {
+	problem := "fof(a1, axiom, ! [X] : ? [Y] : p(X, Y)).\nfof(a2, axiom, ! [X, Y] : (p(X, Y) => q(Y))).\n" +
+		"fof(a3, axiom, ! [X] : (q(X) => ? [Z] : (r(Z) & s(X, Z)))).\nfof(c, conjecture, ? [U, V] : (r(V) & s(U, V))).\n"
+
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Connection: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+
+	problem = "fof(a, axiom, ! [X] : (p(X) | q(X))).\nfof(c, conjecture, ? [X] : (p(X) & q(X))).\n"
+	res, _, err = goeland.ProveString(context.Background(), problem, goeland.Options{Connection: true})
+	if err != nil || res.Status != "CounterSatisfiable" {
+		t.Fatalf("Error: expected the status CounterSatisfiable, got %s (%v).", res.Status, err)
+	}
+}
+
+func TestProveIncremental(t *testing.T) {
+	problem := "fof(a, axiom, p(a)).\nfof(c, conjecture, ? [X] : p(X)).\n"
+	res, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{Incremental: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+
+	// The open branch is not a model, as the search does not use the equality.
+	problem = "fof(a, axiom, a = b).\nfof(c, conjecture, p(a) => p(b)).\n"
+	res, _, err = goeland.ProveString(context.Background(), problem, goeland.Options{Incremental: true})
+	if err != nil || res.Status != "GaveUp" {
+		t.Fatalf("Error: expected the status GaveUp, got %s (%v).", res.Status, err)
+	}
+
+	// The search never ends on this satisfiable problem.
+	problem = "fof(ax, axiom, ! [X] : (p(X) | q(f(X)))).\nfof(c, conjecture, r).\n"
+	res, _, err = goeland.ProveString(context.Background(), problem, goeland.Options{Incremental: true, Timeout: 300 * time.Millisecond})
+	if err != nil || res.Status != "Timeout" || Search.GetExitCode() != Search.ExitTimeout {
+		t.Fatalf("Error: expected a timeout, got the status %s (exit code %d, %v).", res.Status, Search.GetExitCode(), err)
+	}
+}
+
+func TestProveRegularity(t *testing.T) {
+	// Every beta rule has a branch that is already on the branch.
+	problem := "fof(a0, axiom, p).\n"
+	for i := 1; i <= 12; i++ {
+		problem += fmt.Sprintf("fof(a%d, axiom, p | q%d).\n", i, i)
+	}
+	problem += "fof(b, axiom, ! [X] : (r(X) | s(X))).\nfof(c, axiom, r(a)).\nfof(g, conjecture, ? [Y] : (r(Y) & p)).\n"
+
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Regularity: true, Timeout: 10 * time.Second})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+}
+
+func TestProveRegularityBeta(t *testing.T) {
+	// The branches of the beta rules are expanded concurrently, each one with
+	// its own formulas (run with -race).
+	problem := "fof(g, axiom, ! [X] : (s(X) | t(X))).\nfof(h, axiom, ! [X] : ((s(X) | t(X)) => u(X))).\n"
+	for i := 1; i <= 3; i++ {
+		problem += fmt.Sprintf("fof(a%d, axiom, p%d | q%d).\nfof(b%d, axiom, (p%d | q%d) => r%d).\n", i, i, i, i, i, i, i)
+	}
+	problem += "fof(c, conjecture, r1 & r2 & r3 & u(a)).\n"
+
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Regularity: true, Timeout: 10 * time.Second})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+
+	// The instances of the gamma formulas have the same hash, and are added to
+	// the same lists by both branches of the beta rules.
+	problem = "fof(g, axiom, ! [X] : (q | r)).\nfof(h, axiom, ! [X] : (s | t)).\nfof(c, conjecture, u).\n"
+	res, _, err = goeland.ProveString(context.Background(), problem, goeland.Options{Regularity: true, Timeout: 300 * time.Millisecond})
+	if err != nil || res.Status != "Timeout" {
+		t.Fatalf("Error: expected a timeout, got the status %s (%v).", res.Status, err)
+	}
+}
+
+func TestProveSimplify(t *testing.T) {
+	// The defined predicates are never assumed true.
+	problem := "tff(p_type, type, p: $o).\ntff(a, axiom, $less(2, 1)).\ntff(c, conjecture, p).\n"
+	res, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{Arithmetic: true, Simplify: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+
+	// The predicate p/1 is pure, but not p/0, so that only a2 can be removed.
+	problem = "fof(a1, axiom, p(a)).\nfof(a2, axiom, ~ p).\nfof(a3, axiom, p(a) => q).\nfof(c, conjecture, q).\n"
+	res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Simplify: true})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+	if err := proof.Check(); err != nil {
+		t.Fatalf("Error: the proof is not valid, %v", err)
+	}
+}
+
+func TestProveModel(t *testing.T) {
+	// The only saturated branch has p(a) and q(a) false.
+	problem := "fof(a, axiom, p(a) => q(a)).\nfof(c, conjecture, q(a)).\n"
+	res, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{Completeness: true})
+	if err != nil || res.Status != "CounterSatisfiable" {
+		t.Fatalf("Error: expected the status CounterSatisfiable, got %s (%v).", res.Status, err)
+	}
+	for _, expected := range []string{
+		"% SZS output start FiniteModel",
+		"fof(interpretation_domain, fi_domain, ! [X] : (X = a)).",
+		"fof(interpretation_atoms, fi_predicates, ((! [X1] : ~ p(X1)) & (! [X1] : ~ q(X1)))).",
+		"% SZS output end FiniteModel",
+	} {
+		if !strings.Contains(res.Model, expected) {
+			t.Fatalf("Error: the model does not contain %q:\n%s", expected, res.Model)
+		}
+	}
+
+	// The constants are distinct, so that the model falsifies the conjecture:
+	// the axioms of the model, with the negation of the conjecture as
+	// conjecture, are proven.
+	problem = "fof(a1, axiom, p(a) | q(b)).\nfof(a2, axiom, ~ p(a)).\nfof(c, conjecture, q(a)).\n"
+	res, _, err = goeland.ProveString(context.Background(), problem, goeland.Options{Completeness: true})
+	if err != nil || res.Status != "CounterSatisfiable" {
+		t.Fatalf("Error: expected the status CounterSatisfiable, got %s (%v).", res.Status, err)
+	}
+	if !strings.Contains(res.Model, "fof(interpretation_distinct, fi_domain, (a != b)).") {
+		t.Fatalf("Error: the constants are not distinct in the model:\n%s", res.Model)
+	}
+	interpretation := strings.NewReplacer("fi_domain", "axiom", "fi_predicates", "axiom").Replace(res.Model)
+	res, _, err = goeland.ProveString(context.Background(), interpretation+"fof(c, conjecture, ~ q(a)).\n", goeland.Options{})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: the model does not falsify the conjecture, got the status %s (%v).", res.Status, err)
+	}
+
+	// The domain is infinite with a function symbol.
+	problem = "fof(a, axiom, p(f(a))).\nfof(c, conjecture, q).\n"
+	res, _, _ = goeland.ProveString(context.Background(), problem, goeland.Options{Completeness: true})
+	if !strings.Contains(res.Model, "% SZS output start Model") ||
+		!strings.Contains(res.Model, "(! [X1] : (p(X1) <=> (X1 = f(a))))") || !strings.Contains(res.Model, "~ q") {
+		t.Fatalf("Error: unexpected model:\n%s", res.Model)
+	}
+
+	// No model is given without completeness.
+	res, _, _ = goeland.ProveString(context.Background(), problem, goeland.Options{})
+	if res.Model != "" {
+		t.Fatalf("Error: unexpected model:\n%s", res.Model)
+	}
+}
+
+func TestProveResourceOut(t *testing.T) {
+	// The search never ends on this satisfiable problem, and every step forks
+	// new branches.
+	problem := "fof(ax, axiom, ! [X] : (p(X) | q(f(X)))).\nfof(c, conjecture, r).\n"
+
+	for _, opts := range []goeland.Options{{MaxGoroutines: 50}, {MaxMemory: 1}} {
+		opts.Timeout = 10 * time.Second
+		goroutines := runtime.NumGoroutine()
+		res, _, err := goeland.ProveString(context.Background(), problem, opts)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		if res.Status != "ResourceOut" || Search.GetExitCode() != Search.ExitResourceOut {
+			t.Fatalf("Error: expected to run out of resources with %+v, got the status %s (exit code %d).",
+				opts, res.Status, Search.GetExitCode())
+		}
+		if n := runtime.NumGoroutine(); n > goroutines {
+			t.Fatalf("Error: %d goroutines are still running after the search.", n-goroutines)
+		}
+	}
+
+	// The limits do not apply to a problem that is solved first.
+	problem = "fof(ax, axiom, ! [X] : (p(X) => q(X))).\nfof(c, conjecture, p(a) => q(a)).\n"
+	res, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{MaxGoroutines: 1000, MaxMemory: 1000})
+	if err != nil || res.Status != "Theorem" {
+		t.Fatalf("Error: expected a proof, got the status %s (%v).", res.Status, err)
+	}
+}
+
+func TestProveReintroduction(t *testing.T) {
+	// The proof needs three instances of a2, whose terms are deeper than the
+	// ones of a3, which is not needed.
+	problem := "fof(a1, axiom, p(a)).\nfof(a2, axiom, ! [X] : (p(X) => p(f(X)))).\n" +
+		"fof(a3, axiom, ! [X] : (q(X) | r(X))).\nfof(c, conjecture, p(f(f(f(a))))).\n"
+
+	for _, policy := range []string{"uniform", "depth", "closures"} {
+		res, proof, err := goeland.ProveString(context.Background(), problem, goeland.Options{Reintroduction: policy, Timeout: 10 * time.Second})
+		if err != nil || res.Status != "Theorem" {
+			t.Fatalf("Error: expected a proof with %s, got the status %s (%v).", policy, res.Status, err)
+		}
+		if err := proof.Check(); err != nil {
+			t.Fatalf("Error: the proof found with %s is not valid, %v", policy, err)
+		}
+	}
+
+	if _, _, err := goeland.ProveString(context.Background(), problem, goeland.Options{Reintroduction: "none"}); err == nil {
+		t.Fatal("Error: an unknown policy has not been reported.")
+	}
+}
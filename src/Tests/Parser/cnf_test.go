@@ -0,0 +1,106 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the parsing and the elaboration of CNF statements.
+ **/
+
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Engine"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+func TestMain(m *testing.M) {
+	AST.Init()
+	os.Exit(m.Run())
+}
+
+func parseString(t *testing.T, content string) []Parser.PStatement {
+	file := filepath.Join(t.TempDir(), "problem.p")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statements, _, _ := Parser.ParseTPTPFile(file)
+	return statements
+}
+
+func TestCNFClauseIsClosed(t *testing.T) {
+	statements := parseString(t, "cnf(c1, axiom, ~p(X) | q(X, Y)).\n")
+
+	if len(statements) != 1 {
+		t.Fatalf("Error: expected 1 statement, got %d.", len(statements))
+	}
+	if !statements[0].IsClause() {
+		t.Fatal("Error: a cnf statement should be flagged as a clause.")
+	}
+
+	elaborated := Engine.ToInternalSyntax(statements)
+	switch f := elaborated[0].GetForm().(type) {
+	case Lib.Some[AST.Form]:
+		all, ok := f.Val.(AST.All)
+		if !ok {
+			t.Fatalf("Error: expected a universal closure, got %s.", f.Val.ToString())
+		}
+		if len(all.GetVarList()) != 2 {
+			t.Fatalf("Error: expected 2 closed variables, got %d.", len(all.GetVarList()))
+		}
+	default:
+		t.Fatal("Error: the clause has no formula.")
+	}
+}
+
+func TestCNFGroundClause(t *testing.T) {
+	statements := parseString(t, "cnf(c1, negated_conjecture, (~p(a) | a = b)).\n")
+	elaborated := Engine.ToInternalSyntax(statements)
+
+	if elaborated[0].GetRole() != Core.NegatedConjecture {
+		t.Fatalf("Error: expected a negated conjecture, got %s.", elaborated[0].GetRole().ToString())
+	}
+
+	switch f := elaborated[0].GetForm().(type) {
+	case Lib.Some[AST.Form]:
+		if _, ok := f.Val.(AST.Or); !ok {
+			t.Fatalf("Error: a ground clause should not be closed, got %s.", f.Val.ToString())
+		}
+	default:
+		t.Fatal("Error: the clause has no formula.")
+	}
+}
@@ -0,0 +1,129 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the parsing and the checking of SC-TPTP proofs.
+ **/
+
+package parser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/tptp"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+// The -osctptp output of Goéland on ! [X] : (p(X) => q(X)) |- p(a) => q(a).
+var sctptpProof = `
+fof(ax4, axiom, (! [X5] : ((p(X5) => q(X5))))).
+fof(c, conjecture, (p(a) => q(a))).
+fof(f6, plain, [(! [X5] : ((p(X5) => q(X5)))), ~((p(a) => q(a))), p(a), ~(q(a)), (p(a) => q(a)), ~(p(a))] --> [], inference(leftHyp, [status(thm), 2], [])).
+fof(f7, plain, [(! [X5] : ((p(X5) => q(X5)))), ~((p(a) => q(a))), p(a), ~(q(a)), (p(a) => q(a)), q(a)] --> [], inference(leftHyp, [status(thm), 5], [])).
+fof(f5, plain, [(! [X5] : ((p(X5) => q(X5)))), ~((p(a) => q(a))), p(a), ~(q(a)), (p(a) => q(a))] --> [], inference(leftImp2, [status(thm), 4], [f6, f7])).
+fof(f4, plain, [(! [X5] : ((p(X5) => q(X5)))), ~((p(a) => q(a))), p(a), ~(q(a))] --> [], inference(leftForall, [status(thm), 0, $fot(a)], [f5])).
+fof(f3, plain, [(! [X5] : ((p(X5) => q(X5)))), ~((p(a) => q(a)))] --> [], inference(leftNotImplies, [status(thm), 1], [f4])).
+fof(f2, plain, [(! [X5] : ((p(X5) => q(X5)))), (p(a) => q(a))] --> [(p(a) => q(a))], inference(hyp, [status(thm), 1], [])).
+fof(f1, plain, [(! [X5] : ((p(X5) => q(X5))))] --> [(p(a) => q(a)), ~((p(a) => q(a)))], inference(rightNot, [status(thm), 1], [f2])).
+fof(f0, plain, [(! [X5] : ((p(X5) => q(X5))))] --> [(p(a) => q(a))], inference(cut, [status(thm), 1], [f1, f3])).
+fof(ac0, plain, [] --> [(p(a) => q(a))], inference(cut, [status(thm), 0], [ax4, f0])).
+`
+
+func TestSCTPTPParse(t *testing.T) {
+	statements := Parser.ParseSCTPTPString(sctptpProof)
+
+	if len(statements) != 11 {
+		t.Fatalf("Error: expected 11 statements, got %d.", len(statements))
+	}
+	if statements[1].Role() != "conjecture" {
+		t.Fatalf("Error: expected a conjecture, got %s.", statements[1].Role())
+	}
+
+	step := statements[5]
+	sequent, isSequent := step.Sequent().(Lib.Some[Parser.PSequent])
+	if !isSequent || len(sequent.Val.Left()) != 4 || len(sequent.Val.Right()) != 0 {
+		t.Fatalf("Error: expected a sequent with 4 hypotheses for %s.", step.Name())
+	}
+
+	inference := step.Inference().(Lib.Some[Parser.PInference]).Val
+	if inference.Rule() != "leftForall" || len(inference.Params()) != 3 {
+		t.Fatalf("Error: unexpected inference %s with %d parameters.", inference.Rule(), len(inference.Params()))
+	}
+	if _, isTerm := inference.Params()[2].(Parser.PGeneralTermData); !isTerm {
+		t.Fatalf("Error: expected a $fot parameter, got %s.", inference.Params()[2].ToString())
+	}
+	if len(inference.Premises()) != 1 || inference.Premises()[0] != "f5" {
+		t.Fatalf("Error: expected the premise f5, got %v.", inference.Premises())
+	}
+}
+
+func TestSCTPTPCheck(t *testing.T) {
+	if err := tptp.CheckSCTPTPProof(Parser.ParseSCTPTPString(sctptpProof)); err != nil {
+		t.Fatalf("Error: the proof should be valid, got %v.", err)
+	}
+
+	// q(a) is not the complement of p(a).
+	tampered := strings.Replace(sctptpProof, "inference(leftHyp, [status(thm), 2]", "inference(leftHyp, [status(thm), 3]", 1)
+	expectCheckError(t, tampered, "f6")
+
+	// The instance does not match the premise.
+	tampered = strings.Replace(sctptpProof, "$fot(a)", "$fot(b)", 1)
+	expectCheckError(t, tampered, "f4")
+
+	// The conjecture is never proven.
+	tampered = strings.Replace(sctptpProof, "fof(ac0, plain, [] -->", "fof(ac0, plain, [p(a)] -->", 1)
+	expectCheckError(t, tampered, "-")
+}
+
+func TestSCTPTPCheckFreshness(t *testing.T) {
+	proof := `
+fof(s1, plain, [(? [X] : p(X)), ~(p(a)), p(a)] --> [], inference(leftHyp, [status(thm), 2], [])).
+fof(s0, plain, [(? [X] : p(X)), ~(p(a))] --> [], inference(leftExists, [status(thm), 0, 'a'], [s1])).
+`
+	expectCheckError(t, proof, "s0")
+}
+
+func expectCheckError(t *testing.T, proof, step string) {
+	t.Helper()
+	err := tptp.CheckSCTPTPProof(Parser.ParseSCTPTPString(proof))
+
+	var checkErr tptp.CheckError
+	if !errors.As(err, &checkErr) {
+		t.Fatalf("Error: expected a check error, got %v.", err)
+	}
+	if checkErr.Step != step {
+		t.Fatalf("Error: expected an error at the step %s, got %v.", step, err)
+	}
+}
@@ -0,0 +1,93 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+/**
+ * This file tests the parsing and the encoding of THF statements.
+ **/
+
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Engine"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+)
+
+func TestTHFApplication(t *testing.T) {
+	statements := parseString(t, "thf(f_type, type, f: $i > $i > $o).\nthf(ax, axiom, f @ a @ b).\n")
+
+	if len(statements) != 2 || statements[1].Language() != Parser.PTHF {
+		t.Fatal("Error: expected two thf statements.")
+	}
+
+	switch f := statements[1].Form().(type) {
+	case Lib.Some[Parser.PForm]:
+		app, ok := f.Val.(Parser.PApp)
+		if !ok {
+			t.Fatalf("Error: expected an application, got %s.", f.Val.ToString())
+		}
+		if _, ok := app.Fun().(Parser.PApp); !ok {
+			t.Fatalf("Error: application should be left-associative, got %s.", f.Val.ToString())
+		}
+	default:
+		t.Fatal("Error: the axiom has no formula.")
+	}
+
+	// The type declaration is consumed by the encoding.
+	elaborated := Engine.ToInternalSyntax(statements)
+	if len(elaborated) != 1 {
+		t.Fatalf("Error: expected 1 statement, got %d.", len(elaborated))
+	}
+	switch f := elaborated[0].GetForm().(type) {
+	case Lib.Some[AST.Form]:
+		if _, ok := f.Val.(AST.Pred); !ok {
+			t.Fatalf("Error: expected an atom, got %s.", f.Val.ToString())
+		}
+	default:
+		t.Fatal("Error: the axiom has no formula.")
+	}
+}
+
+func TestTHFLambdaIsLifted(t *testing.T) {
+	statements := parseString(t, "thf(c, conjecture, ? [P: $i > $o]: (P = (^ [X: $i]: (X = X)))).\n")
+	elaborated := Engine.ToInternalSyntax(statements)
+
+	if len(elaborated) != 2 {
+		t.Fatalf("Error: expected a definition and the conjecture, got %d statements.", len(elaborated))
+	}
+	if elaborated[0].GetRole() != Core.Axiom || elaborated[1].GetRole() != Core.Conjecture {
+		t.Fatal("Error: the definition of the lambda-abstraction should be an axiom preceding the conjecture.")
+	}
+}
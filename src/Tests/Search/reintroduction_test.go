@@ -0,0 +1,143 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the policies choosing the formulas to reintroduce.
+ **/
+
+package search_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/Unif"
+)
+
+func TestMain(m *testing.M) {
+	AST.Init()
+	os.Exit(m.Run())
+}
+
+// Returns the generator of metavariables ! [X] : pred(t), where t is X under
+// depth applications of f, reintroduced counter times.
+func generator(pred string, depth int, counter int) Core.MetaGen {
+	x := AST.MakerVar("X")
+	var term AST.Term = x
+	for i := 0; i < depth; i++ {
+		term = AST.MakerFun(AST.MakerId("f"), Lib.MkListV(term), []AST.TypeApp{})
+	}
+	form := AST.MakerAll([]AST.Var{x}, AST.MakerPred(AST.MakerId(pred), Lib.MkListV(term), []AST.TypeApp{}))
+	return Core.MakeMetaGen(Core.MakeFormAndTerm(form, Lib.NewList[AST.Term]()), counter)
+}
+
+// A substitution instantiating a metavariable of the generator of the given
+// index.
+func closureWith(index int) Unif.Substitutions {
+	return Unif.Substitutions{Unif.MakeSubstitution(AST.MakerMeta("X", index), AST.MakerConst(AST.MakerId("a")))}
+}
+
+func makePolicy(t *testing.T, name string) Search.ReintroductionPolicy {
+	policy, err := Search.MakeReintroductionPolicy(name)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	return policy
+}
+
+func TestUniformPolicy(t *testing.T) {
+	for _, name := range []string{"", "uniform"} {
+		generators := []Core.MetaGen{generator("p", 0, 3), generator("q", 2, 0)}
+		if chosen := makePolicy(t, name).Choose(generators); chosen != -1 {
+			t.Fatalf("Error: the policy %q chose the formula %d instead of the least reintroduced one.", name, chosen)
+		}
+	}
+
+	if _, err := Search.MakeReintroductionPolicy("none"); err == nil {
+		t.Fatal("Error: an unknown policy has not been reported.")
+	}
+}
+
+func TestDepthPolicy(t *testing.T) {
+	policy := makePolicy(t, "depth")
+
+	// The formula with deeper terms has a third of the weight of the other one.
+	tests := []struct {
+		deepCounter, shallowCounter, expected int
+	}{
+		{1, 1, 1},
+		{0, 1, 0},
+		{1, 3, 0},
+		{1, 4, 0},
+		{2, 5, 1},
+	}
+	for _, test := range tests {
+		generators := []Core.MetaGen{generator("q", 2, test.deepCounter), generator("p", 0, test.shallowCounter)}
+		if chosen := policy.Choose(generators); chosen != test.expected {
+			t.Errorf("Error: expected the formula %d with the counters %d and %d, got %d.",
+				test.expected, test.deepCounter, test.shallowCounter, chosen)
+		}
+	}
+}
+
+func TestClosuresPolicy(t *testing.T) {
+	policy := makePolicy(t, "closures")
+	generators := []Core.MetaGen{generator("p", 0, 1), generator("q", 0, 1)}
+
+	if chosen := policy.Choose(generators); chosen != 0 {
+		t.Fatalf("Error: expected the first formula in case of a tie, got %d.", chosen)
+	}
+
+	// The metavariables of q took part in two closures, so that q gets three
+	// times the weight of p.
+	policy.RecordClosure(generators, closureWith(1))
+	policy.RecordClosure(generators, closureWith(1))
+	if chosen := policy.Choose(generators); chosen != 1 {
+		t.Fatalf("Error: expected the formula that took part in the closures, got %d.", chosen)
+	}
+	generators[1] = Core.MakeMetaGen(generators[1].GetForm(), 4)
+	if chosen := policy.Choose(generators); chosen != 0 {
+		t.Fatalf("Error: expected the formula that is less reintroduced relatively to its weight, got %d.", chosen)
+	}
+
+	// Closures with metavariables of unknown formulas are ignored.
+	policy.RecordClosure(generators, closureWith(5))
+	policy.Reset()
+	generators[1] = Core.MakeMetaGen(generators[1].GetForm(), 1)
+	if chosen := policy.Choose(generators); chosen != 0 {
+		t.Fatalf("Error: the closures are not forgotten after a reset, got %d.", chosen)
+	}
+}
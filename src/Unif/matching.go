@@ -39,6 +39,7 @@ package Unif
 import (
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/GoelandProver/Goeland/AST"
 	"github.com/GoelandProver/Goeland/Glob"
@@ -194,7 +195,8 @@ func (m *Machine) unifyAux(node Node) []MatchingSubstitutions {
 
 /* Unify on goroutines - to manage die message */
 /* TODO : remove when debug ok */
-func (m *Machine) unifyAuxOnGoroutine(n Node, ch chan []MatchingSubstitutions, father_id uint64) {
+func (m *Machine) unifyAuxOnGoroutine(n Node, ch chan []MatchingSubstitutions, father_id uint64, wg *sync.WaitGroup) {
+	defer wg.Done()
 	Glob.PrintDebug(
 		"UA",
 		Lib.MkLazy(func() string { return fmt.Sprintf("Child of %v, Unify Aux", father_id) }),
@@ -216,6 +218,7 @@ func (m *Machine) launchChildrenSearch(node Node) []MatchingSubstitutions {
 	}
 
 	matching := []MatchingSubstitutions{}
+	var wg sync.WaitGroup
 	for i, n := range node.children {
 		ch := channels[i]
 		st := m.terms.Copy(AST.Term.Copy)
@@ -224,7 +227,8 @@ func (m *Machine) launchChildrenSearch(node Node) []MatchingSubstitutions {
 
 		copy := Machine{subst: sc, beginLock: m.beginLock, terms: st, meta: m.meta.Copy(), q: m.q, beginCount: m.beginCount, hasPushed: m.hasPushed, hasPoped: m.hasPoped, post: ip, topLevelTot: m.topLevelTot, topLevelCount: m.topLevelCount}
 
-		go copy.unifyAuxOnGoroutine(*n, ch, Glob.GetGID())
+		wg.Add(1)
+		go copy.unifyAuxOnGoroutine(*n, ch, Glob.GetGID(), &wg)
 		Glob.IncrGoRoutine(1)
 	}
 
@@ -240,6 +244,8 @@ func (m *Machine) launchChildrenSearch(node Node) []MatchingSubstitutions {
 		cpt_remaining_children--
 	}
 
+	// The children are waited for, so that none of them outlives the search.
+	wg.Wait()
 	return matching
 }
 
@@ -0,0 +1,241 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the batch mode: every problem of a directory or of a
+* list is run in turn, with a fresh state, and one line is reported for each
+* of them.
+**/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/goeland"
+)
+
+var batch_label = "Batch"
+
+type batchLine struct {
+	Problem    string `json:"problem"`
+	Status     string `json:"status"`
+	TimeMs     int64  `json:"time_ms"`
+	Goroutines int    `json:"goroutines"`
+	ProofSize  int    `json:"proof_size"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (line batchLine) toRecord() []string {
+	return []string{
+		line.Problem,
+		line.Status,
+		strconv.FormatInt(line.TimeMs, 10),
+		strconv.Itoa(line.Goroutines),
+		strconv.Itoa(line.ProofSize),
+		line.Error,
+	}
+}
+
+var batchHeader = []string{"problem", "status", "time_ms", "goroutines", "proof_size", "error"}
+
+// Options of the command line that are given to the problems of the batch. The
+// other ones are ignored.
+var batchSupportedOptions = map[string]bool{
+	"batch": true, "batch_format": true, "timeout": true, "l": true, "one_step": true,
+	"completeness": true, "answers": true, "dmt": true, "noeq": true, "sateq": true, "ari": true,
+	"inner": true, "preinner": true, "no-type-check": true, "core_limit": true, "silent": true, "sine": true,
+	"definitional": true, "miniscope": true, "simplify": true, "connection": true, "regularity": true,
+	"max_goroutines": true, "max_memory": true, "reintroduction": true, "selection": true,
+}
+
+// Runs every problem of the batch and returns the exit code of Goéland.
+func runBatch(problems string) int {
+	format := Glob.GetBatchFormat()
+	if format != "csv" && format != "json" {
+		Glob.Fatal(batch_label, fmt.Sprintf("Unknown report format %s (expected csv or json)", format))
+	}
+
+	files := readBatch(problems)
+	opts := batchOptions()
+
+	csvWriter := csv.NewWriter(os.Stdout)
+	if format == "csv" {
+		csvWriter.Write(batchHeader)
+		csvWriter.Flush()
+	}
+
+	for _, file := range files {
+		line := runBatchProblem(file, opts)
+
+		if format == "csv" {
+			csvWriter.Write(line.toRecord())
+			csvWriter.Flush()
+		} else {
+			encoded, _ := json.Marshal(line)
+			fmt.Println(string(encoded))
+		}
+	}
+
+	return 0
+}
+
+// Returns the problems of the batch: the .p files of a directory and of its
+// subdirectories, or the files of a list with one file per line. Empty lines and
+// lines starting with % or # are ignored in a list.
+func readBatch(problems string) []string {
+	info, err := os.Stat(problems)
+	if err != nil {
+		Glob.Fatal(batch_label, fmt.Sprintf("Could not open the batch: %v", err))
+	}
+
+	files := []string{}
+
+	if info.IsDir() {
+		err = filepath.WalkDir(problems, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && filepath.Ext(file) == ".p" {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			Glob.Fatal(batch_label, fmt.Sprintf("Could not read the batch: %v", err))
+		}
+		sort.Strings(files)
+		return files
+	}
+
+	f, err := os.Open(problems)
+	if err != nil {
+		Glob.Fatal(batch_label, fmt.Sprintf("Could not open the batch: %v", err))
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		file := strings.TrimSpace(scanner.Text())
+		if file == "" || strings.HasPrefix(file, "%") || strings.HasPrefix(file, "#") {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	if err := scanner.Err(); err != nil {
+		Glob.Fatal(batch_label, fmt.Sprintf("Could not read the batch: %v", err))
+	}
+
+	return files
+}
+
+// The options of the command line, as options of the library.
+func batchOptions() goeland.Options {
+	flag.Visit(func(f *flag.Flag) {
+		if !batchSupportedOptions[f.Name] {
+			Glob.PrintWarn(batch_label, fmt.Sprintf("The option -%s is ignored in batch mode", f.Name))
+		}
+	})
+
+	isSet := func(name string) bool {
+		return flag.Lookup(name).Value.String() == "true"
+	}
+
+	opts := goeland.Options{
+		Limit:                 Glob.GetLimit(),
+		OneStep:               Glob.IsOneStep(),
+		MaxGoroutines:         Glob.GetMaxGoroutines(),
+		MaxMemory:             Glob.GetMaxMemory(),
+		Reintroduction:        Glob.GetReintroduction(),
+		Selection:             Glob.GetSelection(),
+		Answers:               Glob.GetAnswers(),
+		Completeness:          Glob.GetCompleteness(),
+		DMT:                   Glob.IsLoaded("dmt"),
+		NoEquality:            isSet("noeq"),
+		SatEquality:           isSet("sateq"),
+		Arithmetic:            Glob.GetArithModule(),
+		InnerSkolemization:    Glob.IsInnerSko(),
+		PreInnerSkolemization: Glob.IsPreInnerSko(),
+		NoTypeCheck:           Glob.NoTypeCheck(),
+		DefinitionalSize:      Glob.GetDefinitionalSize(),
+		Miniscope:             Glob.GetMiniscope(),
+		Simplify:              Glob.GetSimplify(),
+		Connection:            isSet("connection"),
+		Regularity:            Glob.GetRegularity(),
+	}
+	opts.SineTolerance, opts.SineDepth = Glob.GetSine()
+
+	if Glob.GetTimeout() > 0 {
+		opts.Timeout = time.Duration(Glob.GetTimeout()) * time.Second
+	}
+
+	return opts
+}
+
+// Runs one problem of the batch. The search of the previous problem has
+// stopped when Prove returns, even after a timeout, so the problems do not
+// share the global state of the prover.
+func runBatchProblem(file string, opts goeland.Options) batchLine {
+	start := time.Now()
+	result, proof, err := goeland.Prove(context.Background(), file, opts)
+
+	if err != nil {
+		return batchLine{
+			Problem: file,
+			Status:  "Error",
+			TimeMs:  time.Since(start).Milliseconds(),
+			Error:   err.Error(),
+		}
+	}
+
+	return batchLine{
+		Problem:    file,
+		Status:     result.Status,
+		TimeMs:     result.Time.Milliseconds(),
+		Goroutines: result.Goroutines,
+		ProofSize:  proof.Size(),
+	}
+}
@@ -0,0 +1,363 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file provides the entry point to use Goéland as a library. The
+* configuration is given as a value rather than through the command line, and
+* the result of the search is returned instead of being printed.
+*
+* Goéland still relies on global state: the proofs are run one at a time, and
+* each call resets this state before searching.
+**/
+
+package goeland
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Core/Sko"
+	"github.com/GoelandProver/Goeland/Engine"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Mods/arith"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+	equality "github.com/GoelandProver/Goeland/Mods/equality/bse"
+	"github.com/GoelandProver/Goeland/Mods/equality/eqStruct"
+	"github.com/GoelandProver/Goeland/Mods/equality/sateq"
+	"github.com/GoelandProver/Goeland/Mods/gs3"
+	"github.com/GoelandProver/Goeland/Parser"
+	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/Search/connection"
+	"github.com/GoelandProver/Goeland/Search/incremental"
+)
+
+// The configuration of a proof search. The zero value corresponds to the
+// default options of the command line.
+type Options struct {
+	// Time limit of the search, none if zero. The deadline of the context
+	// given to Prove also applies.
+	Timeout time.Duration
+	// Limit of the destructive search (-l), the default one if zero.
+	Limit int
+	// Stops the destructive search after its first step (-one_step), with the
+	// GaveUp status when the step is not conclusive.
+	OneStep bool
+	// Policy choosing the formulas to reintroduce (-reintroduction): uniform,
+	// depth or closures, uniform if empty.
+	Reintroduction string
+	// Heuristic choosing the formula to expand first (-selection): first,
+	// branches, connection or size, first if empty.
+	Selection string
+	// The search stops with the ResourceOut status when more goroutines are
+	// running or more memory (in MiB) is in use, without limit if zero. It
+	// does not go on with fewer goroutines once the limit is reached.
+	MaxGoroutines int
+	MaxMemory     int
+	// Computes the answers of an existential conjecture, as it is always done
+	// for a question.
+	Answers bool
+	// Tolerance and depth of the SInE premise selection (-sine), which is
+	// disabled if the tolerance is zero. A depth of zero is unlimited.
+	SineTolerance float64
+	SineDepth     int
+	// Subformulas larger than this size are named by fresh predicates
+	// (-definitional), none if zero.
+	DefinitionalSize int
+	// Pushes the quantifiers inward before the search (-miniscope).
+	Miniscope bool
+	// Removes the statements that are not needed and simplifies the other
+	// ones before the search (-simplify).
+	Simplify bool
+	// Refutes the clausal form of the problem with the connection calculus
+	// instead of the tableau search (-connection).
+	Connection bool
+	// Uses the incremental search instead of the destructive one (-incr).
+	Incremental bool
+	// Never adds a formula twice to a branch of the destructive search, up to
+	// the substitutions applied on it (-regularity).
+	Regularity bool
+
+	Completeness          bool
+	DMT                   bool
+	NoEquality            bool
+	SatEquality           bool
+	Arithmetic            bool
+	InnerSkolemization    bool
+	PreInnerSkolemization bool
+	NoTypeCheck           bool
+}
+
+type Result struct {
+	// SZS status: Theorem, Unsatisfiable, CounterSatisfiable, Satisfiable,
+	// GaveUp or Timeout.
+	Status string
+	Time   time.Duration
+	// Number of goroutines created by the search.
+	Goroutines int
+	// The answers of a proven question, one tuple of terms per instance of the
+	// question used in the proof.
+	Answers [][]string
+	// The model of the problem given by an open saturated branch, in the TPTP
+	// interpretation format, when the search ends without proof in
+	// completeness mode. Empty if no saturated branch has been found.
+	Model string
+}
+
+// Tells whether the problem has been proven, i.e., the status is Theorem or
+// Unsatisfiable.
+func (r Result) IsProved() bool {
+	return r.Status == "Theorem" || r.Status == "Unsatisfiable"
+}
+
+// The proof found by the search, as a tableau. It is empty when the problem has
+// not been proven.
+type Proof struct {
+	Steps []Search.ProofStruct
+	// The problem given to the search, whose root the proof must refute.
+	Problem AST.Form
+}
+
+func (p Proof) IsEmpty() bool {
+	return len(p.Steps) == 0
+}
+
+// The number of steps of the tableau, in all its branches.
+func (p Proof) Size() int {
+	return proofSize(p.Steps)
+}
+
+func proofSize(steps []Search.ProofStruct) int {
+	size := len(steps)
+	for _, step := range steps {
+		for _, child := range step.Children {
+			size += proofSize(child)
+		}
+	}
+	return size
+}
+
+// Translates the tableau into a GS3 sequent proof, from which the proofs in
+// the other formats are built.
+func (p Proof) GS3() *gs3.GS3Sequent {
+	return gs3.MakeGS3Proof(p.Steps)
+}
+
+// Checks the GS3 proof of the problem with the built-in checker. The error is a
+// gs3.CheckError locating the first invalid rule application, or, when the
+// rest of the proof is valid, a gs3.UncheckedError locating the first rule
+// application that cannot be checked.
+func (p Proof) Check() error {
+	return gs3.CheckSearchProof(p.Problem, p.Steps)
+}
+
+// Returns the proof without the steps and the axioms that no closure depends
+// on, along with the axioms of the problem that it uses.
+func (p Proof) Minimize() (Proof, []Search.Axiom) {
+	steps, axioms := Search.MinimizeProof(p.Steps)
+	return Proof{Steps: steps, Problem: p.Problem}, axioms
+}
+
+var proverLock sync.Mutex
+
+// The hooks of the plugins, as they are before any plugin is enabled.
+var defaultTryEquality = Search.TryEquality
+var defaultTryArithmeticClosure = Search.TryArithmeticClosure
+var defaultArithmeticClosure = gs3.ArithmeticClosure
+var defaultNewEqStruct = eqStruct.NewEqStruct
+
+// Runs Goéland on the TPTP problem in the given file. The error is not nil
+// if the problem cannot be loaded or if the context is cancelled; reaching
+// the deadline of the context gives the Timeout status instead.
+//
+// Prove returns once the goroutines of the search have stopped. The fatal
+// errors of the prover (an invalid option, a proof rejected by -check, ...)
+// are returned as errors: they are raised by the calling goroutine. A panic in
+// a goroutine of the search is a bug of the prover and stops the program.
+func Prove(ctx context.Context, problem string, opts Options) (Result, Proof, error) {
+	return prove(ctx, func() (AST.Form, int, error) { return LoadProblem(problem) }, opts)
+}
+
+// Runs Goéland on the TPTP problem given as a string, see Prove. The includes
+// of the problem are looked up from the current directory.
+func ProveString(ctx context.Context, problem string, opts Options) (Result, Proof, error) {
+	return prove(ctx, func() (AST.Form, int, error) { return LoadProblemString(problem) }, opts)
+}
+
+func prove(ctx context.Context, load func() (AST.Form, int, error), opts Options) (result Result, proof Proof, err error) {
+	proverLock.Lock()
+	defer proverLock.Unlock()
+
+	Glob.SetRecoverableErrors(true)
+	defer Glob.SetRecoverableErrors(false)
+	defer func() {
+		if r := recover(); r != nil {
+			fatal, isFatal := r.(Glob.FatalError)
+			if !isFatal {
+				panic(r)
+			}
+			result, proof, err = Result{}, Proof{}, fatal
+		}
+	}()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	configure(opts)
+
+	form, bound, err := load()
+	if err != nil {
+		return Result{}, Proof{}, err
+	}
+
+	Search.SetInterruption(ctx.Done())
+	defer Search.SetInterruption(nil)
+	Search.Search(form, bound)
+
+	result = Result{
+		Search.GetStatus(),
+		time.Since(Glob.GetStart()),
+		Glob.GetNbGoroutines(),
+		Search.GetAnswers(),
+		Search.GetModel(),
+	}
+	if result.IsProved() {
+		proof = Proof{Search.GetFinalProof(), form}
+	}
+
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return result, proof, ctx.Err()
+	}
+	return result, proof, nil
+}
+
+// Resets the global state of Goéland and applies the options.
+func configure(opts Options) {
+	Glob.DisableLoggers()
+	Glob.SetPrintResults(false)
+	// The proof is only built when it is to be output.
+	Glob.SetProof(true)
+	Glob.SetStart(time.Now())
+	Glob.SetTimeout(-1)
+	Glob.SetNbStep(1)
+	Glob.SetNbGoroutines(0)
+	Glob.SetConjecture(false)
+	Glob.SetOneStep(opts.OneStep)
+	Glob.SetDestructive(true)
+
+	Glob.SetLimit(noLimitIfZero(opts.Limit))
+	Glob.SetMaxGoroutines(noLimitIfZero(opts.MaxGoroutines))
+	Glob.SetMaxMemory(noLimitIfZero(opts.MaxMemory))
+
+	policy, err := Search.MakeReintroductionPolicy(opts.Reintroduction)
+	if err != nil {
+		Glob.Fatal("Options", err.Error())
+	}
+	Glob.SetReintroduction(opts.Reintroduction)
+	Search.SetReintroductionPolicy(policy)
+
+	selector, err := Search.MakeFormulaSelector(opts.Selection)
+	if err != nil {
+		Glob.Fatal("Options", err.Error())
+	}
+	Glob.SetSelection(opts.Selection)
+	Search.SetFormulaSelector(selector)
+
+	Glob.SetCompleteness(opts.Completeness)
+	Glob.SetAnswers(opts.Answers)
+	Glob.SetSine(opts.SineTolerance, opts.SineDepth)
+	Glob.SetDefinitionalSize(opts.DefinitionalSize)
+	Glob.SetMiniscope(opts.Miniscope)
+	Glob.SetSimplify(opts.Simplify)
+	Glob.SetRegularity(opts.Regularity)
+	Search.SetAnswerVariables(nil)
+	Glob.SetArithModule(opts.Arithmetic)
+	Glob.SetInnerSko(opts.InnerSkolemization)
+	Glob.SetPreInnerSko(opts.PreInnerSkolemization)
+	Glob.SetTypeCheck(!opts.NoTypeCheck)
+
+	switch {
+	case opts.PreInnerSkolemization:
+		Core.SetSelectedSkolemization(Sko.MkPreInnerSkolemization())
+	case opts.InnerSkolemization:
+		Core.SetSelectedSkolemization(Sko.MkInnerSkolemization())
+	default:
+		Core.SetSelectedSkolemization(Sko.MkOuterSkolemization())
+	}
+
+	Search.SetSearchAlgorithm(Search.NewDestructiveSearch())
+	switch {
+	case opts.Connection:
+		Search.SetSearchAlgorithm(connection.NewConnectionSearch())
+	case opts.Incremental:
+		Search.SetSearchAlgorithm(incremental.NewIncrementalSearch())
+	}
+	Search.TryEquality = defaultTryEquality
+	Search.TryArithmeticClosure = defaultTryArithmeticClosure
+	gs3.ArithmeticClosure = defaultArithmeticClosure
+	eqStruct.NewEqStruct = defaultNewEqStruct
+
+	switch {
+	case opts.SatEquality:
+		equality.SetTryEquality()
+		sateq.Enable()
+	case !opts.NoEquality:
+		equality.Enable()
+	}
+
+	if opts.Arithmetic {
+		arith.Enable()
+	}
+
+	Glob.SetPlugin("dmt", opts.DMT)
+	if opts.DMT {
+		dmt.InitPlugin()
+	}
+
+	AST.Init()
+	Parser.ResetParsingState()
+	Engine.ResetHOSignature()
+	Engine.ResetDefinitions()
+}
+
+func noLimitIfZero(limit int) int {
+	if limit > 0 {
+		return limit
+	}
+	return -1
+}
@@ -0,0 +1,455 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file loads a problem: it parses the problem and its includes, and
+* builds the formula to refute.
+**/
+
+package goeland
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Engine"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/dmt"
+	"github.com/GoelandProver/Goeland/Parser"
+	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/Typing"
+)
+
+var load_label = "Load"
+
+// Parses the problem in the given file, or on the standard input if the file
+// is Parser.StdinName, and returns the formula to refute with the bound of the
+// search.
+func LoadProblem(problem string) (AST.Form, int, error) {
+	statements, bound, containsEquality := Parser.ParseTPTPFile(problem)
+
+	// The includes of a problem read on the standard input are looked up from
+	// the current directory.
+	problemDir := path.Dir(problem)
+	if problem == Parser.StdinName {
+		problemDir = "."
+	}
+
+	return loadStatements(statements, bound, containsEquality, problemDir)
+}
+
+// Parses the problem given as a string. Its includes are looked up from the
+// current directory.
+func LoadProblemString(problem string) (AST.Form, int, error) {
+	statements, bound, containsEquality := Parser.ParseTPTPString(problem)
+	return loadStatements(statements, bound, containsEquality, ".")
+}
+
+func loadStatements(statements []Parser.PStatement, bound int, containsEquality bool, problemDir string) (AST.Form, int, error) {
+	actualStatements := Engine.ToInternalSyntax(statements)
+
+	Glob.PrintDebug(
+		load_label,
+		Lib.MkLazy(func() string {
+			return fmt.Sprintf(
+				"Statement : %s", Core.StatementListToString(actualStatements))
+		}),
+	)
+
+	if Glob.GetLimit() != -1 {
+		bound = Glob.GetLimit()
+	}
+
+	form, bound, contEq := StatementListToFormula(actualStatements, bound, problemDir)
+	containsEquality = containsEquality || contEq
+
+	if !containsEquality {
+		Glob.SetPlugin("equality", false)
+		Glob.PrintInfo("EQU", "Plugin Equality disabled")
+	}
+
+	if form == nil {
+		return nil, bound, errors.New("problem not found")
+	}
+
+	if err := checkForTypedProof(form); err != nil {
+		return nil, bound, err
+	}
+
+	return form, bound, nil
+}
+
+// FIXME: eventually, we would want to add an "interpretation" layer between elab and internal representation that does this
+func StatementListToFormula(statements []Core.Statement, old_bound int, problemDir string) (form AST.Form, bound int, containsEquality bool) {
+	Search.ClearAxioms()
+	Core.ClearMiniscopings()
+	Glob.SetPremisesDropped(false)
+
+	tolerance, depth := Glob.GetSine()
+	if tolerance <= 0 && !Glob.GetSimplify() {
+		return statementListToFormula(statements, old_bound, problemDir, []string{})
+	}
+
+	// The statements of all the included files are simplified, and the premises
+	// are selected among their axioms.
+	expanded, bound, containsEquality, ok := expandIncludes(statements, old_bound, problemDir, []string{})
+	if !ok {
+		return nil, -1, false
+	}
+
+	if Glob.GetSimplify() {
+		expanded = simplifyStatements(expanded)
+	}
+	if tolerance > 0 {
+		expanded = selectPremises(expanded, tolerance, depth)
+	}
+
+	form, bound, contEq := statementListToFormula(expanded, bound, problemDir, []string{})
+	return form, bound, containsEquality || contEq
+}
+
+// Replaces the includes by the statements of the included files, recursively.
+func expandIncludes(statements []Core.Statement, old_bound int, problemDir string, includeStack []string) (expanded []Core.Statement, bound int, containsEquality bool, ok bool) {
+	bound = old_bound
+
+	for _, statement := range statements {
+		if statement.GetRole() != Core.Include {
+			expanded = append(expanded, statement)
+			continue
+		}
+
+		included, ok := parseInclude(statement.GetName(), problemDir, includeStack)
+		if !ok {
+			return nil, -1, false, false
+		}
+
+		includedStatements, new_bound, contEq, ok := expandIncludes(
+			included.statements,
+			included.bound,
+			included.dir,
+			append(includeStack, included.file),
+		)
+		if !ok {
+			return nil, -1, false, false
+		}
+
+		bound = new_bound
+		containsEquality = containsEquality || included.containsEquality || contEq
+		expanded = append(expanded, includedStatements...)
+	}
+
+	return expanded, bound, containsEquality, true
+}
+
+// The include stack holds the files being included, from the outermost one, in
+// order to detect the include cycles.
+func statementListToFormula(statements []Core.Statement, old_bound int, problemDir string, includeStack []string) (form AST.Form, bound int, containsEquality bool) {
+	and_list := AST.NewFormList()
+	var not_form AST.Form
+	bound = old_bound
+
+	for _, statement := range statements {
+		switch statement.GetRole() {
+		case Core.Include:
+			included, ok := parseInclude(statement.GetName(), problemDir, includeStack)
+			if !ok {
+				return nil, -1, false
+			}
+
+			containsEquality = containsEquality || included.containsEquality
+			new_form_list, new_bound, contEq := statementListToFormula(
+				included.statements,
+				included.bound,
+				included.dir,
+				append(includeStack, included.file),
+			)
+			containsEquality = containsEquality || contEq
+
+			if new_form_list != nil {
+				bound = new_bound
+				and_list.Append(new_form_list)
+			}
+
+		case Core.Axiom:
+			switch f := statement.GetForm().(type) {
+			case Lib.Some[AST.Form]:
+				and_list = doAxiomStatement(and_list, statement.GetName(), f.Val)
+			case Lib.None[AST.Form]:
+				Glob.Anomaly(load_label, "Axiom statement "+statement.ToString()+" has no formula")
+			}
+
+		case Core.NegatedConjecture:
+			switch f := statement.GetForm().(type) {
+			case Lib.Some[AST.Form]:
+				negatedConjecture := maybeMiniscope(f.Val.RenameVariables())
+				Search.AddAxiom(statement.GetName(), negatedConjecture)
+				and_list.Append(negatedConjecture)
+			case Lib.None[AST.Form]:
+				Glob.Anomaly(load_label, "Negated conjecture statement "+statement.ToString()+" has no formula")
+			}
+
+		case Core.Conjecture:
+			switch f := statement.GetForm().(type) {
+			case Lib.Some[AST.Form]:
+				not_form = doConjectureStatement(f.Val)
+			case Lib.None[AST.Form]:
+				Glob.Anomaly(load_label, "Conjecture statement "+statement.ToString()+" has no formula")
+			}
+
+		case Core.Question:
+			switch f := statement.GetForm().(type) {
+			case Lib.Some[AST.Form]:
+				not_form = doQuestionStatement(f.Val)
+			case Lib.None[AST.Form]:
+				Glob.Anomaly(load_label, "Question statement "+statement.ToString()+" has no formula")
+			}
+
+		case Core.Type:
+			switch ty := statement.GetAtomTyping().(type) {
+			case Lib.Some[Core.TFFAtomTyping]:
+				doTypeStatement(ty.Val)
+			case Lib.None[Core.TFFAtomTyping]:
+				Glob.Anomaly(load_label, "Type statement "+statement.ToString()+" has no formula")
+			}
+		}
+	}
+
+	switch {
+	case and_list.IsEmpty() && not_form == nil:
+		return nil, bound, containsEquality
+	case and_list.IsEmpty():
+		return AST.MakerNot(not_form), bound, containsEquality
+	case not_form == nil:
+		return AST.MakerAnd(and_list), bound, containsEquality
+	default:
+		flattened := and_list.Flatten()
+		flattened.Append(AST.MakerNot(not_form))
+		return AST.MakerAnd(flattened), bound, containsEquality
+	}
+}
+
+// The statements of an included file, with the directory from which its own
+// includes are looked up.
+type includedFile struct {
+	file             string
+	dir              string
+	statements       []Core.Statement
+	bound            int
+	containsEquality bool
+}
+
+func parseInclude(fileName string, problemDir string, includeStack []string) (includedFile, bool) {
+	realname, err := getFile(fileName, problemDir)
+	Glob.PrintDebug(
+		load_label,
+		Lib.MkLazy(func() string { return fmt.Sprintf("File to parse : %s\n", realname) }),
+	)
+
+	if err != nil {
+		Glob.PrintError(load_label, err.Error())
+		return includedFile{}, false
+	}
+
+	checkIncludeCycle(realname, includeStack)
+
+	includeDir := path.Join(problemDir, path.Dir(fileName))
+	if path.IsAbs(fileName) {
+		includeDir = path.Dir(fileName)
+	}
+
+	statements, bound, containsEquality := Parser.ParseTPTPFile(realname)
+	return includedFile{realname, includeDir, Engine.ToInternalSyntax(statements), bound, containsEquality}, true
+}
+
+func doAxiomStatement(andList *AST.FormList, name string, f AST.Form) *AST.FormList {
+	newForm := maybeMiniscope(f.RenameVariables())
+	Search.AddAxiom(name, newForm)
+
+	// FIXME: dmt should be a plugin and therefore not checked here.
+	// Ideally, we want to be able to define a hook here and let the plugins do
+	// whatever they want, returning only whether they consumed the axiom or
+	// not. It would also avoid duplicated code.
+	if !Glob.IsLoaded("dmt") {
+		andList.Append(newForm)
+		return andList
+	}
+
+	consumed := dmt.RegisterAxiom(newForm.Copy())
+	if !consumed {
+		andList.Append(newForm)
+		return andList
+	}
+
+	return andList
+}
+
+func doConjectureStatement(f AST.Form) AST.Form {
+	Glob.SetConjecture(true)
+	conjecture := f.RenameVariables()
+
+	// The answers are read from the outermost existential quantifier, which
+	// miniscoping could split.
+	if Glob.GetAnswers() {
+		registerAnswerVariables(conjecture)
+	} else {
+		conjecture = maybeMiniscope(conjecture)
+	}
+
+	return conjecture
+}
+
+func maybeMiniscope(form AST.Form) AST.Form {
+	if !Glob.GetMiniscope() {
+		return form
+	}
+	return Core.MiniscopeStatement(form)
+}
+
+// A question is a conjecture whose answers are always printed.
+func doQuestionStatement(f AST.Form) AST.Form {
+	Glob.SetConjecture(true)
+	question := f.RenameVariables()
+	registerAnswerVariables(question)
+	return question
+}
+
+// The answers are the instances of the outermost existential variables of the
+// conjecture. They are read from the global unifier, which is only computed
+// when the proof is.
+func registerAnswerVariables(conjecture AST.Form) {
+	ex, isEx := conjecture.(AST.Ex)
+	if !isEx {
+		Glob.PrintWarn(load_label, "The conjecture is not existential, no answer will be given")
+		return
+	}
+
+	Search.SetAnswerVariables(ex.GetVarList())
+	Glob.SetProof(true)
+}
+
+func doTypeStatement(atomTyping Core.TFFAtomTyping) {
+	typeScheme := atomTyping.Ts
+
+	if typeScheme == nil {
+		Glob.PrintWarn("main", fmt.Sprintf("The constant %s has no type!", atomTyping.Literal.ToString()))
+		return
+	}
+
+	if typeScheme.Size() == 1 {
+		isNewType := typeScheme.ToString() == "$tType"
+		if isNewType {
+			AST.MkTypeHint(atomTyping.Literal.GetName())
+		} else {
+			isConstant := !Glob.Is[AST.QuantifiedType](typeScheme)
+			if isConstant {
+				AST.SaveConstant(atomTyping.Literal.GetName(), typeScheme.GetPrimitives()[0])
+			} else {
+				AST.SavePolymorphScheme(atomTyping.Literal.GetName(), typeScheme)
+			}
+		}
+	} else {
+		switch typeScheme.(type) {
+		case AST.TypeArrow:
+			AST.SaveTypeScheme(atomTyping.Literal.GetName(), AST.GetInputType(typeScheme)[0], AST.GetOutType(typeScheme))
+		case AST.QuantifiedType:
+			AST.SavePolymorphScheme(atomTyping.Literal.GetName(), typeScheme)
+		}
+	}
+}
+
+func getFile(filename string, dir string) (string, error) {
+	fileExists := func(err error) bool {
+		return err == nil && !errors.Is(err, os.ErrNotExist)
+	}
+	// Check in Goéland's path
+	_, err := os.Stat(filename)
+	if fileExists(err) {
+		return filename, err
+	}
+
+	// Check in the dir's path
+	otherFilename := path.Join(dir, filename)
+	_, err = os.Stat(otherFilename)
+	if fileExists(err) {
+		return otherFilename, err
+	}
+
+	// Check in the environment variable
+	directory := os.Getenv("TPTP")
+	otherFilename = path.Join(directory, filename)
+	_, err = os.Stat(otherFilename)
+	if fileExists(err) {
+		return otherFilename, err
+	}
+
+	return "", fmt.Errorf("file %s not found", filename)
+}
+
+// Stops the loading if the file is already being included.
+func checkIncludeCycle(file string, includeStack []string) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		absFile = file
+	}
+
+	for i, included := range includeStack {
+		absIncluded, err := filepath.Abs(included)
+		if err != nil {
+			absIncluded = included
+		}
+
+		if absIncluded == absFile {
+			cycle := append(append([]string{}, includeStack[i:]...), file)
+			Glob.Fatal(load_label, fmt.Sprintf("Include cycle: %s", strings.Join(cycle, " -> ")))
+		}
+	}
+}
+
+func checkForTypedProof(form AST.Form) error {
+	isTypedProof := !AST.EmptyGlobalContext() && !Glob.NoTypeCheck()
+
+	if isTypedProof {
+		if err := Typing.WellFormedVerification(form.Copy(), Glob.GetTypeProof()); err != nil {
+			return fmt.Errorf("typing error: %v", err)
+		}
+		Glob.PrintInfo(load_label, "Well typed.")
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file simplifies the statements of a problem before the search: the
+* $true and $false subformulas are simplified, and the tautologies, the
+* duplicate axioms (up to the names of their variables) and the axioms made
+* true by the pure predicates are removed.
+*
+* A predicate is pure when it occurs with only one polarity in the problem. It
+* can then be assumed true (or false, when it only occurs negatively), which
+* keeps the other formulas satisfiable, so that the axioms which are true under
+* this assumption are not needed.
+**/
+
+package goeland
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+const simplify_label = "Simplify"
+
+type polarity int
+
+const (
+	positive polarity = 1 << iota
+	negative
+)
+
+// Predicates are told apart by their name and their arity.
+type predicateKey struct {
+	name  string
+	arity int
+}
+
+func keyOfPredicate(pred AST.Pred) predicateKey {
+	return predicateKey{pred.GetID().GetName(), pred.GetArgs().Len()}
+}
+
+// The equality and the defined predicates of TPTP (such as $less), whose names
+// start with $, have an interpretation: they are never pure.
+func isInterpreted(pred AST.Pred) bool {
+	return pred.GetID().Equals(AST.Id_eq) || strings.HasPrefix(pred.GetID().GetName(), "$")
+}
+
+func (p polarity) flip() polarity {
+	switch p {
+	case positive:
+		return negative
+	case negative:
+		return positive
+	}
+	return p
+}
+
+// Simplifies the statements, and removes the tautologies, the duplicate axioms
+// and the axioms made true by the pure predicates.
+func simplifyStatements(statements []Core.Statement) []Core.Statement {
+	simplified := []Core.Statement{}
+	tautologies, duplicates, pures := 0, 0, 0
+
+	for _, statement := range statements {
+		statement = simplifyStatement(statement)
+		if form, isAxiom := axiomForm(statement); isAxiom {
+			if _, isTop := form.(AST.Top); isTop {
+				tautologies++
+				continue
+			}
+			if isDuplicate(form, simplified) {
+				duplicates++
+				continue
+			}
+		}
+		simplified = append(simplified, statement)
+	}
+
+	// Removing an axiom may make other predicates pure.
+	for removed := true; removed; {
+		removed = false
+		values := purePredicates(simplified)
+		kept := []Core.Statement{}
+		for _, statement := range simplified {
+			if form, isAxiom := axiomForm(statement); isAxiom {
+				if _, isTop := simplifyConstants(assignPredicates(form, values)).(AST.Top); isTop {
+					pures++
+					removed = true
+					continue
+				}
+			}
+			kept = append(kept, statement)
+		}
+		simplified = kept
+	}
+
+	Glob.PrintInfo(
+		simplify_label,
+		fmt.Sprintf("%d statements removed out of %d (%d tautologies, %d duplicates, %d with pure predicates)",
+			tautologies+duplicates+pures, len(statements), tautologies, duplicates, pures),
+	)
+
+	return simplified
+}
+
+// The questions are left untouched, as well as the conjectures when their
+// answers are required, as they are read from their outermost quantifiers.
+func simplifyStatement(statement Core.Statement) Core.Statement {
+	form, hasForm := statement.GetForm().(Lib.Some[AST.Form])
+	switch {
+	case !hasForm, statement.GetRole() == Core.Question:
+		return statement
+	case statement.GetRole() == Core.Conjecture && Glob.GetAnswers():
+		return statement
+	}
+
+	simplified := simplifyConstants(form.Val)
+	if simplified.Equals(form.Val) {
+		return statement
+	}
+	return Core.MakeFormStatement(statement.GetName(), statement.GetRole(), simplified)
+}
+
+func axiomForm(statement Core.Statement) (AST.Form, bool) {
+	form, hasForm := statement.GetForm().(Lib.Some[AST.Form])
+	if !hasForm || statement.GetRole() != Core.Axiom {
+		return nil, false
+	}
+	return form.Val, true
+}
+
+func isDuplicate(form AST.Form, statements []Core.Statement) bool {
+	for _, statement := range statements {
+		if other, isAxiom := axiomForm(statement); isAxiom && Core.AlphaEquals(other, form) {
+			return true
+		}
+	}
+	return false
+}
+
+// The value that can be assumed for each pure predicate: true if it only occurs
+// positively, and false if it only occurs negatively. The conjectures occur
+// negatively in the problem.
+func purePredicates(statements []Core.Statement) map[predicateKey]bool {
+	polarities := make(map[predicateKey]polarity)
+	for _, statement := range statements {
+		form, hasForm := statement.GetForm().(Lib.Some[AST.Form])
+		if !hasForm {
+			continue
+		}
+
+		switch statement.GetRole() {
+		case Core.Axiom, Core.NegatedConjecture:
+			collectPolarities(form.Val, positive, polarities)
+		case Core.Conjecture, Core.Question:
+			collectPolarities(form.Val, negative, polarities)
+		}
+	}
+
+	values := make(map[predicateKey]bool)
+	for key, pol := range polarities {
+		if pol != positive|negative {
+			values[key] = pol == positive
+		}
+	}
+	return values
+}
+
+func collectPolarities(form AST.Form, pol polarity, polarities map[predicateKey]polarity) {
+	switch nf := form.(type) {
+	case AST.Pred:
+		if !isInterpreted(nf) {
+			polarities[keyOfPredicate(nf)] |= pol
+		}
+	case AST.Not:
+		collectPolarities(nf.GetForm(), pol.flip(), polarities)
+	case AST.Imp:
+		collectPolarities(nf.GetF1(), pol.flip(), polarities)
+		collectPolarities(nf.GetF2(), pol, polarities)
+	case AST.Equ:
+		for _, f := range nf.GetChildFormulas().Slice() {
+			collectPolarities(f, positive|negative, polarities)
+		}
+	default:
+		for _, f := range form.GetChildFormulas().Slice() {
+			collectPolarities(f, pol, polarities)
+		}
+	}
+}
+
+// Replaces the atoms of the predicates by their value.
+func assignPredicates(form AST.Form, values map[predicateKey]bool) AST.Form {
+	switch nf := form.(type) {
+	case AST.Pred:
+		value, found := values[keyOfPredicate(nf)]
+		switch {
+		case !found:
+			return form
+		case value:
+			return AST.MakerTop()
+		default:
+			return AST.MakerBot()
+		}
+	case AST.Not:
+		return AST.MakerNot(assignPredicates(nf.GetForm(), values))
+	case AST.And:
+		return AST.MakerAnd(assignPredicatesList(nf.FormList, values))
+	case AST.Or:
+		return AST.MakerOr(assignPredicatesList(nf.FormList, values))
+	case AST.Imp:
+		return AST.MakerImp(assignPredicates(nf.GetF1(), values), assignPredicates(nf.GetF2(), values))
+	case AST.Equ:
+		return AST.MakerEqu(assignPredicates(nf.GetF1(), values), assignPredicates(nf.GetF2(), values))
+	case AST.All:
+		return AST.MakerAll(nf.GetVarList(), assignPredicates(nf.GetForm(), values))
+	case AST.Ex:
+		return AST.MakerEx(nf.GetVarList(), assignPredicates(nf.GetForm(), values))
+	case AST.AllType:
+		return AST.MakerAllType(nf.GetVarList(), assignPredicates(nf.GetForm(), values))
+	}
+	return form
+}
+
+func assignPredicatesList(forms *AST.FormList, values map[predicateKey]bool) *AST.FormList {
+	result := AST.NewFormList()
+	for _, f := range forms.Slice() {
+		result.Append(assignPredicates(f, values))
+	}
+	return result
+}
+
+// Simplifies the $true and $false subformulas, the trivial equalities, and the
+// connectives applied on complementary or equal formulas.
+func simplifyConstants(form AST.Form) AST.Form {
+	switch nf := form.(type) {
+	case AST.Pred:
+		args := nf.GetArgs().GetSlice()
+		if nf.GetID().Equals(AST.Id_eq) && len(args) == 2 && args[0].Equals(args[1]) {
+			return AST.MakerTop()
+		}
+
+	case AST.Not:
+		switch inner := simplifyConstants(nf.GetForm()).(type) {
+		case AST.Top:
+			return AST.MakerBot()
+		case AST.Bot:
+			return AST.MakerTop()
+		default:
+			return AST.MakerNot(inner)
+		}
+
+	case AST.And:
+		return simplifyConnective(nf.FormList, AST.MakerTop(), AST.MakerBot(), func(fl *AST.FormList) AST.Form { return AST.MakerAnd(fl) })
+	case AST.Or:
+		return simplifyConnective(nf.FormList, AST.MakerBot(), AST.MakerTop(), func(fl *AST.FormList) AST.Form { return AST.MakerOr(fl) })
+
+	case AST.Imp:
+		f1, f2 := simplifyConstants(nf.GetF1()), simplifyConstants(nf.GetF2())
+		_, f1Top := f1.(AST.Top)
+		_, f1Bot := f1.(AST.Bot)
+		_, f2Top := f2.(AST.Top)
+		_, f2Bot := f2.(AST.Bot)
+		switch {
+		case f1Bot, f2Top, f1.Equals(f2):
+			return AST.MakerTop()
+		case f1Top:
+			return f2
+		case f2Bot:
+			return simplifyConstants(AST.MakerNot(f1))
+		}
+		return AST.MakerImp(f1, f2)
+
+	case AST.Equ:
+		f1, f2 := simplifyConstants(nf.GetF1()), simplifyConstants(nf.GetF2())
+		if f1.Equals(f2) {
+			return AST.MakerTop()
+		}
+		for _, sides := range [][2]AST.Form{{f1, f2}, {f2, f1}} {
+			switch sides[0].(type) {
+			case AST.Top:
+				return sides[1]
+			case AST.Bot:
+				return simplifyConstants(AST.MakerNot(sides[1]))
+			}
+		}
+		return AST.MakerEqu(f1, f2)
+
+	case AST.All:
+		inner := simplifyConstants(nf.GetForm())
+		if isConstant(inner) {
+			return inner
+		}
+		return AST.MakerAll(nf.GetVarList(), inner)
+	case AST.Ex:
+		inner := simplifyConstants(nf.GetForm())
+		if isConstant(inner) {
+			return inner
+		}
+		return AST.MakerEx(nf.GetVarList(), inner)
+	case AST.AllType:
+		inner := simplifyConstants(nf.GetForm())
+		if isConstant(inner) {
+			return inner
+		}
+		return AST.MakerAllType(nf.GetVarList(), inner)
+	}
+	return form
+}
+
+// Simplifies a conjunction (or a disjunction), whose neutral element is unit
+// and absorbing element is zero.
+func simplifyConnective(forms *AST.FormList, unit, zero AST.Form, maker func(*AST.FormList) AST.Form) AST.Form {
+	result := AST.NewFormList()
+	for _, f := range forms.Slice() {
+		f = simplifyConstants(f)
+		switch {
+		case f.Equals(zero):
+			return zero
+		case f.Equals(unit):
+			continue
+		case result.Contains(AST.MakerNot(f)):
+			return zero
+		}
+		if not, isNot := f.(AST.Not); isNot && result.Contains(not.GetForm()) {
+			return zero
+		}
+		result.AppendIfNotContains(f)
+	}
+
+	switch result.Len() {
+	case 0:
+		return unit
+	case 1:
+		return result.Get(0)
+	}
+	return maker(result)
+}
+
+func isConstant(form AST.Form) bool {
+	switch form.(type) {
+	case AST.Top, AST.Bot:
+		return true
+	}
+	return false
+}
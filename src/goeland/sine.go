@@ -0,0 +1,174 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the SInE premise selection: only the axioms that are
+* reachable from the symbols of the conjecture through the trigger relation
+* are kept.
+**/
+
+package goeland
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/GoelandProver/Goeland/AST"
+	"github.com/GoelandProver/Goeland/Core"
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+)
+
+const sine_label = "SInE"
+
+// Keeps the statements that are not axioms, the axioms without symbols, and the
+// axioms triggered, at most depth times (without limit if it is 0), from the
+// symbols of the conjectures.
+// A symbol triggers an axiom when it occurs in at most tolerance times as many
+// axioms as the least common symbol of the axiom.
+func selectPremises(statements []Core.Statement, tolerance float64, depth int) []Core.Statement {
+	symbols := make([]map[string]bool, len(statements))
+	occurrences := make(map[string]int)
+	frontier := make(map[string]bool)
+	axiomCount := 0
+
+	for i, statement := range statements {
+		form, hasForm := statement.GetForm().(Lib.Some[AST.Form])
+		if !hasForm {
+			continue
+		}
+
+		symbols[i] = formSymbols(form.Val)
+		switch statement.GetRole() {
+		case Core.Axiom:
+			axiomCount++
+			for symbol := range symbols[i] {
+				occurrences[symbol]++
+			}
+		case Core.Conjecture, Core.Question, Core.NegatedConjecture:
+			for symbol := range symbols[i] {
+				frontier[symbol] = true
+			}
+		}
+	}
+
+	if len(frontier) == 0 {
+		Glob.PrintInfo(sine_label, "No conjecture, all the axioms are kept")
+		return statements
+	}
+
+	triggers := make(map[string][]int)
+	for i, statement := range statements {
+		if statement.GetRole() != Core.Axiom || symbols[i] == nil {
+			continue
+		}
+
+		rarest := math.MaxInt
+		for symbol := range symbols[i] {
+			rarest = min(rarest, occurrences[symbol])
+		}
+		for symbol := range symbols[i] {
+			if float64(occurrences[symbol]) <= tolerance*float64(rarest) {
+				triggers[symbol] = append(triggers[symbol], i)
+			}
+		}
+	}
+
+	// The axioms without symbols, e.g., the ones made of equalities between
+	// variables, are never triggered: they are always kept.
+	selected := make([]bool, len(statements))
+	for i, statement := range statements {
+		selected[i] = statement.GetRole() == Core.Axiom && symbols[i] != nil && len(symbols[i]) == 0
+	}
+	reached := make(map[string]bool)
+	steps := 0
+	for ; len(frontier) > 0 && (depth <= 0 || steps < depth); steps++ {
+		next := make(map[string]bool)
+		for symbol := range frontier {
+			reached[symbol] = true
+			for _, i := range triggers[symbol] {
+				if selected[i] {
+					continue
+				}
+				selected[i] = true
+				for s := range symbols[i] {
+					if !reached[s] && !frontier[s] {
+						next[s] = true
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+
+	kept := []Core.Statement{}
+	selectedCount := 0
+	for i, statement := range statements {
+		switch {
+		case statement.GetRole() != Core.Axiom:
+			kept = append(kept, statement)
+		case selected[i]:
+			kept = append(kept, statement)
+			selectedCount++
+		}
+	}
+
+	Glob.PrintInfo(
+		sine_label,
+		fmt.Sprintf("%d axioms selected out of %d (%d symbols reached in %d steps, tolerance %v)",
+			selectedCount, axiomCount, len(reached), steps, tolerance),
+	)
+	Glob.SetPremisesDropped(selectedCount < axiomCount)
+
+	return kept
+}
+
+// The predicate and function symbols of a formula, but the equality.
+func formSymbols(form AST.Form) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, f := range form.GetSubFormulasRecur().Slice() {
+		pred, isPred := f.(AST.Pred)
+		if !isPred {
+			continue
+		}
+
+		if !pred.GetID().Equals(AST.Id_eq) {
+			symbols[pred.GetID().GetName()] = true
+		}
+		for _, term := range pred.GetSubTerms().GetSlice() {
+			if fun, isFun := term.(AST.Fun); isFun {
+				symbols[fun.GetName()] = true
+			}
+		}
+	}
+	return symbols
+}
@@ -37,7 +37,6 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -48,15 +47,13 @@ import (
 	_ "net/http/pprof"
 
 	"github.com/GoelandProver/Goeland/AST"
-	"github.com/GoelandProver/Goeland/Core"
-	"github.com/GoelandProver/Goeland/Engine"
 	"github.com/GoelandProver/Goeland/Glob"
 	"github.com/GoelandProver/Goeland/Lib"
 	"github.com/GoelandProver/Goeland/Mods/assisted"
-	"github.com/GoelandProver/Goeland/Mods/dmt"
+	"github.com/GoelandProver/Goeland/Mods/tptp"
 	"github.com/GoelandProver/Goeland/Parser"
 	"github.com/GoelandProver/Goeland/Search"
-	"github.com/GoelandProver/Goeland/Typing"
+	"github.com/GoelandProver/Goeland/goeland"
 )
 
 var chAssistant chan bool = make(chan bool)
@@ -73,6 +70,19 @@ func main() {
 		return
 	}
 
+	if Glob.GetCheckSCTPTP() != "" {
+		checkSCTPTPFile(Glob.GetCheckSCTPTP())
+		return
+	}
+
+	if Glob.GetBatch() != "" {
+		os.Exit(runBatch(Glob.GetBatch()))
+	}
+
+	if Glob.GetSchedule() != "" {
+		os.Exit(runSchedule(os.Args[len(os.Args)-1]))
+	}
+
 	form, bound := presearchLoader()
 
 	// This block cannot be removed from the main function, as it breaks how the CPU profiler works
@@ -92,6 +102,20 @@ func main() {
 	startSearch(form, bound)
 
 	doMemProfile()
+
+	// os.Exit does not run the deferred calls.
+	if code := Search.GetExitCode(); code != Search.ExitSolved {
+		pprof.StopCPUProfile()
+		os.Exit(code)
+	}
+}
+
+// Checks an SC-TPTP proof file, and exits with an error if it is not valid.
+func checkSCTPTPFile(file string) {
+	if err := tptp.CheckSCTPTPProof(Parser.ParseSCTPTPFile(file)); err != nil {
+		Glob.Fatal("Check", fmt.Sprintf("The SC-TPTP proof %s is not valid, %s", file, err.Error()))
+	}
+	fmt.Printf("%% The SC-TPTP proof %s has been checked\n", file)
 }
 
 // Start solving
@@ -118,43 +142,28 @@ func startSearch(form AST.Form, bound int) {
 // Initialization
 func presearchLoader() (AST.Form, int) {
 	problem := os.Args[len(os.Args)-1]
-	Glob.SetProblemName(path.Base(problem))
+	Glob.SetProblemName(problemName(problem))
 
 	fmt.Printf("You are running Goeland v.%v\n", Glob.GetVersion())
 	fmt.Printf("Problem: %v\n", Glob.GetProblemName())
 
 	Glob.PrintInfo(main_label, fmt.Sprintf("Problem : %v", problem))
 
-	statements, bound, containsEquality := Parser.ParseTPTPFile(problem)
-	actualStatements := Engine.ToInternalSyntax(statements)
-
-	Glob.PrintDebug(
-		main_label,
-		Lib.MkLazy(func() string {
-			return fmt.Sprintf(
-				"Statement : %s", Core.StatementListToString(actualStatements))
-		}),
-	)
-
-	if Glob.GetLimit() != -1 {
-		bound = Glob.GetLimit()
+	form, bound, err := goeland.LoadProblem(problem)
+	if err != nil {
+		Glob.Fatal(main_label, err.Error())
 	}
 
-	form, bound, contEq := StatementListToFormula(actualStatements, bound, path.Dir(problem))
-	containsEquality = containsEquality || contEq
-
-	if !containsEquality {
-		Glob.SetPlugin("equality", false)
-		Glob.PrintInfo("EQU", "Plugin Equality disabled")
-	}
+	return form, bound
+}
 
-	if form == nil {
-		Glob.Fatal(main_label, "Problem not found")
+// The name of the problem in the outputs, "stdin" for a problem read on the
+// standard input.
+func problemName(problem string) string {
+	if problem == Parser.StdinName {
+		return "stdin"
 	}
-
-	form = checkForTypedProof(form)
-
-	return form, bound
+	return path.Base(problem)
 }
 
 func doMemProfile() {
@@ -180,180 +189,3 @@ func initEverything() {
 	runtime.GOMAXPROCS(Glob.GetCoreLimit())
 	AST.Init()
 }
-
-// FIXME: eventually, we would want to add an "interpretation" layer between elab and internal representation that does this
-func StatementListToFormula(statements []Core.Statement, old_bound int, problemDir string) (form AST.Form, bound int, containsEquality bool) {
-	and_list := AST.NewFormList()
-	var not_form AST.Form
-	bound = old_bound
-
-	for _, statement := range statements {
-		switch statement.GetRole() {
-		case Core.Include:
-			file_name := statement.GetName()
-
-			realname, err := getFile(file_name, problemDir)
-			Glob.PrintDebug(
-				main_label,
-				Lib.MkLazy(func() string { return fmt.Sprintf("File to parse : %s\n", realname) }),
-			)
-
-			if err != nil {
-				Glob.PrintError(main_label, err.Error())
-				return nil, -1, false
-			}
-
-			new_lstm, bound_tmp, contEq := Parser.ParseTPTPFile(realname)
-			actual_new_lstm := Engine.ToInternalSyntax(new_lstm)
-			containsEquality = containsEquality || contEq
-			new_form_list, new_bound, contEq := StatementListToFormula(
-				actual_new_lstm,
-				bound_tmp,
-				path.Join(problemDir, path.Dir(file_name)),
-			)
-			containsEquality = containsEquality || contEq
-
-			if new_form_list != nil {
-				bound = new_bound
-				and_list.Append(new_form_list)
-			}
-
-		case Core.Axiom:
-			switch f := statement.GetForm().(type) {
-			case Lib.Some[AST.Form]:
-				and_list = doAxiomStatement(and_list, f.Val)
-			case Lib.None[AST.Form]:
-				Glob.Anomaly("main", "Axiom statement "+statement.ToString()+" has no formula")
-			}
-
-		case Core.Conjecture:
-			switch f := statement.GetForm().(type) {
-			case Lib.Some[AST.Form]:
-				not_form = doConjectureStatement(f.Val)
-			case Lib.None[AST.Form]:
-				Glob.Anomaly("main", "Conjecture statement "+statement.ToString()+" has no formula")
-			}
-
-		case Core.Type:
-			switch ty := statement.GetAtomTyping().(type) {
-			case Lib.Some[Core.TFFAtomTyping]:
-				doTypeStatement(ty.Val)
-			case Lib.None[Core.TFFAtomTyping]:
-				Glob.Anomaly("main", "Type statement "+statement.ToString()+" has no formula")
-			}
-		}
-	}
-
-	switch {
-	case and_list.IsEmpty() && not_form == nil:
-		return nil, bound, containsEquality
-	case and_list.IsEmpty():
-		return AST.MakerNot(not_form), bound, containsEquality
-	case not_form == nil:
-		return AST.MakerAnd(and_list), bound, containsEquality
-	default:
-		flattened := and_list.Flatten()
-		flattened.Append(AST.MakerNot(not_form))
-		return AST.MakerAnd(flattened), bound, containsEquality
-	}
-}
-
-func doAxiomStatement(andList *AST.FormList, f AST.Form) *AST.FormList {
-	newForm := f.RenameVariables()
-
-	// FIXME: dmt should be a plugin and therefore not checked here.
-	// Ideally, we want to be able to define a hook here and let the plugins do
-	// whatever they want, returning only whether they consumed the axiom or
-	// not. It would also avoid duplicated code.
-	if !Glob.IsLoaded("dmt") {
-		andList.Append(newForm)
-		return andList
-	}
-
-	consumed := dmt.RegisterAxiom(newForm.Copy())
-	if !consumed {
-		andList.Append(newForm)
-		return andList
-	}
-
-	return andList
-}
-
-func doConjectureStatement(f AST.Form) AST.Form {
-	Glob.SetConjecture(true)
-	return f.RenameVariables()
-}
-
-func doTypeStatement(atomTyping Core.TFFAtomTyping) {
-	typeScheme := atomTyping.Ts
-
-	if typeScheme == nil {
-		Glob.PrintWarn("main", fmt.Sprintf("The constant %s has no type!", atomTyping.Literal.ToString()))
-		return
-	}
-
-	if typeScheme.Size() == 1 {
-		isNewType := typeScheme.ToString() == "$tType"
-		if isNewType {
-			AST.MkTypeHint(atomTyping.Literal.GetName())
-		} else {
-			isConstant := !Glob.Is[AST.QuantifiedType](typeScheme)
-			if isConstant {
-				AST.SaveConstant(atomTyping.Literal.GetName(), typeScheme.GetPrimitives()[0])
-			} else {
-				AST.SavePolymorphScheme(atomTyping.Literal.GetName(), typeScheme)
-			}
-		}
-	} else {
-		switch typeScheme.(type) {
-		case AST.TypeArrow:
-			AST.SaveTypeScheme(atomTyping.Literal.GetName(), AST.GetInputType(typeScheme)[0], AST.GetOutType(typeScheme))
-		case AST.QuantifiedType:
-			AST.SavePolymorphScheme(atomTyping.Literal.GetName(), typeScheme)
-		}
-	}
-}
-
-func getFile(filename string, dir string) (string, error) {
-	fileExists := func(err error) bool {
-		return err == nil && !errors.Is(err, os.ErrNotExist)
-	}
-	// Check in Goéland's path
-	_, err := os.Stat(filename)
-	if fileExists(err) {
-		return filename, err
-	}
-
-	// Check in the dir's path
-	otherFilename := path.Join(dir, filename)
-	_, err = os.Stat(otherFilename)
-	if fileExists(err) {
-		return otherFilename, err
-	}
-
-	// Check in the environment variable
-	directory := os.Getenv("TPTP")
-	otherFilename = path.Join(directory, filename)
-	_, err = os.Stat(otherFilename)
-	if fileExists(err) {
-		return otherFilename, err
-	}
-
-	return "", fmt.Errorf("file %s not found", filename)
-}
-
-func checkForTypedProof(form AST.Form) AST.Form {
-	isTypedProof := !AST.EmptyGlobalContext() && !Glob.NoTypeCheck()
-
-	if isTypedProof {
-		err := Typing.WellFormedVerification(form.Copy(), Glob.GetTypeProof())
-
-		if err != nil {
-			Glob.Fatal(main_label, fmt.Sprintf("Typing error: %v", err))
-		} else {
-			Glob.PrintInfo(main_label, "Well typed.")
-		}
-	}
-
-	return form
-}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/GoelandProver/Goeland/AST"
@@ -10,15 +13,19 @@ import (
 	"github.com/GoelandProver/Goeland/Core/Sko"
 	"github.com/GoelandProver/Goeland/Glob"
 	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Mods/arith"
 	"github.com/GoelandProver/Goeland/Mods/assisted"
 	"github.com/GoelandProver/Goeland/Mods/coq"
 	"github.com/GoelandProver/Goeland/Mods/dmt"
 	equality "github.com/GoelandProver/Goeland/Mods/equality/bse"
 	"github.com/GoelandProver/Goeland/Mods/equality/sateq"
 	"github.com/GoelandProver/Goeland/Mods/gs3"
+	"github.com/GoelandProver/Goeland/Mods/isabelle"
 	"github.com/GoelandProver/Goeland/Mods/lambdapi"
+	"github.com/GoelandProver/Goeland/Mods/lean"
 	"github.com/GoelandProver/Goeland/Mods/tptp"
 	"github.com/GoelandProver/Goeland/Search"
+	"github.com/GoelandProver/Goeland/Search/connection"
 	"github.com/GoelandProver/Goeland/Search/incremental"
 )
 
@@ -243,7 +250,10 @@ func buildOptions() {
 		"ari",
 		false,
 		"Enables the use of (TPTP) arithmetic functions",
-		func(bool) { Glob.SetArithModule(true) },
+		func(bool) {
+			Glob.SetArithModule(true)
+			arith.Enable()
+		},
 		func(bool) {})
 	(&option[int]{}).init(
 		"core_limit",
@@ -251,12 +261,78 @@ func buildOptions() {
 		"Sets the limit in number of cores (default: all)",
 		func(nb int) { Glob.SetCoreLimit(nb) },
 		func(int) {})
+	(&option[int]{}).init(
+		"timeout",
+		-1,
+		"Sets a wall-clock time limit in seconds (default: none)",
+		func(seconds int) { Glob.SetTimeout(seconds) },
+		func(int) {})
+	(&option[int]{}).init(
+		"max_goroutines",
+		-1,
+		"Stops the search with the ResourceOut status when more goroutines are running (default: no limit)",
+		func(nb int) { Glob.SetMaxGoroutines(nb) },
+		func(int) {})
+	(&option[int]{}).init(
+		"max_memory",
+		-1,
+		"Stops the search with the ResourceOut status when more memory is in use, in MiB (default: no limit)",
+		func(mib int) { Glob.SetMaxMemory(mib) },
+		func(int) {})
+	(&option[string]{}).init(
+		"reintroduction",
+		"uniform",
+		"Chooses the formulas to reintroduce by the given `policy`: uniform, depth (less for the formulas with deeper terms) or closures (more for the formulas whose instances close more branches)",
+		func(policy string) { setReintroductionPolicy(policy) },
+		func(string) {})
+	(&option[string]{}).init(
+		"selection",
+		"first",
+		"Chooses the formula to expand first by the given `heuristic`: first, branches (the beta formulas with the fewest branches), connection (the beta formulas with a literal complementary to the branch) or size (the smallest formulas)",
+		func(selector string) { setFormulaSelector(selector) },
+		func(string) {})
+	(&option[string]{}).init(
+		"sine",
+		"",
+		"Only keeps the axioms that SInE selects from the symbols of the conjecture, given `tolerance,depth` (e.g. 1.5,3, the depth being unlimited if it is 0 or omitted)",
+		func(parameters string) { Glob.SetSine(parseSine(parameters)) },
+		func(string) {})
+	(&option[string]{}).init(
+		"schedule",
+		"",
+		"Runs the option sets listed in `file` (one per line, each preceded by a time slice in seconds) until one of them finds a result",
+		func(file string) { Glob.SetSchedule(file) },
+		func(string) {})
+	(&option[bool]{}).init(
+		"schedule_parallel",
+		false,
+		"Runs the option sets of the schedule in parallel, at most -core_limit at a time",
+		func(bool) { Glob.SetScheduleParallel(true) },
+		func(bool) {})
+	(&option[string]{}).init(
+		"batch",
+		"",
+		"Runs every problem of the directory or of the list `file` (one per line) and reports one line per problem. The -timeout applies to each problem",
+		func(problems string) { Glob.SetBatch(problems) },
+		func(string) {})
+	(&option[string]{}).init(
+		"batch_format",
+		"csv",
+		"Sets the format of the -batch report (csv or json)",
+		func(string) {},
+		func(format string) { Glob.SetBatchFormat(format) })
 	(&option[bool]{}).init(
 		"completeness",
 		false,
 		"Enables completeness mode",
 		func(bool) { Glob.SetCompleteness(true) },
 		func(bool) {})
+	(&option[bool]{}).init(
+		"answers",
+		false,
+		"Prints the SZS answers of an existential conjecture when it is proven (always done for a question)",
+		func(bool) { Glob.SetAnswers(true) },
+		func(bool) {})
 	(&option[bool]{}).init(
 		"ocoq",
 		false,
@@ -299,11 +375,66 @@ func buildOptions() {
 			Search.AddPrintProofAlgorithm(lambdapi.LambdapiOutputProofStruct)
 		},
 		func(bool) {})
+	(&option[bool]{}).init(
+		"oisabelle",
+		false,
+		"Enables the Isabelle/Isar format for proofs instead of text",
+		func(bool) {
+			Glob.SetProof(true)
+			Search.AddPrintProofAlgorithm(isabelle.IsabelleOutputProofStruct)
+		},
+		func(bool) {})
+	(&option[bool]{}).init(
+		"olean",
+		false,
+		"Enables the Lean 4 format for proofs instead of text",
+		func(bool) {
+			Glob.SetProof(true)
+			Search.AddPrintProofAlgorithm(lean.LeanOutputProofStruct)
+		},
+		func(bool) {})
+	(&option[bool]{}).init(
+		"check",
+		false,
+		"Checks the proof that is found, and fails with the node of the first invalid rule application",
+		func(bool) {
+			Glob.SetProof(true)
+			Search.AddProofChecker(gs3.CheckSearchProof)
+		},
+		func(bool) {})
+	(&option[string]{}).init(
+		"check_sctptp",
+		"",
+		"Checks the SC-TPTP proof in `file` (- for the standard input), and fails with the first invalid step. No problem is expected",
+		func(file string) { Glob.SetCheckSCTPTP(file) },
+		func(string) {})
+	(&option[bool]{}).init(
+		"minimize",
+		false,
+		"Drops the steps and the axioms that no closure depends on from the proof, and prints the axioms that are used",
+		func(bool) {
+			Glob.SetProof(true)
+			Glob.SetMinimizeProof(true)
+		},
+		func(bool) {})
+	(&option[bool]{}).init(
+		"core_axioms",
+		false,
+		"Prints the names of the axioms that the proof uses, one per line, between SZS output delimiters",
+		func(bool) {
+			Glob.SetProof(true)
+			Glob.SetCoreAxioms(true)
+		},
+		func(bool) {})
 	(&option[bool]{}).init(
 		"context",
 		false,
-		"Should only be used with the -ocoq or the -olp parameters. Enables the context for a standalone execution",
-		func(bool) { coq.SetContextEnabled(true) },
+		"Should only be used with the -ocoq, the -olp, the -oisabelle or the -olean parameters. Enables the context for a standalone execution",
+		func(bool) {
+			coq.SetContextEnabled(true)
+			isabelle.SetContextEnabled(true)
+			lean.SetContextEnabled(true)
+		},
 		func(bool) {})
 	(&option[bool]{}).init(
 		"inner",
@@ -373,6 +504,14 @@ func buildOptions() {
 			Search.SetSearchAlgorithm(incremental.NewIncrementalSearch())
 		},
 		func(bool) {})
+	(&option[bool]{}).init(
+		"connection",
+		false,
+		"Enables the connection search algorithm, which refutes the clausal form of the problem",
+		func(bool) {
+			Search.SetSearchAlgorithm(connection.NewConnectionSearch())
+		},
+		func(bool) {})
 	(&option[string]{}).init(
 		"proof_file",
 		"problem_proof",
@@ -416,6 +555,30 @@ func buildOptions() {
 		"Flatten the or & and formulas at parsing",
 		func(bool) { Glob.SetFlatteningAllowed() },
 		func(bool) {})
+	(&option[int]{}).init(
+		"definitional",
+		0,
+		"Names the subformulas larger than the given size by fresh predicates at parsing (default: none)",
+		func(size int) { Glob.SetDefinitionalSize(max(size, 0)) },
+		func(int) {})
+	(&option[bool]{}).init(
+		"miniscope",
+		false,
+		"Pushes the quantifiers inward as far as possible before the search",
+		func(bool) { Glob.SetMiniscope(true) },
+		func(bool) {})
+	(&option[bool]{}).init(
+		"simplify",
+		false,
+		"Removes the tautologies, the duplicate axioms and the axioms made true by the pure predicates, and simplifies $true and $false, before the search",
+		func(bool) { Glob.SetSimplify(true) },
+		func(bool) {})
+	(&option[bool]{}).init(
+		"regularity",
+		false,
+		"Never adds a formula twice to a branch of the destructive search, up to the substitutions applied on it, and skips the beta rules having a branch already on the branch",
+		func(bool) { Glob.SetRegularity(true) },
+		func(bool) {})
 	(&option[bool]{}).init(
 		"no-type-check",
 		false,
@@ -424,6 +587,44 @@ func buildOptions() {
 		func(bool) {})
 }
 
+// Parses the "tolerance,depth" parameters of SInE.
+func parseSine(parameters string) (float64, int) {
+	toleranceStr, depthStr, hasDepth := strings.Cut(parameters, ",")
+
+	tolerance, err := strconv.ParseFloat(strings.TrimSpace(toleranceStr), 64)
+	if err != nil || tolerance < 1 {
+		Glob.Fatal(main_label, fmt.Sprintf("Invalid SInE tolerance %s (expected a number greater than or equal to 1)", toleranceStr))
+	}
+
+	depth := 0
+	if hasDepth {
+		depth, err = strconv.Atoi(strings.TrimSpace(depthStr))
+		if err != nil || depth < 0 {
+			Glob.Fatal(main_label, fmt.Sprintf("Invalid SInE depth %s (expected a non-negative integer)", depthStr))
+		}
+	}
+
+	return tolerance, depth
+}
+
+func setReintroductionPolicy(name string) {
+	policy, err := Search.MakeReintroductionPolicy(name)
+	if err != nil {
+		Glob.Fatal(main_label, err.Error())
+	}
+	Glob.SetReintroduction(name)
+	Search.SetReintroductionPolicy(policy)
+}
+
+func setFormulaSelector(name string) {
+	selector, err := Search.MakeFormulaSelector(name)
+	if err != nil {
+		Glob.Fatal(main_label, err.Error())
+	}
+	Glob.SetSelection(name)
+	Search.SetFormulaSelector(selector)
+}
+
 func chronoInit() {
 	oldCoq := coq.MakeCoqProof
 	coq.MakeCoqProof = func(proof *gs3.GS3Sequent, meta Lib.List[AST.Meta]) string {
@@ -0,0 +1,356 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+* This file implements the strategy scheduling: the option sets of a schedule
+* file are tried on the problem, each one in its own Goéland process, until
+* one of them finds a result.
+**/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoelandProver/Goeland/Glob"
+	"github.com/GoelandProver/Goeland/Lib"
+	"github.com/GoelandProver/Goeland/Parser"
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+var schedule_label = "Schedule"
+
+type scheduleEntry struct {
+	slice   int
+	options []string
+}
+
+func (entry scheduleEntry) toString() string {
+	if len(entry.options) == 0 {
+		return fmt.Sprintf("[%ds] (default options)", entry.slice)
+	}
+	return fmt.Sprintf("[%ds] %s", entry.slice, strings.Join(entry.options, " "))
+}
+
+type scheduleAttempt struct {
+	index  int
+	status string
+	output []byte
+}
+
+// The problem read on the standard input, given in turn to every process of
+// the schedule.
+var scheduleStdin []byte
+
+var szsStatusRegexp = regexp.MustCompile(`% SZS status (\w+)`)
+
+// Options that are set by the schedule itself and must not be given to the
+// processes it launches.
+var scheduleOwnOptions = map[string]bool{"schedule": true, "schedule_parallel": true, "timeout": true}
+
+// Reads a schedule file. Each line contains a time slice in seconds followed by
+// options of Goéland. Empty lines and lines starting with % or # are ignored.
+func readSchedule(file string) []scheduleEntry {
+	f, err := os.Open(file)
+	if err != nil {
+		Glob.Fatal(schedule_label, fmt.Sprintf("Could not open the schedule: %v", err))
+	}
+	defer f.Close()
+
+	entries := []scheduleEntry{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "%") || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		slice, err := strconv.Atoi(fields[0])
+		if err != nil || slice <= 0 {
+			Glob.Fatal(schedule_label, fmt.Sprintf("%s:%d: the line should start with a positive time slice", file, line))
+		}
+
+		for _, option := range fields[1:] {
+			if !strings.HasPrefix(option, "-") {
+				continue
+			}
+			name := strings.SplitN(strings.TrimLeft(option, "-"), "=", 2)[0]
+			if flag.Lookup(name) == nil || scheduleOwnOptions[name] {
+				Glob.Fatal(schedule_label, fmt.Sprintf("%s:%d: unexpected option %s", file, line, option))
+			}
+		}
+
+		entries = append(entries, scheduleEntry{slice, fields[1:]})
+	}
+
+	if err := scanner.Err(); err != nil {
+		Glob.Fatal(schedule_label, fmt.Sprintf("Could not read the schedule: %v", err))
+	}
+	if len(entries) == 0 {
+		Glob.Fatal(schedule_label, fmt.Sprintf("The schedule %s is empty", file))
+	}
+
+	return entries
+}
+
+// The options of the command line, except the schedule ones and the problem,
+// that are given to every process of the schedule.
+func forwardedArgs() []string {
+	args := []string{}
+	cmdArgs := os.Args[1 : len(os.Args)-1]
+
+	for i := 0; i < len(cmdArgs); i++ {
+		name, hasValue := optionName(cmdArgs[i])
+		// The value of the option, if it is given as the next argument.
+		valueArgs := 0
+		if !hasValue && takesValue(name) && i+1 < len(cmdArgs) && !isOption(cmdArgs[i+1]) {
+			valueArgs = 1
+		}
+
+		if !scheduleOwnOptions[name] {
+			args = append(args, cmdArgs[i:i+1+valueArgs]...)
+		}
+		i += valueArgs
+	}
+
+	return args
+}
+
+// Returns the name of the option given by an argument of the command line, and
+// whether its value is given in the argument itself (-name=value).
+func optionName(arg string) (string, bool) {
+	nameAndValue := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)
+	return nameAndValue[0], len(nameAndValue) == 2
+}
+
+// Whether the argument is an option of Goéland, and not a value such as -1.
+func isOption(arg string) bool {
+	name, _ := optionName(arg)
+	return strings.HasPrefix(arg, "-") && flag.Lookup(name) != nil
+}
+
+// Whether the option expects a value, i.e., it is not a boolean option.
+func takesValue(name string) bool {
+	f := flag.Lookup(name)
+	if f == nil {
+		return false
+	}
+	boolFlag, isBoolFlag := f.Value.(interface{ IsBoolFlag() bool })
+	return !isBoolFlag || !boolFlag.IsBoolFlag()
+}
+
+// Runs the schedule on the problem and returns the exit code of Goéland.
+func runSchedule(problem string) int {
+	Glob.SetProblemName(problemName(problem))
+	entries := readSchedule(Glob.GetSchedule())
+
+	if problem == Parser.StdinName {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			Glob.Fatal(schedule_label, fmt.Sprintf("Could not read the standard input: %v", err))
+		}
+		scheduleStdin = input
+	}
+
+	mode := "sequential"
+	if Glob.IsScheduleParallel() {
+		mode = "parallel"
+	}
+	fmt.Printf("%% Schedule: %s (%d option sets, %s)\n", Glob.GetSchedule(), len(entries), mode)
+
+	var winner Lib.Option[scheduleAttempt]
+	if Glob.IsScheduleParallel() {
+		winner = runScheduleParallel(entries, problem)
+	} else {
+		winner = runScheduleSequential(entries, problem)
+	}
+
+	switch attempt := winner.(type) {
+	case Lib.Some[scheduleAttempt]:
+		fmt.Printf("%% Schedule: solved by option set %d\n", attempt.Val.index+1)
+		os.Stdout.Write(attempt.Val.output)
+	case Lib.None[scheduleAttempt]:
+		if remainingTime() == 0 {
+			Search.PrintNoResult(Search.TimeoutStatus)
+		} else {
+			Search.PrintNoResult(Search.GaveUpStatus)
+		}
+	}
+
+	return Search.GetExitCode()
+}
+
+func runScheduleSequential(entries []scheduleEntry, problem string) Lib.Option[scheduleAttempt] {
+	for i, entry := range entries {
+		if remainingTime() == 0 {
+			break
+		}
+
+		attempt := runScheduleEntry(context.Background(), i, entry, problem)
+		reportAttempt(attempt, entries)
+		if isConclusive(attempt.status) {
+			return Lib.MkSome(attempt)
+		}
+	}
+	return Lib.MkNone[scheduleAttempt]()
+}
+
+// Runs the entries at most -core_limit at a time, and stops the remaining ones
+// as soon as a result is found.
+func runScheduleParallel(entries []scheduleEntry, problem string) Lib.Option[scheduleAttempt] {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	maxProcesses := Glob.GetCoreLimit()
+	if maxProcesses <= 0 {
+		maxProcesses = runtime.NumCPU()
+	}
+
+	slots := make(chan bool, maxProcesses)
+	attempts := make(chan scheduleAttempt)
+	var wg sync.WaitGroup
+
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry scheduleEntry) {
+			defer wg.Done()
+			slots <- true
+			defer func() { <-slots }()
+			if ctx.Err() == nil {
+				attempts <- runScheduleEntry(ctx, i, entry, problem)
+			}
+		}(i, entry)
+	}
+
+	go func() {
+		wg.Wait()
+		close(attempts)
+	}()
+
+	winner := Lib.MkNone[scheduleAttempt]()
+	for attempt := range attempts {
+		if _, found := winner.(Lib.Some[scheduleAttempt]); found {
+			continue
+		}
+		reportAttempt(attempt, entries)
+		if isConclusive(attempt.status) {
+			winner = Lib.MkSome(attempt)
+			cancel()
+		}
+	}
+	return winner
+}
+
+// Runs Goéland on the problem with the options of the entry, during its time
+// slice or the remaining time if it is shorter.
+func runScheduleEntry(ctx context.Context, index int, entry scheduleEntry, problem string) scheduleAttempt {
+	slice := entry.slice
+	if remaining := remainingTime(); remaining != -1 && remaining < slice {
+		slice = remaining
+	}
+
+	args := append(forwardedArgs(), entry.options...)
+	args = append(args, "-timeout", strconv.Itoa(slice), problem)
+
+	// The process stops by itself at the end of its slice, this is a safeguard.
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(slice+1)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, os.Args[0], args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if problem == Parser.StdinName {
+		cmd.Stdin = bytes.NewReader(scheduleStdin)
+	}
+
+	Glob.PrintInfo(schedule_label, fmt.Sprintf("Launching %s", strings.Join(args, " ")))
+	err := cmd.Run()
+
+	status := "Error"
+	if match := szsStatusRegexp.FindSubmatch(output.Bytes()); match != nil {
+		status = string(match[1])
+	} else if ctx.Err() != nil {
+		status = Search.TimeoutStatus
+	} else if err != nil {
+		Glob.PrintWarn(schedule_label, fmt.Sprintf("Option set %d failed: %v", index+1, err))
+	}
+
+	return scheduleAttempt{index, status, output.Bytes()}
+}
+
+func reportAttempt(attempt scheduleAttempt, entries []scheduleEntry) {
+	fmt.Printf(
+		"%% Schedule entry %d/%d %s: %s\n",
+		attempt.index+1,
+		len(entries),
+		entries[attempt.index].toString(),
+		attempt.status,
+	)
+}
+
+// A satisfiable status ends the schedule as well: an option set only gives it
+// when its search is exhaustive or finds a saturated open branch, and gives up
+// otherwise.
+func isConclusive(status string) bool {
+	switch status {
+	case "Theorem", "Unsatisfiable", "CounterSatisfiable", "Satisfiable":
+		return true
+	}
+	return false
+}
+
+// Returns the remaining time of the schedule in seconds, -1 if there is no
+// time limit.
+func remainingTime() int {
+	if Glob.GetTimeout() == -1 {
+		return -1
+	}
+	elapsed := int(time.Since(Glob.GetStart()).Seconds())
+	if elapsed >= Glob.GetTimeout() {
+		return 0
+	}
+	return Glob.GetTimeout() - elapsed
+}
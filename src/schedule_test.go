@@ -0,0 +1,163 @@
+/**
+* Copyright 2022 by the authors (see AUTHORS).
+*
+* Goéland is an automated theorem prover for first order logic.
+*
+* This software is governed by the CeCILL license under French law and
+* abiding by the rules of distribution of free software.  You can  use,
+* modify and/ or redistribute the software under the terms of the CeCILL
+* license as circulated by CEA, CNRS and INRIA at the following URL
+* "http://www.cecill.info".
+*
+* As a counterpart to the access to the source code and  rights to copy,
+* modify and redistribute granted by the license, users are provided only
+* with a limited warranty  and the software's author,  the holder of the
+* economic rights,  and the successive licensors  have only  limited
+* liability.
+*
+* In this respect, the user's attention is drawn to the risks associated
+* with loading,  using,  modifying and/or developing or reproducing the
+* software by the user in light of its specific status of free software,
+* that may mean  that it is complicated to manipulate,  and  that  also
+* therefore means  that it is reserved for developers  and  experienced
+* professionals having in-depth computer knowledge. Users are therefore
+* encouraged to load and test the software's suitability as regards their
+* requirements in conditions enabling the security of their systems and/or
+* data to be ensured and,  more generally, to use and operate it in the
+* same conditions as regards security.
+*
+* The fact that you are presently reading this means that you have had
+* knowledge of the CeCILL license and that you accept its terms.
+**/
+
+/**
+ * This file tests the strategy scheduling.
+ **/
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/GoelandProver/Goeland/Search"
+)
+
+// The schedule launches os.Args[0], i.e., this test binary, which then runs
+// Goéland instead of the tests.
+func TestMain(m *testing.M) {
+	if os.Getenv("GOELAND_RUN_MAIN") != "" {
+		main()
+		os.Exit(0)
+	}
+	buildOptions()
+	os.Exit(m.Run())
+}
+
+// Runs Goéland with the schedule on the problem, and returns its output and
+// exit code.
+func runGoeland(t *testing.T, schedule string, args ...string) (string, int) {
+	dir := t.TempDir()
+	scheduleFile, problem := filepath.Join(dir, "test.schedule"), filepath.Join(dir, "problem.p")
+	// The theorem needs three instances of a2, more than a first step gives.
+	content := "fof(a1, axiom, p(a)).\nfof(a2, axiom, ! [X] : (p(X) => p(f(X)))).\nfof(c, conjecture, p(f(f(f(a))))).\n"
+	if err := os.WriteFile(problem, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(scheduleFile, []byte(schedule), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args = append(append([]string{"-schedule", scheduleFile}, args...), problem)
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "GOELAND_RUN_MAIN=1")
+	output, err := cmd.CombinedOutput()
+
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		return string(output), exitError.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return string(output), 0
+}
+
+func expectOutput(t *testing.T, output string, expected ...string) {
+	t.Helper()
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Fatalf("Error: the output does not contain %q:\n%s", line, output)
+		}
+	}
+}
+
+func TestScheduleSequential(t *testing.T) {
+	schedule := "% The first option set gives up.\n5 -one_step\n\n5\n5 -one_step\n"
+	output, code := runGoeland(t, schedule)
+	if code != Search.ExitSolved {
+		t.Fatalf("Error: unexpected exit code %d:\n%s", code, output)
+	}
+	expectOutput(t, output,
+		"(3 option sets, sequential)",
+		"% Schedule entry 1/3 [5s] -one_step: GaveUp",
+		"% Schedule entry 2/3 [5s] (default options): Theorem",
+		"% Schedule: solved by option set 2",
+		"% SZS status Theorem",
+	)
+
+	// The schedule stops at the first result.
+	if strings.Contains(output, "Schedule entry 3/3") {
+		t.Fatalf("Error: the third option set has been run:\n%s", output)
+	}
+}
+
+func TestScheduleParallel(t *testing.T) {
+	output, code := runGoeland(t, "5 -one_step\n5\n", "-schedule_parallel", "-core_limit", "2")
+	if code != Search.ExitSolved {
+		t.Fatalf("Error: unexpected exit code %d:\n%s", code, output)
+	}
+	expectOutput(t, output, "(2 option sets, parallel)", "% Schedule: solved by option set 2", "% SZS status Theorem")
+}
+
+func TestScheduleGaveUp(t *testing.T) {
+	output, code := runGoeland(t, "5 -one_step\n5 -one_step -completeness\n")
+	if code != Search.ExitGaveUp {
+		t.Fatalf("Error: unexpected exit code %d:\n%s", code, output)
+	}
+	expectOutput(t, output, "% Schedule entry 2/2 [5s] -one_step -completeness: GaveUp", "% SZS status GaveUp")
+}
+
+func TestScheduleErrors(t *testing.T) {
+	for _, schedule := range []string{"", "-one_step\n", "5 -unknown\n", "5 -timeout 3\n"} {
+		if output, code := runGoeland(t, schedule); code == Search.ExitSolved {
+			t.Fatalf("Error: the schedule %q has not been rejected:\n%s", schedule, output)
+		}
+	}
+}
+
+func TestScheduleForwardedArgs(t *testing.T) {
+	defer func(args []string) { os.Args = args }(os.Args)
+
+	tests := []struct {
+		args, expected []string
+	}{
+		{[]string{"-schedule", "s", "-dmt", "-l", "5"}, []string{"-dmt", "-l", "5"}},
+		{[]string{"-schedule=s", "-timeout", "10", "-dmt"}, []string{"-dmt"}},
+		// The value of -log is not the -schedule option.
+		{[]string{"-log", "schedule", "-dmt", "-schedule", "s"}, []string{"-log", "schedule", "-dmt"}},
+		// -schedule_parallel takes no value, and -timeout is not followed by one.
+		{[]string{"-schedule_parallel", "-dmt", "-timeout", "-inner"}, []string{"-dmt", "-inner"}},
+		{[]string{"-timeout", "-1", "--schedule", "s", "-inner"}, []string{"-inner"}},
+	}
+	for _, test := range tests {
+		os.Args = append(append([]string{"goeland"}, test.args...), "problem.p")
+		if args := forwardedArgs(); !slices.Equal(args, test.expected) {
+			t.Errorf("Error: expected the arguments %v to be forwarded from %v, got %v.", test.expected, test.args, args)
+		}
+	}
+}